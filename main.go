@@ -1,12 +1,24 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -17,10 +29,65 @@ var (
 	screenHeight = int32(800)
 )
 
-const (
-	worldWidth  = 2400
-	worldHeight = 1600
-)
+// WorldSize is a map size offered for a match
+type WorldSize struct {
+	Name          string
+	Width, Height float32
+}
+
+// worldSizes are the map sizes the player or host can pick; defaultWorldSize
+// is the original map, which the object counts in generateObjects are tuned for
+var worldSizes = []WorldSize{
+	{Name: "Small", Width: 1800, Height: 1200},
+	{Name: "Medium", Width: 2400, Height: 1600},
+	{Name: "Large", Width: 3600, Height: 2400},
+}
+
+const defaultWorldSize = 1
+
+// ObjectDensity scales how many objects are spawned for a given area
+type ObjectDensity struct {
+	Name   string
+	Factor float32
+}
+
+var objectDensities = []ObjectDensity{
+	{Name: "Sparse", Factor: 0.5},
+	{Name: "Normal", Factor: 1.0},
+	{Name: "Dense", Factor: 1.6},
+}
+
+const defaultObjectDensity = 1
+
+// matchDurations are the match lengths in seconds the host can pick in the
+// lobby; defaultMatchDuration is the original 2 minutes
+var matchDurations = []float32{60, 120, 300, 600}
+
+const defaultMatchDuration = 1
+
+// validMatchDuration reports whether seconds is one of the offered match
+// lengths, so a malformed start message can't set an absurd timer
+func validMatchDuration(seconds float32) bool {
+	for _, d := range matchDurations {
+		if seconds == d {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFrameTime caps the delta passed to update. After a stall (window drag,
+// hitch, breakpoint) GetFrameTime can report seconds, which would teleport the
+// hole, fling particles and eat a chunk of the match timer in one step.
+const maxFrameTime = 0.1
+
+// clampFrameTime limits a frame delta to maxFrameTime
+func clampFrameTime(deltaTime float32) float32 {
+	if deltaTime > maxFrameTime {
+		return maxFrameTime
+	}
+	return deltaTime
+}
 
 type Vector2 struct {
 	X, Y float32
@@ -34,6 +101,155 @@ type GameObject struct {
 	Value    int
 	Active   bool
 	Rotation float32
+	Velocity Vector2 // Only non-zero while being pulled in physics map modes
+	Wander   Vector2 // Walking velocity of wandering NPCs; zero for static objects
+	PowerUp  PowerUp // Effect granted when eaten; PowerUpNone for ordinary objects
+}
+
+// Catch is an object a hole ate, kept to show off the biggest one of a match
+type Catch struct {
+	Type string  `json:"type"`
+	Size float32 `json:"size"`
+}
+
+// maxCatchTypeLength bounds the object type a remote player may report
+const maxCatchTypeLength = 16
+
+// String describes the catch as "<type> (<size>)"
+func (c Catch) String() string {
+	if c.Type == "" {
+		return "nothing"
+	}
+	return fmt.Sprintf("%s (%.1f)", c.Type, c.Size)
+}
+
+// PowerUp is a timed effect granted by eating a pickup object
+type PowerUp int
+
+const (
+	PowerUpNone PowerUp = iota
+	PowerUpSpeed
+	PowerUpMagnet
+	PowerUpGrowth
+	powerUpCount
+)
+
+// valid reports whether p is an effect a pickup can grant
+func (p PowerUp) valid() bool {
+	return p > PowerUpNone && p < powerUpCount
+}
+
+func (p PowerUp) String() string {
+	switch p {
+	case PowerUpSpeed:
+		return "Speed"
+	case PowerUpMagnet:
+		return "Magnet"
+	case PowerUpGrowth:
+		return "Growth"
+	default:
+		return "None"
+	}
+}
+
+// Color is used for the pickup, its HUD icon and the glow around a powered hole
+func (p PowerUp) Color() rl.Color {
+	switch p {
+	case PowerUpSpeed:
+		return rl.Color{R: 0, G: 220, B: 255, A: 255} // Cyan
+	case PowerUpMagnet:
+		return rl.Color{R: 255, G: 60, B: 200, A: 255} // Magenta
+	default:
+		return rl.Color{R: 120, G: 255, B: 80, A: 255} // Lime
+	}
+}
+
+const (
+	powerUpPickups     = 12   // Pickups scattered over each map
+	powerUpSize        = 8.0  // Small enough for a fresh hole to eat
+	powerUpDuration    = 8.0  // Seconds each effect lasts
+	powerUpSpeedFactor = 1.6  // Speed multiplier while boosted
+	powerUpGrowthBonus = 15.0 // Temporary size added by PowerUpGrowth
+	magnetRadius       = 200  // Reach beyond the hole's edge
+	magnetSpeed        = 150  // World units per second objects are dragged in
+)
+
+// PowerUpPickup tells other players to show a glow around the sender's hole
+type PowerUpPickup struct {
+	Kind     PowerUp `json:"kind"`
+	Duration float32 `json:"duration"`
+}
+
+// People ("small" objects) stroll around and run from a hole that can eat them
+const (
+	npcWalkSpeed  = 20.0  // World units per second
+	npcFleeSpeed  = 70.0  // World units per second
+	npcFleeRadius = 150.0 // Distance beyond the hole's edge that sends them running
+	npcTurnChance = 0.3   // Chance per second of picking a new heading
+)
+
+// walkVelocity returns a walking velocity heading at angle degrees
+func walkVelocity(angle float32) Vector2 {
+	rad := float64(angle) * math.Pi / 180
+	return Vector2{X: float32(math.Cos(rad)) * npcWalkSpeed, Y: float32(math.Sin(rad)) * npcWalkSpeed}
+}
+
+// mass is how strongly an object resists being pulled; bigger objects are heavier
+func (o *GameObject) mass() float32 {
+	if o.Size < 1 {
+		return 1
+	}
+	return o.Size
+}
+
+// PhysicsMode selects how objects react to a nearby hole
+type PhysicsMode int
+
+const (
+	PhysicsClassic  PhysicsMode = iota // Objects drop the instant the hole covers their center
+	PhysicsSlippery                    // Strong pull, little drag - objects slide into the hole
+	PhysicsHeavy                       // Weak pull, lots of drag - objects creep in
+	physicsModeCount
+)
+
+func (m PhysicsMode) String() string {
+	switch m {
+	case PhysicsSlippery:
+		return "Slippery"
+	case PhysicsHeavy:
+		return "Heavy"
+	default:
+		return "Classic"
+	}
+}
+
+// params returns the pull strength multiplier and velocity drag per second for a mode
+func (m PhysicsMode) params() (pull float32, drag float32) {
+	switch m {
+	case PhysicsSlippery:
+		return 1.0, 1.0
+	case PhysicsHeavy:
+		return 0.4, 4.0
+	default:
+		return 0, 0
+	}
+}
+
+const (
+	attractionRadiusFactor = 2.5      // Pull reaches this many hole radii out
+	attractionStrength     = 600.0    // Base acceleration at the hole's edge
+	attractionMaxSpeed     = 600.0    // Fastest an object is dragged, three times a hole's base speed
+	attractionRestSpeed    = 1.0      // Drifting objects slower than this come to rest
+	attractionStep         = 1.0 / 60 // Seconds per step the pull is integrated at
+)
+
+// attractionFalloff scales the pull by proximity: 0 at the edge of the radius, 1 at the center
+func attractionFalloff(distance, radius float32) float32 {
+	if radius <= 0 || distance >= radius {
+		return 0
+	}
+	t := 1 - distance/radius
+	return t * t
 }
 
 type Hole struct {
@@ -44,6 +260,22 @@ type Hole struct {
 	Animation float32
 }
 
+// Bot is an AI-controlled hole used as a single-player opponent
+type Bot struct {
+	Hole   Hole
+	Name   string
+	Color  rl.Color
+	Wander Vector2 // Roaming destination while nothing edible is in reach
+	Eaten  int     // Objects eaten this match
+}
+
+// Default single-player bot settings
+const (
+	defaultBotCount           = 3
+	defaultBotSpeedMultiplier = 0.8
+	defaultBotReactionRadius  = 300.0
+)
+
 type Particle struct {
 	Position Vector2
 	Velocity Vector2
@@ -53,14 +285,356 @@ type Particle struct {
 	Size     float32
 }
 
+// ScorePopup is a floating "+N" label shown where an object was consumed
+type ScorePopup struct {
+	Position Vector2
+	Value    int
+	Life     float32
+	MaxLife  float32
+}
+
+const (
+	maxScorePopups    = 24   // Oldest popups are dropped beyond this
+	scorePopupLife    = 0.9  // Seconds a popup stays on screen
+	scorePopupRise    = 40.0 // Upward drift in world units per second
+	scorePopupMinFont = 14
+	scorePopupMaxFont = 48
+)
+
+// soundDir holds the consume sounds, named consume_<category>.wav
+const soundDir = "assets/sounds"
+
+// defaultConsumeSound is played for categories whose own sound is missing
+const defaultConsumeSound = "default"
+
+// SoundBank lazily loads consume sounds per object category. Missing or broken
+// files are remembered so the game stays silent instead of retrying every eat.
+type SoundBank struct {
+	sounds map[string]rl.Sound
+	failed map[string]bool
+}
+
+func NewSoundBank() *SoundBank {
+	return &SoundBank{
+		sounds: make(map[string]rl.Sound),
+		failed: make(map[string]bool),
+	}
+}
+
+// consumeSoundCategory maps an object type to the sound category it plays
+func consumeSoundCategory(objType string) string {
+	switch objType {
+	case "tiny":
+		return "tink"
+	case "small", "medium-small":
+		return "pop"
+	case "medium", "medium-large":
+		return "thud"
+	case "large", "extra-large", "huge", "massive":
+		return "crunch"
+	default:
+		return defaultConsumeSound
+	}
+}
+
+// load returns the sound for a category, loading it on first use
+func (b *SoundBank) load(category string) (rl.Sound, bool) {
+	if sound, ok := b.sounds[category]; ok {
+		return sound, true
+	}
+	if b.failed[category] || !rl.IsAudioDeviceReady() {
+		return rl.Sound{}, false
+	}
+
+	path := filepath.Join(soundDir, "consume_"+category+".wav")
+	if _, err := os.Stat(path); err != nil {
+		b.failed[category] = true
+		return rl.Sound{}, false
+	}
+
+	sound := rl.LoadSound(path)
+	if !rl.IsSoundReady(sound) {
+		fmt.Printf("Failed to load sound: %s\n", path)
+		b.failed[category] = true
+		return rl.Sound{}, false
+	}
+
+	b.sounds[category] = sound
+	return sound, true
+}
+
+// soundFor picks the consume sound for an object type, falling back to the default
+func (b *SoundBank) soundFor(objType string) (rl.Sound, bool) {
+	if sound, ok := b.load(consumeSoundCategory(objType)); ok {
+		return sound, true
+	}
+	return b.load(defaultConsumeSound)
+}
+
+// PlayConsume plays the sound matching the eaten object's type
+func (b *SoundBank) PlayConsume(objType string) {
+	if b == nil {
+		return
+	}
+	if sound, ok := b.soundFor(objType); ok {
+		rl.PlaySound(sound)
+	}
+}
+
+// Unload frees every loaded sound
+func (b *SoundBank) Unload() {
+	if b == nil {
+		return
+	}
+	for category, sound := range b.sounds {
+		rl.UnloadSound(sound)
+		delete(b.sounds, category)
+	}
+}
+
+// textureDir holds the object sprites, named <sprite>.png
+const textureDir = "assets/textures"
+
+// objectSprites maps object types to the sprite drawn for them. Types without
+// a sprite, or whose sprite failed to load, keep their shape rendering.
+var objectSprites = map[string]string{
+	"small":        "person",
+	"medium":       "car",
+	"medium-large": "tree",
+	"large":        "building",
+	"extra-large":  "building",
+	"huge":         "building",
+	"massive":      "building",
+}
+
+// loadAssets loads the object sprites. It needs a GPU context, so call it after InitWindow.
+func (g *Game) loadAssets() {
+	g.Textures = make(map[string]rl.Texture2D)
+	for _, sprite := range objectSprites {
+		if _, loaded := g.Textures[sprite]; loaded {
+			continue
+		}
+		path := filepath.Join(textureDir, sprite+".png")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		texture := rl.LoadTexture(path)
+		if !rl.IsTextureReady(texture) {
+			fmt.Printf("Failed to load texture: %s\n", path)
+			continue
+		}
+		g.Textures[sprite] = texture
+	}
+}
+
+// unloadAssets frees every loaded texture
+func (g *Game) unloadAssets() {
+	for sprite, texture := range g.Textures {
+		rl.UnloadTexture(texture)
+		delete(g.Textures, sprite)
+	}
+}
+
+// objectTexture returns the loaded sprite for an object type, if any
+func (g *Game) objectTexture(objType string) (rl.Texture2D, bool) {
+	texture, ok := g.Textures[objectSprites[objType]]
+	return texture, ok
+}
+
 type NetworkPlayer struct {
 	ID       int
 	Hole     Hole
 	Name     string
 	Color    rl.Color
 	LastSeen time.Time
+	EatenAt  time.Time     // When we last swallowed this hole; guards against double kills
+	RTT      time.Duration // Last measured round trip to this player; zero until known
+	lastSeq  uint64        // Seq of the newest player_update applied
+
+	BiggestCatch Catch // Largest object this player has eaten this match
+	ObjectsEaten int
+
+	// Latest power-up this player picked up, shown as a glow until it ends
+	PowerUp      PowerUp
+	PowerUpUntil time.Time
+
+	// The two most recent positions from player_update, for smoothing
+	PrevPosition Vector2
+	PrevUpdate   time.Time
+	LastUpdate   time.Time
+}
+
+const (
+	maxExtrapolation = 0.5   // How far past the latest update to predict, in update intervals
+	maxInterpolJump  = 300.0 // Moves longer than this (respawns) snap instead of sliding
+)
+
+// renderPosition smooths a remote hole's movement between network updates.
+// It slides from the previous update toward the latest over one update
+// interval, then keeps going a short way if the next update is late.
+func (p *NetworkPlayer) renderPosition(now time.Time, width, height float32) Vector2 {
+	current := p.Hole.Position
+	interval := p.LastUpdate.Sub(p.PrevUpdate)
+	if p.PrevUpdate.IsZero() || interval <= 0 || distanceBetween(p.PrevPosition, current) > maxInterpolJump {
+		return current
+	}
+
+	alpha := float32(now.Sub(p.LastUpdate).Seconds() / interval.Seconds())
+	if alpha < 0 {
+		alpha = 0
+	}
+	// A stalled connection stops after a short prediction instead of flinging the hole away
+	if alpha > 1+maxExtrapolation {
+		alpha = 1 + maxExtrapolation
+	}
+
+	pos := Vector2{
+		X: p.PrevPosition.X + (current.X-p.PrevPosition.X)*alpha,
+		Y: p.PrevPosition.Y + (current.Y-p.PrevPosition.Y)*alpha,
+	}
+	smoothed := Hole{Position: pos, Size: p.Hole.Size}
+	clampToBounds(&smoothed, width, height)
+	return smoothed.Position
+}
+
+// PlayerEaten reports that the sender swallowed another player's hole
+type PlayerEaten struct {
+	VictimID int     `json:"victim_id"`
+	Score    int     `json:"score"` // Points transferred from the victim to the eater
+	Size     float32 `json:"size"`
+}
+
+// killClaim is a client's report that it swallowed another player, held for
+// the host to check against its own view
+type killClaim struct {
+	EaterID int
+	PlayerEaten
+}
+
+// GameStart schedules the synchronized start of a multiplayer match. Both
+// times are Unix milliseconds on the host's clock; clients only use their
+// difference, so clock skew between machines doesn't matter.
+type GameStart struct {
+	StartAt     int64   `json:"start_at"`
+	SentAt      int64   `json:"sent_at"`
+	MaxGameTime float32 `json:"max_game_time"` // Match length in seconds
+}
+
+// Ping carries the sender's clock in Unix nanoseconds; the pong echoes it back
+// untouched so the sender can time the round trip on its own clock.
+type Ping struct {
+	SentAt int64 `json:"sent_at"`
+}
+
+// matchCountdown is the 3-2-1 lead-in before a multiplayer match starts
+const matchCountdown = 3 * time.Second
+
+// ObjectEaten names an object and the player eating it. Clients send it as an
+// eat_request; the host answers with object_eaten to everyone, or eat_denied
+// to the requester alone.
+type ObjectEaten struct {
+	Index   int `json:"index"`
+	EaterID int `json:"eater_id"`
+}
+
+// WorldState tells a client that joined mid-match which objects of the field
+// built from WorldSeed are still there
+type WorldState struct {
+	WorldSeed int64  `json:"world_seed,string"`
+	Count     int    `json:"count"`  // Objects in the field, to catch a different layout
+	Active    []byte `json:"active"` // Bit i is set while object i is on the field
+}
+
+// newWorldState packs which objects are active into a bitset, which stays
+// small for fields of 800+ objects where a JSON array of bools wouldn't
+func newWorldState(seed int64, objects []GameObject) WorldState {
+	state := WorldState{WorldSeed: seed, Count: len(objects), Active: make([]byte, (len(objects)+7)/8)}
+	for i := range objects {
+		if objects[i].Active {
+			state.Active[i/8] |= 1 << (i % 8)
+		}
+	}
+	return state
+}
+
+// active reports whether object i is still on the field
+func (s WorldState) active(i int) bool {
+	return i/8 < len(s.Active) && s.Active[i/8]&(1<<(i%8)) != 0
+}
+
+// RoundReset sends every peer from the standings back to the lobby together,
+// on a fresh object field
+type RoundReset struct {
+	WorldSeed int64 `json:"world_seed,string"`
+}
+
+// roundStandingsTime is how long final standings stay up before the host
+// starts the next multiplayer round
+const roundStandingsTime = 8 * time.Second
+
+// SuddenDeath sends a tied timed multiplayer match into overtime: the first of
+// Players to eat an object wins. Duration caps overtime, in milliseconds.
+type SuddenDeath struct {
+	Players  []int `json:"players"`
+	Duration int64 `json:"duration"`
+}
+
+// SuddenDeathOver ends overtime. WinnerID is 0 when time ran out with nobody
+// eating, and the standings stand as they are.
+type SuddenDeathOver struct {
+	WinnerID int `json:"winner_id"`
+}
+
+const (
+	suddenDeathEpsilon = 0.5 // Holes whose sizes are closer than this are tied
+	suddenDeathTime    = 30 * time.Second
+)
+
+// pendingEat is an eat a client has shown on screen but the host hasn't ruled on
+type pendingEat struct {
+	Value int
+	At    time.Time
+}
+
+const (
+	eatVerdictTimeout = 2 * time.Second // Restore predicted eats the host never answered
+	eatRequestSlack   = 60.0            // Extra reach the host allows for a client's stale position
+)
+
+const (
+	holeEatRatio        = 1.2  // A hole must be this much bigger than another to swallow it
+	holeAbsorbFraction  = 0.25 // Share of the victim's score and size the eater absorbs
+	holeRespawnSize     = 20.0
+	holeRespawnImmunity = 3 * time.Second
+)
+
+// GameMode decides how a match ends
+type GameMode int
+
+const (
+	ModeTimed       GameMode = iota // Biggest hole when the clock runs out wins
+	ModeSurvival                    // Lasts until the player goes too long without eating
+	ModeTargetScore                 // First hole to TargetScore wins
+	gameModeCount
+)
+
+func (m GameMode) String() string {
+	switch m {
+	case ModeSurvival:
+		return "Survival"
+	case ModeTargetScore:
+		return "Target Score"
+	default:
+		return "Timed"
+	}
 }
 
+const (
+	survivalStarveTime = 15.0 // Seconds without eating before a survival match ends
+	defaultTargetScore = 500
+)
+
 type GameState int
 
 const (
@@ -71,8 +645,15 @@ const (
 	StateLobby
 	StateGameplay
 	StateGameOver
+	StatePaused
+	StateSettings
+	StateControls
+	StateReplay
 )
 
+// NetworkMessage wraps every message on the wire. Data arrives as generic JSON
+// before it is decoded into its own type, so world seeds are sent as strings:
+// an int64 UnixNano seed doesn't survive the trip through float64.
 type NetworkMessage struct {
 	Type     string      `json:"type"`
 	PlayerID int         `json:"player_id"`
@@ -80,10 +661,32 @@ type NetworkMessage struct {
 }
 
 type LobbyUpdate struct {
-	PlayerCount int    `json:"player_count"`
-	GameStarted bool   `json:"game_started"`
-	HostReady   bool   `json:"host_ready"`
-	ServerIP    string `json:"server_ip,omitempty"`
+	PlayerCount int         `json:"player_count"`
+	GameStarted bool        `json:"game_started"`
+	HostReady   bool        `json:"host_ready"`
+	ServerIP    string      `json:"server_ip,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	WorldSeed   int64       `json:"world_seed,string,omitempty"` // Host only: seed every client builds the field from
+	Mode        GameMode    `json:"mode"`                        // Host only, like WorldSeed
+	TargetScore int         `json:"target_score,omitempty"`
+	Pace        int         `json:"pace"`
+	Physics     PhysicsMode `json:"physics"`
+	WorldSize   int         `json:"world_size"`
+	Density     int         `json:"density"`
+	Duration    int         `json:"duration"` // Index into matchDurations
+	Transport   Transport   `json:"transport"`
+	Password    string      `json:"password,omitempty"` // Client only: room password for the host to check
+}
+
+// JoinRejected tells a client the host turned it away before closing the connection
+type JoinRejected struct {
+	Reason string `json:"reason"`
+}
+
+// ChatMessage is one line of lobby chat
+type ChatMessage struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
 }
 
 type PlayerUpdate struct {
@@ -91,6 +694,103 @@ type PlayerUpdate struct {
 	Size      float32 `json:"size"`
 	Score     int     `json:"score"`
 	Animation float32 `json:"animation"`
+	Name      string  `json:"name,omitempty"`
+	Seq       uint64  `json:"seq,omitempty"` // Counts up with every update the sender sends
+	Catch     Catch   `json:"catch"`         // Biggest object the sender has eaten this match
+	Eaten     int     `json:"eaten"`         // Objects the sender has eaten this match
+}
+
+// Transport picks how player positions travel between peers
+type Transport int
+
+const (
+	TransportTCP    Transport = iota // Everything over the lobby's TCP connection
+	TransportHybrid                  // Positions over UDP, everything else over TCP
+	transportCount
+)
+
+func (t Transport) String() string {
+	switch t {
+	case TransportHybrid:
+		return "UDP positions + TCP"
+	default:
+		return "TCP only"
+	}
+}
+
+// udpUpdate is a player_update as sent in a single UDP datagram
+type udpUpdate struct {
+	Type     string       `json:"type"`
+	PlayerID int          `json:"player_id"`
+	Data     PlayerUpdate `json:"data"`
+}
+
+// maxDatagramSize bounds one UDP datagram; a player update is a few hundred bytes
+const maxDatagramSize = 2048
+
+// Limits on what a remote player may claim in a PlayerUpdate
+const (
+	maxRemoteHoleSize = 400.0 // Far beyond what normal growth reaches
+	remoteBoundsSlack = 100.0 // How far outside the world a position may drift
+)
+
+// sanitizePlayerUpdate rejects updates no honest client could send in a world of
+// the given size and clamps the size, so one misbehaving peer can't take over
+// the shared game
+func sanitizePlayerUpdate(u *PlayerUpdate, width, height float32) error {
+	finite := func(v float32) bool {
+		return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+	}
+	if !finite(u.Position.X) || !finite(u.Position.Y) || !finite(u.Size) || !finite(u.Animation) {
+		return fmt.Errorf("non-finite value")
+	}
+	if u.Position.X < -remoteBoundsSlack || u.Position.X > width+remoteBoundsSlack ||
+		u.Position.Y < -remoteBoundsSlack || u.Position.Y > height+remoteBoundsSlack {
+		return fmt.Errorf("position (%.0f, %.0f) outside the world", u.Position.X, u.Position.Y)
+	}
+	if u.Size <= 0 {
+		return fmt.Errorf("size %.1f", u.Size)
+	}
+	if u.Size > maxRemoteHoleSize {
+		u.Size = maxRemoteHoleSize
+	}
+	if u.Score < 0 {
+		u.Score = 0
+	}
+	if u.Eaten < 0 {
+		u.Eaten = 0
+	}
+	if !finite(u.Catch.Size) || u.Catch.Size < 0 {
+		u.Catch = Catch{}
+	}
+	if runes := []rune(u.Catch.Type); len(runes) > maxCatchTypeLength {
+		u.Catch.Type = string(runes[:maxCatchTypeLength])
+	}
+	return nil
+}
+
+// InputTarget selects what the menu text box is editing
+type InputTarget int
+
+const (
+	InputServerIP InputTarget = iota
+	InputPlayerName
+	InputHostPassword // Room password chosen before hosting; blank keeps the lobby open
+	InputJoinPassword // Room password asked for after the server IP
+)
+
+const maxPlayerNameLength = 16
+
+// playerDisplayName trims and truncates a chosen name, falling back to "Player <id>"
+func playerDisplayName(name string, id int) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Sprintf("Player %d", id)
+	}
+	if runes := []rune(name); len(runes) > maxPlayerNameLength {
+		name = string(runes[:maxPlayerNameLength])
+	}
+	return name
 }
 
 type Game struct {
@@ -99,526 +799,4226 @@ type Game struct {
 	NetworkPlayers  map[int]*NetworkPlayer
 	Objects         []GameObject
 	Particles       []Particle
+	ScorePopups     []ScorePopup
+	Physics         PhysicsMode
 	Camera          rl.Camera2D
 	GameTime        float32
 	MaxGameTime     float32
 	BaseZoom        float32
+	zoomOffset      float32 // Scroll-wheel zoom on top of the automatic zoom, in powers of two
+	zoomIdle        float32 // Seconds since the wheel last moved
 	MenuSelection   int
+	hasSave         bool // A saved single-player match is waiting on disk
 	IsHost          bool
-	ServerConn      net.Conn
+	ServerConn      net.Conn     // Client: connection to the host; guarded by netMu
+	dialedConn      net.Conn     // Connection connectToServer opened, for the main loop to join; guarded by netMu
+	listener        net.Listener // Host: accepts client connections; guarded by netMu
 	ClientConns     []net.Conn
+	clientByID      map[int]net.Conn // Admitted clients by player ID; guarded by netMu
+	pendingJoins    int              // Host: accepted connections holding a lobby slot before their first message; guarded by netMu
 	PlayerID        int
 	ServerIP        string
 	InputText       string
 	InputActive     bool
+	InputTarget     InputTarget
+	PlayerName      string
 	LobbyReady      bool
 	MinPlayers      int
+	MaxPlayers      int
+	KickSelection   int // Host: 1-based lobby row picked for kicking, 0 for none
 	LocalIP         string
 	GameStarted     bool
+	Autopilot       bool    // Hole steers itself instead of reading input (attract mode)
+	menuIdleTime    float32 // Seconds the menu has gone without input
+	attractGame     *Game   // Self-playing demo match shown behind an idle menu
+	Sounds          *SoundBank
+	chatLog         []ChatMessage // Recent lobby chat, newest last; guarded by netMu
+	chatInput       string        // Line being typed while ChatActive
+	ChatActive      bool          // Lobby chat box has the keyboard
+	Textures        map[string]rl.Texture2D
+	netMu           sync.RWMutex  // Guards NetworkPlayers and ClientConns across network goroutines
+	heartbeatStop   chan struct{} // Closed to end the heartbeat goroutine; guarded by netMu
+	heartbeatActive atomic.Bool   // Set by the main loop while heartbeats should be sent
+	reconnecting    atomic.Bool   // Set while redialing a host that dropped us
+	hostRTT         atomic.Int64  // Last measured round trip to the host, in nanoseconds
+	ReconnectTries  int           // Redials before a dropped client gives up
+	ReconnectDelay  time.Duration // Wait before the first redial; doubles after each failure
+	PauseSelection  int
+	ShowDebug       bool
+	RoomPassword    string  // Host: required to join. Client: sent when joining
+	MenuMessage     string  // Error shown on the main menu, e.g. a rejected join
+	shakeTime       float32 // Seconds of camera shake left
+	shakeMagnitude  float32 // Peak shake offset in pixels
+	Mode            GameMode
+	Pace            int     // Index into growthPaces, chosen by the host
+	WorldSize       int     // Index into worldSizes, chosen by the host
+	Density         int     // Index into objectDensities, chosen by the host
+	MatchDuration   int     // Index into matchDurations, chosen by the host
+	WorldWidth      float32 // Dimensions of the current map, set from WorldSize
+	WorldHeight     float32
+	TargetScore     int     // Score that ends a ModeTargetScore match
+	lastMealTime    float32 // GameTime of the player's last meal, for survival mode
+	biggestCatch    Catch   // Largest object the player has eaten this match
+	objectsEaten    int     // Objects the player has eaten this match
+	rankByEaten     bool    // Game-over standings ranked by objects eaten instead of the mode's metric
+	ShowMinimap     bool
+	Settings        Settings
+	SettingsChoice  int
+	Bindings        map[Action]int32 // Key for each rebindable action
+	ControlsMessage string           // Rebinding warning shown on the controls screen
+	rebinding       bool             // Waiting for a key to bind to ControlsChoice
+	ControlsChoice  int
+	stickHeldX      int // Stick direction already turned into a menu step
+	stickHeldY      int
+	Quit            bool // Set from the main menu to close the game
+	practice        bool // Untimed single-player session from the Practice menu entry
+	Bots            []Bot
+	BotCount        int     // Bots spawned for single player
+	BotSpeed        float32 // Bot speed as a multiple of the player's base speed
+	BotReaction     float32 // How far away a bot notices food
+	RespawnEnabled  bool    // Bring consumed objects back; off for a fixed-content match
+	RespawnRate     float32 // Objects respawned per second
+	respawnBudget   float32 // Fractional respawns carried between frames
+	WeightPenalty   float32 // Speed fraction lost right after eating something heavy
+	WeightRecovery  float32 // Seconds to win the lost speed back
+	slowdown        float32 // Speed fraction currently lost to a heavy meal
+	BorderBounce    float32 // Share of the player's speed reflected off the world edge; 0 for a hard stop
+	EdgeGlow        bool    // Warn with a red glow as the player nears the world edge
+	bounce          Vector2 // Push away from the world edge left over from the last bounce
+	nearMiss        int     // Index of the object the hole is touching but too small to eat
+	nearMissGlow    float32 // Strength of the near-miss outline, 0 when hidden
+	WorldSeed       int64   // Seed the current object field was generated from
+	hostSeed        int64   // Latest seed announced by the host; guarded by netMu
+	hostMode        GameMode
+	hostTargetScore int
+	hostPace        int
+	hostPhysics     PhysicsMode
+	hostWorldSize   int
+	hostDensity     int
+	hostDuration    int
+	matchStartAt    time.Time // Pending synchronized start; guarded by netMu
+	netWorldSize    Vector2   // Copy of WorldWidth and WorldHeight for checking updates; guarded by netMu
+	startPending    bool      // Host started the match while we wait in the lobby; guarded by netMu
+	hostStartAt     time.Time // When the host's current match started; guarded by netMu
+	hostMatchTime   float32   // Length of the host's current match in seconds; guarded by netMu
+	lastSendTime    time.Time // Grid point of the last player update sent
+	dropReason      string    // Why the host turned us away or was lost; guarded by netMu
+	hostGone        bool      // Host shut down or couldn't be redialed; guarded by netMu
+	hostGoneAt      time.Time // When the host-disconnected notice went up
+	roundOverAt     time.Time // When this match's standings went up
+	roundReset      bool      // Host announced the next round; guarded by netMu
+	objectGrid      *SpatialGrid
+	gridQuery       []int   // Scratch buffer reused by nearbyObjects
+	drifting        []int   // Objects the pull has set moving, by index
+	attractors      []Hole  // Scratch buffer of the holes pulling this frame
+	attractionTime  float32 // Time not yet stepped by applyObjectAttraction
+
+	// The host rules on every object eaten in multiplayer
+	remoteEaten []ObjectEaten      // Eats confirmed by the host; guarded by netMu
+	eatRequests []ObjectEaten      // Host: client eats awaiting validation; guarded by netMu
+	eatDenied   []int              // Client: predicted eats the host refused; guarded by netMu
+	pendingEats map[int]pendingEat // Client: predicted eats by object index
+	lateJoiners []int              // Host: clients owed a world_state; guarded by netMu
+	joinState   *WorldState        // Client: world_state waiting to be applied; guarded by netMu
+	swallowed   []int              // Points lost each time another player ate us, for the main loop; guarded by netMu
+	killClaims  []killClaim        // Host: client kills awaiting validation; guarded by netMu
+
+	// Active power-up effects
+	powerUpEnds [powerUpCount]float32 // GameTime each effect wears off; zero when inactive
+	growthBonus float32               // Size added by PowerUpGrowth, taken back when it ends
+
+	// Replays of the local player's runs
+	recording     *Replay // Match being recorded; nil outside a match
+	lastReplay    string  // File the last finished match was written to
+	playback      *Replay // Replay shown in StateReplay
+	playbackTime  float32 // Match time the playback has reached
+	playbackFrame int     // Index of the frame on screen
+	playbackEvent int     // Index of the next consumption event to show
+
+	// Optional UDP channel for player positions
+	Transport     Transport            // Chosen by the host
+	hostTransport Transport            // Latest transport announced by the host; guarded by netMu
+	udpConn       *net.UDPConn         // Host: socket clients send to. Client: socket to the host
+	udpPeers      map[int]*net.UDPAddr // Host: where each client's datagrams come from; guarded by netMu
+	updateSeq     uint64               // Seq of the last player update we sent
+
+	// Sudden-death overtime for tied multiplayer matches, called by the host
+	suddenDeath         bool             // Overtime in progress; the match can't end until the host says so
+	suddenDeathPlayed   bool             // Overtime already happened this match
+	suddenDeathIDs      []int            // Tied players who can win overtime
+	suddenDeathEnds     time.Time        // When overtime gives up without a winner
+	suddenDeathWinner   int              // Player who won overtime, 0 for none
+	hostSuddenDeath     *SuddenDeath     // Client: overtime called by the host; guarded by netMu
+	hostSuddenDeathOver *SuddenDeathOver // Client: end of overtime from the host; guarded by netMu
 }
 
-func getLocalIP() string {
-	addrs, err := net.InterfaceAddrs()
-	if err != nil {
-		return "127.0.0.1"
-	}
-
-	for _, addr := range addrs {
-		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
-			if ipNet.IP.To4() != nil {
-				ipStr := ipNet.IP.String()
-				// Prefer 192.168.x.x or 10.x.x.x ranges for LAN
-				if strings.HasPrefix(ipStr, "192.168.") || strings.HasPrefix(ipStr, "10.") {
-					return ipStr
-				}
-			}
-		}
-	}
-	return "127.0.0.1"
+// settingsFile is where user settings are persisted, relative to the working directory
+const settingsFile = "settings.json"
+
+// fpsOptions are the frame rate caps offered on the settings screen
+var fpsOptions = []int{30, 60, 120, 144}
+
+// Settings are the user preferences persisted between runs
+type Settings struct {
+	TargetFPS        int              `json:"target_fps"`
+	Fullscreen       bool             `json:"fullscreen"`
+	MasterVolume     int              `json:"master_volume"`     // 0-100
+	MouseSensitivity float32          `json:"mouse_sensitivity"` // 0 turns mouse steering off
+	InvertY          bool             `json:"invert_y"`
+	ShowCursor       bool             `json:"show_cursor"`            // Keep the cursor visible and steer toward it
+	CameraSmoothing  int              `json:"camera_smoothing"`       // 0 snaps to the player, higher trails more
+	KeyBindings      map[string]int32 `json:"key_bindings,omitempty"` // Action ID to raylib key code
 }
 
-func NewGame() *Game {
-	rand.Seed(time.Now().UnixNano())
-	localIP := getLocalIP()
-	return &Game{
-		State:          StateMenu,
-		NetworkPlayers: make(map[int]*NetworkPlayer),
-		MenuSelection:  0,
-		PlayerID:       rand.Intn(10000),
-		ServerIP:       localIP + ":8080",
-		LocalIP:        localIP,
-		MinPlayers:     2,
-		LobbyReady:     false,
-		GameStarted:    false,
+// Action is something the player can rebind to a key
+type Action int
+
+const (
+	ActionMoveUp Action = iota
+	ActionMoveDown
+	ActionMoveLeft
+	ActionMoveRight
+	ActionReady
+	ActionChat
+	ActionMinimap
+	ActionDebug
+	ActionPracticeReset
+	actionCount
+)
+
+// actionInfo names an action for the settings file and the controls screen
+type actionInfo struct {
+	ID      string
+	Label   string
+	Default int32
+}
+
+var actions = [actionCount]actionInfo{
+	ActionMoveUp:        {"move_up", "Move Up", rl.KeyW},
+	ActionMoveDown:      {"move_down", "Move Down", rl.KeyS},
+	ActionMoveLeft:      {"move_left", "Move Left", rl.KeyA},
+	ActionMoveRight:     {"move_right", "Move Right", rl.KeyD},
+	ActionReady:         {"ready", "Ready Up (lobby)", rl.KeySpace},
+	ActionChat:          {"chat", "Chat (lobby)", rl.KeyT},
+	ActionMinimap:       {"minimap", "Toggle Minimap", rl.KeyM},
+	ActionDebug:         {"debug", "Debug Overlay", rl.KeyF3},
+	ActionPracticeReset: {"practice_reset", "Reset Objects (practice)", rl.KeyR},
+}
+
+func defaultBindings() map[Action]int32 {
+	bindings := make(map[Action]int32, actionCount)
+	for action, info := range actions {
+		bindings[Action(action)] = info.Default
 	}
+	return bindings
 }
 
-func (g *Game) initSinglePlayer() {
-	g.State = StateSinglePlayer
-	g.Player = Hole{
-		Position:  Vector2{X: worldWidth / 2, Y: worldHeight / 2},
-		Size:      20.0,
-		Score:     0,
-		Speed:     200.0,
-		Animation: 0.0,
+// loadBindings builds Bindings from the saved settings; actions missing from
+// the file keep their default key
+func (g *Game) loadBindings() {
+	g.Bindings = defaultBindings()
+	for action, info := range actions {
+		if key, ok := g.Settings.KeyBindings[info.ID]; ok && key > 0 {
+			g.Bindings[Action(action)] = key
+		}
+	}
+}
+
+// bindKey binds key to action and saves it. A key already bound to another
+// action is swapped over, and the returned warning says so.
+func (g *Game) bindKey(action Action, key int32) string {
+	warning := ""
+	for other, bound := range g.Bindings {
+		if other != action && bound == key {
+			g.Bindings[other] = g.Bindings[action]
+			warning = fmt.Sprintf("%s was already bound to %s; swapped them", keyName(key), actions[other].Label)
+		}
+	}
+	g.Bindings[action] = key
+
+	g.Settings.KeyBindings = make(map[string]int32, actionCount)
+	for bound, boundKey := range g.Bindings {
+		g.Settings.KeyBindings[actions[bound].ID] = boundKey
+	}
+	g.saveSettings()
+	return warning
+}
+
+func (g *Game) actionDown(action Action) bool {
+	return rl.IsKeyDown(g.Bindings[action])
+}
+
+func (g *Game) actionPressed(action Action) bool {
+	return keyPressed(g.Bindings[action])
+}
+
+// keyName returns a short label for a raylib key code
+func keyName(key int32) string {
+	switch key {
+	case rl.KeySpace:
+		return "Space"
+	case rl.KeyEnter:
+		return "Enter"
+	case rl.KeyTab:
+		return "Tab"
+	case rl.KeyBackspace:
+		return "Backspace"
+	case rl.KeyUp:
+		return "Up"
+	case rl.KeyDown:
+		return "Down"
+	case rl.KeyLeft:
+		return "Left"
+	case rl.KeyRight:
+		return "Right"
+	case rl.KeyLeftShift, rl.KeyRightShift:
+		return "Shift"
+	case rl.KeyLeftControl, rl.KeyRightControl:
+		return "Ctrl"
+	case rl.KeyLeftAlt, rl.KeyRightAlt:
+		return "Alt"
+	}
+	if key >= rl.KeyF1 && key <= rl.KeyF12 {
+		return fmt.Sprintf("F%d", key-rl.KeyF1+1)
+	}
+	if key > 32 && key < 127 {
+		return string(rune(key))
+	}
+	return fmt.Sprintf("Key %d", key)
+}
+
+// maxMouseSensitivity caps the sensitivity setting; steps are 0.1
+const maxMouseSensitivity = 2.0
+
+// Camera follow smoothing
+const (
+	maxCameraSmoothing = 10
+	cameraFollowRate   = 30.0  // Catch-up per second at smoothing 1; divided by the setting
+	maxCameraLag       = 150.0 // Screen pixels the camera may trail the player by
+)
+
+func defaultSettings() Settings {
+	return Settings{
+		TargetFPS:        60,
+		Fullscreen:       false,
+		MasterVolume:     100,
+		MouseSensitivity: 1.0,
+		InvertY:          false,
+		CameraSmoothing:  3,
+	}
+}
+
+// loadSettings reads settings from path, falling back to defaults for a missing
+// file or out-of-range values
+func loadSettings(path string) Settings {
+	settings := defaultSettings()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("Failed to read settings: %v\n", err)
+		}
+		return settings
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		fmt.Printf("Failed to parse settings: %v\n", err)
+		return defaultSettings()
+	}
+
+	if fpsOptionIndex(settings.TargetFPS) < 0 {
+		settings.TargetFPS = defaultSettings().TargetFPS
+	}
+	if settings.MasterVolume < 0 {
+		settings.MasterVolume = 0
+	}
+	if settings.MasterVolume > 100 {
+		settings.MasterVolume = 100
+	}
+	if settings.MouseSensitivity < 0 {
+		settings.MouseSensitivity = 0
+	}
+	if settings.MouseSensitivity > maxMouseSensitivity {
+		settings.MouseSensitivity = maxMouseSensitivity
+	}
+	if settings.CameraSmoothing < 0 {
+		settings.CameraSmoothing = 0
+	}
+	if settings.CameraSmoothing > maxCameraSmoothing {
+		settings.CameraSmoothing = maxCameraSmoothing
+	}
+	return settings
+}
+
+func saveSettings(path string, settings Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// fpsOptionIndex returns the position of fps in fpsOptions, or -1
+func fpsOptionIndex(fps int) int {
+	for i, option := range fpsOptions {
+		if option == fps {
+			return i
+		}
+	}
+	return -1
+}
+
+// applySettings pushes the current settings to the window and audio device
+func (g *Game) applySettings() {
+	g.loadBindings()
+	rl.SetTargetFPS(int32(g.Settings.TargetFPS))
+	rl.SetMasterVolume(float32(g.Settings.MasterVolume) / 100)
+	if g.Settings.Fullscreen != rl.IsWindowFullscreen() {
+		rl.ToggleFullscreen()
+	}
+}
+
+func (g *Game) saveSettings() {
+	if err := saveSettings(settingsFile, g.Settings); err != nil {
+		fmt.Printf("Failed to save settings: %v\n", err)
+	}
+}
+
+// saveFile is where Save & Quit leaves a single-player match, relative to the
+// working directory
+const saveFile = "savegame.json"
+
+// saveVersion is bumped whenever SavedGame changes in a way old saves can't load
+const saveVersion = 1
+
+// SavedGame is a single-player match in progress. Multiplayer matches depend on
+// the other peers and can't be resumed, so they are never saved.
+type SavedGame struct {
+	Version     int                   `json:"version"`
+	WorldSeed   int64                 `json:"world_seed"`
+	WorldSize   int                   `json:"world_size"`
+	Density     int                   `json:"density"`
+	Physics     PhysicsMode           `json:"physics"`
+	Mode        GameMode              `json:"mode"`
+	TargetScore int                   `json:"target_score"`
+	Pace        int                   `json:"pace"`
+	GameTime    float32               `json:"game_time"`
+	MaxGameTime float32               `json:"max_game_time"`
+	LastMeal    float32               `json:"last_meal"`
+	Player      Hole                  `json:"player"`
+	Bots        []Bot                 `json:"bots"`
+	Objects     []GameObject          `json:"objects"` // Eaten objects are kept with Active false
+	PowerUpEnds [powerUpCount]float32 `json:"power_up_ends"`
+	GrowthBonus float32               `json:"growth_bonus"`
+	Catch       Catch                 `json:"catch"`
+	Eaten       int                   `json:"eaten"`
+}
+
+// SaveGame writes the current single-player match to path
+func (g *Game) SaveGame(path string) error {
+	if g.inMultiplayer() {
+		return fmt.Errorf("multiplayer matches can't be saved")
+	}
+	save := SavedGame{
+		Version:     saveVersion,
+		WorldSeed:   g.WorldSeed,
+		WorldSize:   g.WorldSize,
+		Density:     g.Density,
+		Physics:     g.Physics,
+		Mode:        g.Mode,
+		TargetScore: g.TargetScore,
+		Pace:        g.Pace,
+		GameTime:    g.GameTime,
+		MaxGameTime: g.MaxGameTime,
+		LastMeal:    g.lastMealTime,
+		Player:      g.Player,
+		Bots:        g.Bots,
+		Objects:     g.Objects,
+		PowerUpEnds: g.powerUpEnds,
+		GrowthBonus: g.growthBonus,
+		Catch:       g.biggestCatch,
+		Eaten:       g.objectsEaten,
+	}
+	data, err := json.Marshal(save)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadGame restores a single-player match written by SaveGame. The game is
+// left untouched if the file can't be read.
+func (g *Game) LoadGame(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
 	}
+	var save SavedGame
+	if err := json.Unmarshal(data, &save); err != nil {
+		return err
+	}
+	if save.Version != saveVersion {
+		return fmt.Errorf("save version %d, expected %d", save.Version, saveVersion)
+	}
+	// A corrupt save can name effects that don't exist; drop those pickups
+	// rather than index powerUpEnds with them when they're eaten
+	for i := range save.Objects {
+		if kind := save.Objects[i].PowerUp; kind != PowerUpNone && !kind.valid() {
+			save.Objects[i].PowerUp = PowerUpNone
+			save.Objects[i].Active = false
+		}
+	}
+
+	g.WorldSeed = save.WorldSeed
+	g.WorldSize = save.WorldSize
+	g.Density = save.Density
+	g.applyWorldSize()
+	g.Physics = save.Physics
+	g.Mode = save.Mode
+	g.TargetScore = save.TargetScore
+	g.Pace = save.Pace
+	g.GameTime = save.GameTime
+	g.MaxGameTime = save.MaxGameTime
+	g.lastMealTime = save.LastMeal
+	g.Player = save.Player
+	g.Bots = save.Bots
+	g.Objects = save.Objects
+	g.powerUpEnds = save.PowerUpEnds
+	g.growthBonus = save.GrowthBonus
+	g.biggestCatch = save.Catch
+	g.objectsEaten = save.Eaten
+
 	g.Camera = rl.Camera2D{
-		Offset:   rl.Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2},
-		Target:   rl.Vector2{X: worldWidth / 2, Y: worldHeight / 2},
-		Rotation: 0.0,
-		Zoom:     1.0,
+		Offset: rl.Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2},
+		Target: rl.Vector2{X: g.Player.Position.X, Y: g.Player.Position.Y},
+		Zoom:   1.0,
 	}
-	g.GameTime = 0.0
-	g.MaxGameTime = 120.0 // 2 minutes like the original
 	g.BaseZoom = 1.0
+	g.zoomOffset = 0
+	g.respawnBudget = 0
+	g.bounce = Vector2{}
+	g.practice = false
+	g.Particles = nil
+	g.ScorePopups = nil
+	g.rebuildObjectGrid()
+	g.resetConsumption()
+	return nil
+}
 
-	// Lock mouse cursor to the game window during gameplay
-	rl.DisableCursor()
+// continueSavedGame resumes the saved match from the main menu. The save is
+// used up either way, so a broken file doesn't keep offering Continue.
+func (g *Game) continueSavedGame() {
+	err := g.LoadGame(saveFile)
+	os.Remove(saveFile)
+	g.hasSave = false
+	if err != nil {
+		fmt.Printf("Failed to load saved game: %v\n", err)
+		g.MenuMessage = "Could not load the saved game"
+		return
+	}
+	g.State = StateGameplay
+	g.captureCursor()
+}
 
-	g.generateObjects()
+// saveAndQuit saves the paused single-player match and returns to the menu
+func (g *Game) saveAndQuit() {
+	if err := g.SaveGame(saveFile); err != nil {
+		fmt.Printf("Failed to save game: %v\n", err)
+		g.leaveToMenu()
+		g.MenuMessage = "Could not save the game"
+		return
+	}
+	g.hasSave = true
+	g.leaveToMenu()
+	g.MenuSelection = 0 // Continue
 }
 
-func (g *Game) generateObjects() {
-	rand.Seed(time.Now().UnixNano())
+// Replays are written to replayDir when a match ends
+const (
+	replayDir       = "replays"
+	replayVersion   = 1                 // Bumped whenever Replay changes; other versions are refused
+	maxReplayTime   = 15 * 60           // Seconds of match time recorded before the replay is cut short
+	replayFrameStep = 1.0 / 60          // Frames are sampled at most this often, whatever the frame rate
+	replayEndHold   = 2.0               // Seconds the last frame stays up before playback ends
+	replayTimeFmt   = "20060102-150405" // File name for each recording
+)
 
-	// Generate tiny objects (crumbs, coins, etc.) - easiest to eat
-	for i := 0; i < 150; i++ {
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     float32(1 + rand.Intn(2)), // 1-2 size
-			Color:    rl.Color{R: 255, G: 215, B: 0, A: 255}, // Gold
-			Type:     "tiny",
-			Value:    1,
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+// ReplayFrame is the local player's hole on one frame of a match
+type ReplayFrame struct {
+	T        float32 `json:"t"` // Match time
+	Position Vector2 `json:"p"`
+	Size     float32 `json:"s"`
+	Score    int     `json:"sc"`
+}
+
+// ReplayEvent is an object the local player ate
+type ReplayEvent struct {
+	T        float32  `json:"t"`
+	Index    int      `json:"i"` // Object index in the field generated from the seed
+	Position Vector2  `json:"p"`
+	Color    rl.Color `json:"c"`
+}
+
+// Replay is a recording of the local player's run. Playback rebuilds the
+// field from the seed and only shows the local player; opponents, respawns
+// and walking people aren't recorded.
+type Replay struct {
+	Version   int           `json:"version"`
+	WorldSeed int64         `json:"world_seed"`
+	WorldSize int           `json:"world_size"`
+	Density   int           `json:"density"`
+	Frames    []ReplayFrame `json:"frames"`
+	Events    []ReplayEvent `json:"events"`
+	Truncated bool          `json:"truncated,omitempty"` // Recording hit maxReplayTime
+}
+
+// SaveReplay writes replay to path, creating its directory if needed
+func SaveReplay(path string, replay *Replay) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadReplay reads a replay written by SaveReplay. Replays from another
+// version of the format are refused rather than played back wrong.
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	if replay.Version != replayVersion {
+		return nil, fmt.Errorf("replay version %d, expected %d", replay.Version, replayVersion)
+	}
+	if len(replay.Frames) == 0 {
+		return nil, fmt.Errorf("replay has no frames")
+	}
+	return &replay, nil
+}
+
+// recordFrame adds the player's current hole to the recording, starting a new
+// recording on the first frame of a match. Frames are sampled every
+// replayFrameStep of match time, so a fast machine doesn't fill the recording
+// sooner than a slow one.
+func (g *Game) recordFrame() {
+	if g.Autopilot || g.practice {
+		return
+	}
+	if g.recording == nil {
+		g.recording = &Replay{
+			Version:   replayVersion,
+			WorldSeed: g.WorldSeed,
+			WorldSize: g.WorldSize,
+			Density:   g.Density,
 		}
-		g.Objects = append(g.Objects, obj)
 	}
+	if frames := g.recording.Frames; len(frames) > 0 {
+		if g.GameTime-frames[0].T >= maxReplayTime {
+			g.recording.Truncated = true
+			return
+		}
+		if g.GameTime-frames[len(frames)-1].T < replayFrameStep {
+			return
+		}
+	}
+	g.recording.Frames = append(g.recording.Frames, ReplayFrame{
+		T:        g.GameTime,
+		Position: g.Player.Position,
+		Size:     g.Player.Size,
+		Score:    g.Player.Score,
+	})
+}
+
+// recordEat adds the object at index i, just eaten by the player, to the recording
+func (g *Game) recordEat(i int) {
+	if g.recording == nil || g.recording.Truncated {
+		return
+	}
+	obj := &g.Objects[i]
+	g.recording.Events = append(g.recording.Events, ReplayEvent{
+		T:        g.GameTime,
+		Index:    i,
+		Position: obj.Position,
+		Color:    obj.Color,
+	})
+}
 
-	// Generate small objects (people, pets, etc.)
-	for i := 0; i < 200; i++ {
-		size := float32(3 + rand.Intn(4)) // 3-6 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 139, G: 69, B: 19, A: 255}, // Saddle brown
-			Type:     "small",
-			Value:    int(size), // Value based on size
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+// finishRecording writes the match that just ended to a new file in replayDir
+func (g *Game) finishRecording() {
+	replay := g.recording
+	g.recording = nil
+	if replay == nil || len(replay.Frames) == 0 {
+		return
+	}
+	path := filepath.Join(replayDir, time.Now().Format(replayTimeFmt)+".replay")
+	if err := SaveReplay(path, replay); err != nil {
+		fmt.Printf("Failed to save replay: %v\n", err)
+		return
+	}
+	g.lastReplay = path
+}
+
+// watchReplay loads the replay at path and starts playing it back
+func (g *Game) watchReplay(path string) error {
+	replay, err := LoadReplay(path)
+	if err != nil {
+		return err
+	}
+
+	g.WorldSeed = replay.WorldSeed
+	g.WorldSize = replay.WorldSize
+	g.Density = replay.Density
+	g.applyWorldSize()
+	g.generateObjects(replay.WorldSeed)
+	g.Bots = nil
+	g.Particles = nil
+	g.ScorePopups = nil
+	g.clearPowerUps()
+
+	first := replay.Frames[0]
+	g.Player = Hole{Position: first.Position, Size: first.Size, Score: first.Score}
+	g.Camera = rl.Camera2D{
+		Offset: rl.Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2},
+		Target: rl.Vector2{X: first.Position.X, Y: first.Position.Y},
+		Zoom:   1.0,
+	}
+	g.BaseZoom = 1.0
+	g.zoomOffset = 0
+
+	g.playback = replay
+	g.playbackTime = first.T
+	g.playbackFrame = 0
+	g.playbackEvent = 0
+	g.State = StateReplay
+	rl.EnableCursor()
+	return nil
+}
+
+// updateReplay moves the playback on by deltaTime, driving the player's hole
+// and the camera from the recording
+func (g *Game) updateReplay(deltaTime float32) {
+	frames := g.playback.Frames
+	last := frames[len(frames)-1]
+	if backPressed() || g.playbackTime > last.T+replayEndHold {
+		g.stopReplay()
+		return
+	}
+
+	g.playbackTime += deltaTime
+	g.GameTime = g.playbackTime
+	for g.playbackFrame+1 < len(frames) && frames[g.playbackFrame+1].T <= g.playbackTime {
+		g.playbackFrame++
+	}
+	frame := frames[g.playbackFrame]
+	g.Player.Position = frame.Position
+	g.Player.Size = frame.Size
+	g.Player.Score = frame.Score
+	g.Player.Animation += deltaTime * 2.0
+
+	events := g.playback.Events
+	for g.playbackEvent < len(events) && events[g.playbackEvent].T <= g.playbackTime {
+		event := events[g.playbackEvent]
+		if event.Index >= 0 && event.Index < len(g.Objects) {
+			g.Objects[event.Index].Active = false
 		}
-		g.Objects = append(g.Objects, obj)
+		g.addParticle(event.Position, event.Color)
+		g.playbackEvent++
 	}
 
-	// Generate medium-small objects (bikes, benches, etc.)
-	for i := 0; i < 120; i++ {
-		size := float32(7 + rand.Intn(6)) // 7-12 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 0, G: 100, B: 0, A: 255}, // Dark green
-			Type:     "medium-small",
-			Value:    int(size),
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+	g.updateCamera(deltaTime)
+	g.updateEffects(deltaTime)
+}
+
+// stopReplay ends playback and returns to the main menu
+func (g *Game) stopReplay() {
+	g.playback = nil
+	g.State = StateMenu
+	g.MenuSelection = 0
+	g.GameTime = 0
+}
+
+// settingsItemCount is the number of selectable settings entries
+const settingsItemCount = 9
+
+func (g *Game) handleSettingsInput() {
+	if backPressed() {
+		g.State = StateMenu
+		return
+	}
+	step := g.menuStep()
+	if step < 0 {
+		g.SettingsChoice--
+		if g.SettingsChoice < 0 {
+			g.SettingsChoice = settingsItemCount - 1
+		}
+	}
+	if step > 0 {
+		g.SettingsChoice++
+		if g.SettingsChoice >= settingsItemCount {
+			g.SettingsChoice = 0
 		}
-		g.Objects = append(g.Objects, obj)
 	}
 
-	// Generate medium objects (cars, small trees, etc.)
-	for i := 0; i < 80; i++ {
-		size := float32(13 + rand.Intn(8)) // 13-20 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 34, G: 139, B: 34, A: 255}, // Forest green
-			Type:     "medium",
-			Value:    int(size),
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+	change := g.menuSideStep()
+	enter := confirmPressed()
+
+	changed := false
+	switch g.SettingsChoice {
+	case 0: // Target FPS
+		if change != 0 {
+			index := (fpsOptionIndex(g.Settings.TargetFPS) + change + len(fpsOptions)) % len(fpsOptions)
+			g.Settings.TargetFPS = fpsOptions[index]
+			rl.SetTargetFPS(int32(g.Settings.TargetFPS))
+			changed = true
+		}
+	case 1: // Fullscreen
+		if change != 0 || enter {
+			rl.ToggleFullscreen()
+			g.Settings.Fullscreen = rl.IsWindowFullscreen()
+			changed = true
+		}
+	case 2: // Master volume
+		if change != 0 {
+			volume := g.Settings.MasterVolume + change*10
+			if volume < 0 {
+				volume = 0
+			}
+			if volume > 100 {
+				volume = 100
+			}
+			g.Settings.MasterVolume = volume
+			rl.SetMasterVolume(float32(volume) / 100)
+			changed = true
+		}
+	case 3: // Mouse sensitivity
+		if change != 0 {
+			// Work in tenths so repeated steps land exactly back on 0
+			tenths := int(math.Round(float64(g.Settings.MouseSensitivity*10))) + change
+			if tenths < 0 {
+				tenths = 0
+			}
+			if tenths > maxMouseSensitivity*10 {
+				tenths = maxMouseSensitivity * 10
+			}
+			g.Settings.MouseSensitivity = float32(tenths) / 10
+			changed = true
+		}
+	case 4: // Invert Y
+		if change != 0 || enter {
+			g.Settings.InvertY = !g.Settings.InvertY
+			changed = true
+		}
+	case 5: // Visible cursor
+		if change != 0 || enter {
+			// Settings only opens from the main menu, so the cursor is
+			// set up for play the next time a match captures it
+			g.Settings.ShowCursor = !g.Settings.ShowCursor
+			changed = true
+		}
+	case 6: // Camera smoothing
+		if change != 0 {
+			smoothing := g.Settings.CameraSmoothing + change
+			if smoothing < 0 {
+				smoothing = 0
+			}
+			if smoothing > maxCameraSmoothing {
+				smoothing = maxCameraSmoothing
+			}
+			g.Settings.CameraSmoothing = smoothing
+			changed = true
+		}
+	case 7: // Controls
+		if enter {
+			g.State = StateControls
+			g.ControlsChoice = 0
+			g.ControlsMessage = ""
+		}
+	case 8: // Back
+		if enter {
+			g.State = StateMenu
 		}
-		g.Objects = append(g.Objects, obj)
 	}
 
-	// Generate medium-large objects (trucks, large trees, etc.)
-	for i := 0; i < 60; i++ {
-		size := float32(21 + rand.Intn(12)) // 21-32 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 70, G: 130, B: 180, A: 255}, // Steel blue
-			Type:     "medium-large",
-			Value:    int(size),
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+	if changed {
+		g.saveSettings()
+	}
+}
+
+func (g *Game) drawSettings() {
+	rl.BeginDrawing()
+
+	// Gradient background
+	rl.DrawRectangleGradientV(0, 0, screenWidth, screenHeight,
+		rl.Color{R: 25, G: 25, B: 112, A: 255}, // Midnight blue
+		rl.Color{R: 0, G: 0, B: 0, A: 255})     // Black
+
+	rl.DrawText("SETTINGS", screenWidth/2-110, 100, 50, rl.White)
+
+	onOff := func(on bool) string {
+		if on {
+			return "On"
 		}
-		g.Objects = append(g.Objects, obj)
+		return "Off"
+	}
+	mouse := fmt.Sprintf("< %.1f >", g.Settings.MouseSensitivity)
+	if g.Settings.MouseSensitivity == 0 {
+		mouse = "< Off >"
+	}
+	smoothing := fmt.Sprintf("< %d >", g.Settings.CameraSmoothing)
+	if g.Settings.CameraSmoothing == 0 {
+		smoothing = "< Off >"
+	}
+	settingsOptions := []string{
+		fmt.Sprintf("Target FPS: < %d >", g.Settings.TargetFPS),
+		fmt.Sprintf("Fullscreen: %s", onOff(g.Settings.Fullscreen)),
+		fmt.Sprintf("Master Volume: < %d >", g.Settings.MasterVolume),
+		fmt.Sprintf("Mouse Sensitivity: %s", mouse),
+		fmt.Sprintf("Invert Y: %s", onOff(g.Settings.InvertY)),
+		fmt.Sprintf("Visible Cursor: %s", onOff(g.Settings.ShowCursor)),
+		fmt.Sprintf("Camera Smoothing: %s", smoothing),
+		"Controls",
+		"Back",
+	}
+	for i, option := range settingsOptions {
+		y := 200 + i*50
+		color := rl.White
+		if i == g.SettingsChoice {
+			color = rl.Yellow
+			rl.DrawText(">", screenWidth/2-200, int32(y), 30, rl.Yellow)
+		}
+		rl.DrawText(option, screenWidth/2-150, int32(y), 30, color)
 	}
 
-	// Generate large objects (small buildings, etc.)
-	for i := 0; i < 40; i++ {
-		size := float32(33 + rand.Intn(15)) // 33-47 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
+	rl.DrawText("UP/DOWN to select, LEFT/RIGHT to change, ESC to go back", screenWidth/2-230, screenHeight-100, 18, rl.Gray)
+
+	rl.EndDrawing()
+}
+
+// controlsItemCount is every action plus Reset to Defaults and Back
+const controlsItemCount = int(actionCount) + 2
+
+// handleControlsInput picks an action and rebinds it to the next key pressed
+func (g *Game) handleControlsInput() {
+	if g.rebinding {
+		key := rl.GetKeyPressed()
+		if key == 0 {
+			return
+		}
+		g.rebinding = false
+		if key == rl.KeyEscape {
+			// ESC cancels, so it can't be bound
+			return
+		}
+		g.ControlsMessage = g.bindKey(Action(g.ControlsChoice), key)
+		return
+	}
+
+	if backPressed() {
+		g.State = StateSettings
+		return
+	}
+	step := g.menuStep()
+	if step != 0 {
+		g.ControlsChoice = (g.ControlsChoice + step + controlsItemCount) % controlsItemCount
+	}
+	if !confirmPressed() {
+		return
+	}
+	switch {
+	case g.ControlsChoice < int(actionCount):
+		g.rebinding = true
+		g.ControlsMessage = ""
+	case g.ControlsChoice == int(actionCount): // Reset to defaults
+		for action, info := range actions {
+			g.Bindings[Action(action)] = info.Default
+		}
+		g.Settings.KeyBindings = nil
+		g.saveSettings()
+		g.ControlsMessage = "Controls reset to defaults"
+	default: // Back
+		g.State = StateSettings
+	}
+}
+
+func (g *Game) drawControls() {
+	rl.BeginDrawing()
+
+	// Gradient background
+	rl.DrawRectangleGradientV(0, 0, screenWidth, screenHeight,
+		rl.Color{R: 25, G: 25, B: 112, A: 255}, // Midnight blue
+		rl.Color{R: 0, G: 0, B: 0, A: 255})     // Black
+
+	rl.DrawText("CONTROLS", screenWidth/2-120, 60, 50, rl.White)
+
+	for i := 0; i < controlsItemCount; i++ {
+		y := int32(150 + i*45)
+		color := rl.White
+		if i == g.ControlsChoice {
+			color = rl.Yellow
+			rl.DrawText(">", screenWidth/2-250, y, 26, rl.Yellow)
+		}
+		switch {
+		case i < int(actionCount):
+			key := keyName(g.Bindings[Action(i)])
+			if g.rebinding && i == g.ControlsChoice {
+				key = "Press a key..."
+			}
+			rl.DrawText(actions[i].Label, screenWidth/2-200, y, 26, color)
+			rl.DrawText(key, screenWidth/2+120, y, 26, color)
+		case i == int(actionCount):
+			rl.DrawText("Reset to Defaults", screenWidth/2-200, y, 26, color)
+		default:
+			rl.DrawText("Back", screenWidth/2-200, y, 26, color)
+		}
+	}
+
+	if g.ControlsMessage != "" {
+		rl.DrawText(g.ControlsMessage, screenWidth/2-rl.MeasureText(g.ControlsMessage, 18)/2, screenHeight-140, 18, rl.Orange)
+	}
+	rl.DrawText("ENTER to rebind, ESC to cancel or go back. Arrow keys always move too.", screenWidth/2-300, screenHeight-100, 18, rl.Gray)
+
+	rl.EndDrawing()
+}
+
+func getLocalIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "127.0.0.1"
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
+			if ipNet.IP.To4() != nil {
+				ipStr := ipNet.IP.String()
+				// Prefer 192.168.x.x or 10.x.x.x ranges for LAN
+				if strings.HasPrefix(ipStr, "192.168.") || strings.HasPrefix(ipStr, "10.") {
+					return ipStr
+				}
+			}
+		}
+	}
+	return "127.0.0.1"
+}
+
+func NewGame() *Game {
+	rand.Seed(time.Now().UnixNano())
+	localIP := getLocalIP()
+	game := &Game{
+		State:          StateMenu,
+		NetworkPlayers: make(map[int]*NetworkPlayer),
+		clientByID:     make(map[int]net.Conn),
+		udpPeers:       make(map[int]*net.UDPAddr),
+		MenuSelection:  0,
+		PlayerID:       rand.Intn(10000),
+		ServerIP:       localIP + ":8080",
+		LocalIP:        localIP,
+		MinPlayers:     2,
+		MaxPlayers:     defaultMaxPlayers,
+		ReconnectTries: defaultReconnectTries,
+		ReconnectDelay: defaultReconnectDelay,
+		LobbyReady:     false,
+		GameStarted:    false,
+		Sounds:         NewSoundBank(),
+		BotCount:       defaultBotCount,
+		BotSpeed:       defaultBotSpeedMultiplier,
+		BotReaction:    defaultBotReactionRadius,
+		RespawnEnabled: true,
+		RespawnRate:    defaultRespawnRate,
+		WeightPenalty:  defaultWeightPenalty,
+		WeightRecovery: defaultWeightRecovery,
+		BorderBounce:   defaultBorderBounce,
+		EdgeGlow:       true,
+		Settings:       loadSettings(settingsFile),
+		ShowMinimap:    true,
+		TargetScore:    defaultTargetScore,
+		WorldSize:      defaultWorldSize,
+		Density:        defaultObjectDensity,
+		MatchDuration:  defaultMatchDuration,
+	}
+	if _, err := os.Stat(saveFile); err == nil {
+		game.hasSave = true
+	}
+	game.applySettings()
+	return game
+}
+
+// networkPlayerCount returns the number of remote players
+func (g *Game) networkPlayerCount() int {
+	g.netMu.RLock()
+	defer g.netMu.RUnlock()
+	return len(g.NetworkPlayers)
+}
+
+// networkPlayersSnapshot copies the remote players, ordered by ID, so callers can
+// read them without holding the lock while network goroutines keep updating
+func (g *Game) networkPlayersSnapshot() []NetworkPlayer {
+	g.netMu.RLock()
+	players := make([]NetworkPlayer, 0, len(g.NetworkPlayers))
+	for _, player := range g.NetworkPlayers {
+		players = append(players, *player)
+	}
+	g.netMu.RUnlock()
+
+	sort.Slice(players, func(i, j int) bool { return players[i].ID < players[j].ID })
+	return players
+}
+
+func (g *Game) initSinglePlayer() {
+	g.State = StateSinglePlayer
+	g.prepareMatch()
+	g.spawnBots()
+}
+
+// initPractice starts an untimed single-player session with no opponents, for
+// learning the controls. It never ends on its own; back returns to the menu.
+func (g *Game) initPractice() {
+	g.initSinglePlayer()
+	g.practice = true
+	g.Bots = nil
+}
+
+// resetPracticeField lays out a fresh object field around the practicing player
+func (g *Game) resetPracticeField() {
+	g.WorldSeed = time.Now().UnixNano()
+	g.generateObjects(g.WorldSeed)
+	g.nearMissGlow = 0
+}
+
+// prepareMatch resets the world and captures the mouse for a new match
+func (g *Game) prepareMatch() {
+	g.resetMatch()
+	g.captureCursor()
+}
+
+// spawnBots places BotCount AI holes away from the player's starting spot
+func (g *Game) spawnBots() {
+	colors := []rl.Color{rl.Red, rl.Blue, rl.Green, rl.Yellow, rl.Purple, rl.Orange}
+	g.Bots = make([]Bot, 0, g.BotCount)
+	for i := 0; i < g.BotCount; i++ {
+		pos := g.randomWorldPoint(holeRespawnSize)
+		// Keep the opening seconds free of opponents
+		for distanceBetween(pos, g.Player.Position) < 300 {
+			pos = g.randomWorldPoint(holeRespawnSize)
+		}
+		g.Bots = append(g.Bots, Bot{
+			Hole: Hole{
+				Position: pos,
+				Size:     holeRespawnSize,
+				Speed:    g.Player.Speed * g.BotSpeed,
+			},
+			Name:   fmt.Sprintf("Bot %d", i+1),
+			Color:  colors[i%len(colors)],
+			Wander: g.randomWorldPoint(holeRespawnSize),
+		})
+	}
+}
+
+// updateBots steers each bot toward the nearest food it notices and lets it eat
+func (g *Game) updateBots(deltaTime float32) {
+	for i := range g.Bots {
+		bot := &g.Bots[i]
+		bot.Hole.Animation += deltaTime * 2.0
+
+		if target := g.nearestEdibleObject(&bot.Hole, g.BotReaction); target >= 0 {
+			steerToward(&bot.Hole, g.Objects[target].Position, deltaTime)
+		} else {
+			// Nothing in sight - roam until something shows up
+			if distanceBetween(bot.Hole.Position, bot.Wander) < bot.Hole.Size {
+				bot.Wander = g.randomWorldPoint(bot.Hole.Size)
+			}
+			steerToward(&bot.Hole, bot.Wander, deltaTime)
+		}
+		g.clampToWorld(&bot.Hole)
+		g.pushOutOfObstacles(&bot.Hole)
+
+		for _, j := range g.nearbyObjects(bot.Hole.Position, bot.Hole.Size) {
+			obj := &g.Objects[j]
+			if !obj.Active || !canConsume(&bot.Hole, obj, PhysicsClassic) {
+				continue
+			}
+			g.addParticle(obj.Position, obj.Color)
+			obj.Active = false
+			g.objectGrid.Remove(j, obj.Position)
+			bot.Hole.Score += obj.Value
+			bot.Hole.Size += g.growthCurve().Growth(bot.Hole.Size, obj.Value)
+			bot.Eaten++
+		}
+	}
+}
+
+// resetMatch places a fresh player hole, camera, timer and object field
+func (g *Game) resetMatch() {
+	g.resetMatchState(time.Now().UnixNano())
+}
+
+// resetMatchState places a fresh player hole, camera and timer on the object
+// field built from seed. New matches and multiplayer rounds both start here.
+func (g *Game) resetMatchState(seed int64) {
+	g.applyWorldSize()
+	g.Player = Hole{
+		Position:  Vector2{X: g.WorldWidth / 2, Y: g.WorldHeight / 2},
+		Size:      20.0,
+		Score:     0,
+		Speed:     200.0,
+		Animation: 0.0,
+	}
+	g.Camera = rl.Camera2D{
+		Offset:   rl.Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2},
+		Target:   rl.Vector2{X: g.WorldWidth / 2, Y: g.WorldHeight / 2},
+		Rotation: 0.0,
+		Zoom:     1.0,
+	}
+	g.GameTime = 0.0
+	g.MaxGameTime = g.matchDuration()
+	g.practice = false
+	g.lastMealTime = 0
+	g.biggestCatch = Catch{}
+	g.objectsEaten = 0
+	g.clearSuddenDeath()
+	g.nearMissGlow = 0
+	g.bounce = Vector2{}
+	g.BaseZoom = 1.0
+	g.zoomOffset = 0
+	g.Bots = nil
+	g.respawnBudget = 0
+	g.slowdown = 0
+	g.clearPowerUps()
+
+	g.WorldSeed = seed
+	g.generateObjects(seed)
+	g.resetConsumption()
+}
+
+// generateObjects lays out a fresh object field from seed. Every peer that uses
+// the same seed gets the same objects at the same indices.
+func (g *Game) generateObjects(seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	g.Objects = nil
+
+	// Generate tiny objects (crumbs, coins, etc.) - easiest to eat
+	for i, n := 0, g.spawnCount(150); i < n; i++ {
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     float32(1 + rng.Intn(2)), // 1-2 size
+			Color:    rl.Color{R: 255, G: 215, B: 0, A: 255}, // Gold
+			Type:     "tiny",
+			Value:    1,
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate small objects (people, pets, etc.)
+	for i, n := 0, g.spawnCount(200); i < n; i++ {
+		size := float32(3 + rng.Intn(4)) // 3-6 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 139, G: 69, B: 19, A: 255}, // Saddle brown
+			Type:     "small",
+			Value:    int(size), // Value based on size
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+			Wander:   walkVelocity(rng.Float32() * 360),
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate medium-small objects (bikes, benches, etc.)
+	for i, n := 0, g.spawnCount(120); i < n; i++ {
+		size := float32(7 + rng.Intn(6)) // 7-12 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 0, G: 100, B: 0, A: 255}, // Dark green
+			Type:     "medium-small",
+			Value:    int(size),
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate medium objects (cars, small trees, etc.)
+	for i, n := 0, g.spawnCount(80); i < n; i++ {
+		size := float32(13 + rng.Intn(8)) // 13-20 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 34, G: 139, B: 34, A: 255}, // Forest green
+			Type:     "medium",
+			Value:    int(size),
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate medium-large objects (trucks, large trees, etc.)
+	for i, n := 0, g.spawnCount(60); i < n; i++ {
+		size := float32(21 + rng.Intn(12)) // 21-32 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 70, G: 130, B: 180, A: 255}, // Steel blue
+			Type:     "medium-large",
+			Value:    int(size),
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate large objects (small buildings, etc.)
+	for i, n := 0, g.spawnCount(40); i < n; i++ {
+		size := float32(33 + rng.Intn(15)) // 33-47 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
 			Size:     size,
 			Color:    rl.Color{R: 105, G: 105, B: 105, A: 255}, // Dim gray
 			Type:     "large",
 			Value:    int(size),
 			Active:   true,
-			Rotation: rand.Float32() * 360,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate extra large objects (medium buildings, etc.)
+	for i, n := 0, g.spawnCount(25); i < n; i++ {
+		size := float32(48 + rng.Intn(20)) // 48-67 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 128, G: 128, B: 128, A: 255}, // Gray
+			Type:     "extra-large",
+			Value:    int(size),
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate huge objects (large buildings, etc.)
+	for i, n := 0, g.spawnCount(15); i < n; i++ {
+		size := float32(68 + rng.Intn(25)) // 68-92 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 169, G: 169, B: 169, A: 255}, // Dark gray
+			Type:     "huge",
+			Value:    int(size),
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate massive objects (skyscrapers, etc.) - end game content
+	for i, n := 0, g.spawnCount(8); i < n; i++ {
+		size := float32(93 + rng.Intn(30)) // 93-122 size
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     size,
+			Color:    rl.Color{R: 47, G: 79, B: 79, A: 255}, // Dark slate gray
+			Type:     "massive",
+			Value:    int(size),
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate power-up pickups
+	for i, n := 0, g.spawnCount(powerUpPickups); i < n; i++ {
+		kind := PowerUp(1 + rng.Intn(int(powerUpCount)-1))
+		obj := GameObject{
+			Position: Vector2{
+				X: rng.Float32() * g.WorldWidth,
+				Y: rng.Float32() * g.WorldHeight,
+			},
+			Size:     powerUpSize,
+			Color:    kind.Color(),
+			Type:     "powerup",
+			Value:    5,
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+			PowerUp:  kind,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	// Generate obstacles (rocks, walls) - block the hole until it outgrows them
+	center := Vector2{X: g.WorldWidth / 2, Y: g.WorldHeight / 2}
+	for i, n := 0, g.spawnCount(12); i < n; i++ {
+		size := float32(obstacleMinSize + rng.Intn(obstacleSizeRange)) // 25-60 size
+		position := Vector2{X: rng.Float32() * g.WorldWidth, Y: rng.Float32() * g.WorldHeight}
+		// Keep the starting spot clear so nobody spawns boxed in
+		for attempt := 0; attempt < 20 && distanceBetween(position, center) < size+obstacleClearance; attempt++ {
+			position = Vector2{X: rng.Float32() * g.WorldWidth, Y: rng.Float32() * g.WorldHeight}
+		}
+		obj := GameObject{
+			Position: position,
+			Size:     size,
+			Color:    rl.Color{R: 110, G: 95, B: 80, A: 255}, // Weathered stone
+			Type:     obstacleType,
+			Value:    int(size) * 2,
+			Active:   true,
+			Rotation: rng.Float32() * 360,
+		}
+		g.Objects = append(g.Objects, obj)
+	}
+
+	g.rebuildObjectGrid()
+}
+
+// gridCellSize is the edge length of a SpatialGrid cell in world units
+const gridCellSize = 64.0
+
+type gridCell struct {
+	X, Y int
+}
+
+// SpatialGrid buckets object indices by position so collision checks only
+// visit objects near a hole instead of the whole field
+type SpatialGrid struct {
+	cellSize float32
+	cells    map[gridCell][]int
+}
+
+func NewSpatialGrid(cellSize float32) *SpatialGrid {
+	return &SpatialGrid{
+		cellSize: cellSize,
+		cells:    make(map[gridCell][]int),
+	}
+}
+
+func (sg *SpatialGrid) cellFor(pos Vector2) gridCell {
+	return gridCell{
+		X: int(math.Floor(float64(pos.X / sg.cellSize))),
+		Y: int(math.Floor(float64(pos.Y / sg.cellSize))),
+	}
+}
+
+// Insert adds index to the cell containing pos
+func (sg *SpatialGrid) Insert(index int, pos Vector2) {
+	cell := sg.cellFor(pos)
+	sg.cells[cell] = append(sg.cells[cell], index)
+}
+
+// Remove drops index from the cell containing pos
+func (sg *SpatialGrid) Remove(index int, pos Vector2) {
+	cell := sg.cellFor(pos)
+	bucket := sg.cells[cell]
+	for i, entry := range bucket {
+		if entry == index {
+			bucket[i] = bucket[len(bucket)-1]
+			bucket = bucket[:len(bucket)-1]
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(sg.cells, cell)
+	} else {
+		sg.cells[cell] = bucket
+	}
+}
+
+// Move rebuckets index after it travels from one position to another
+func (sg *SpatialGrid) Move(index int, from, to Vector2) {
+	if sg.cellFor(from) == sg.cellFor(to) {
+		return
+	}
+	sg.Remove(index, from)
+	sg.Insert(index, to)
+}
+
+// QueryCircle appends to out every index in a cell overlapping the circle.
+// Results are candidates; callers still do their own exact distance check.
+func (sg *SpatialGrid) QueryCircle(center Vector2, radius float32, out []int) []int {
+	min := sg.cellFor(Vector2{X: center.X - radius, Y: center.Y - radius})
+	max := sg.cellFor(Vector2{X: center.X + radius, Y: center.Y + radius})
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			out = append(out, sg.cells[gridCell{X: x, Y: y}]...)
+		}
+	}
+	return out
+}
+
+// rebuildObjectGrid indexes every active object from scratch
+func (g *Game) rebuildObjectGrid() {
+	g.objectGrid = NewSpatialGrid(gridCellSize)
+	g.drifting = g.drifting[:0]
+	for i := range g.Objects {
+		if g.Objects[i].Active {
+			g.objectGrid.Insert(i, g.Objects[i].Position)
+		}
+	}
+}
+
+// nearbyObjects returns the indices of objects that may lie within radius of center
+func (g *Game) nearbyObjects(center Vector2, radius float32) []int {
+	g.gridQuery = g.objectGrid.QueryCircle(center, radius, g.gridQuery[:0])
+	return g.gridQuery
+}
+
+// objectTier describes one size band of generated objects
+type objectTier struct {
+	Type      string
+	MinSize   int
+	SizeRange int
+	Color     rl.Color
+}
+
+// objectTiers mirrors the bands generateObjects fills, smallest first
+var objectTiers = []objectTier{
+	{"tiny", 1, 2, rl.Color{R: 255, G: 215, B: 0, A: 255}},
+	{"small", 3, 4, rl.Color{R: 139, G: 69, B: 19, A: 255}},
+	{"medium-small", 7, 6, rl.Color{R: 0, G: 100, B: 0, A: 255}},
+	{"medium", 13, 8, rl.Color{R: 34, G: 139, B: 34, A: 255}},
+	{"medium-large", 21, 12, rl.Color{R: 70, G: 130, B: 180, A: 255}},
+	{"large", 33, 15, rl.Color{R: 105, G: 105, B: 105, A: 255}},
+	{"extra-large", 48, 20, rl.Color{R: 128, G: 128, B: 128, A: 255}},
+	{"huge", 68, 25, rl.Color{R: 169, G: 169, B: 169, A: 255}},
+	{"massive", 93, 30, rl.Color{R: 47, G: 79, B: 79, A: 255}},
+}
+
+const (
+	defaultRespawnRate = 3.0   // Objects brought back per second
+	respawnClearance   = 100.0 // Extra gap kept between a respawned object and the player
+)
+
+// respawnTierFor picks a size band near what a hole of the given size can eat,
+// occasionally one step smaller or bigger so there is still something to grow into
+func respawnTierFor(holeSize float32) objectTier {
+	index := 0
+	for i, tier := range objectTiers {
+		if float32(tier.MinSize)*0.8 < holeSize {
+			index = i
+		}
+	}
+	index += rand.Intn(3) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(objectTiers) {
+		index = len(objectTiers) - 1
+	}
+	return objectTiers[index]
+}
+
+// respawnObjects reactivates consumed objects at RespawnRate per second so the
+// field doesn't run dry over a long match
+func (g *Game) respawnObjects(deltaTime float32) {
+	g.respawnBudget += g.RespawnRate * deltaTime
+	for g.respawnBudget >= 1 {
+		index := -1
+		for i := range g.Objects {
+			if !g.Objects[i].Active {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			// Nothing to bring back; don't bank a burst for later
+			g.respawnBudget = 0
+			return
+		}
+		g.respawnBudget--
+		g.respawnObject(&g.Objects[index])
+		g.objectGrid.Insert(index, g.Objects[index].Position)
+	}
+}
+
+// respawnObject refills obj with a fresh object away from the player
+func (g *Game) respawnObject(obj *GameObject) {
+	tier := respawnTierFor(g.Player.Size)
+	size := float32(tier.MinSize + rand.Intn(tier.SizeRange))
+	value := int(size)
+	if tier.Type == "tiny" {
+		value = 1
+	}
+
+	// A huge hole can cover most of the map, so give up on the clearance eventually
+	position := g.randomWorldPoint(size)
+	for attempt := 0; attempt < 20 && distanceBetween(position, g.Player.Position) < g.Player.Size+size+respawnClearance; attempt++ {
+		position = g.randomWorldPoint(size)
+	}
+
+	*obj = GameObject{
+		Position: position,
+		Size:     size,
+		Color:    tier.Color,
+		Type:     tier.Type,
+		Value:    value,
+		Active:   true,
+		Rotation: rand.Float32() * 360,
+	}
+	if tier.Type == "small" {
+		obj.Wander = walkVelocity(rand.Float32() * 360)
+	}
+}
+
+func (g *Game) addParticle(pos Vector2, color rl.Color) {
+	for i := 0; i < 3; i++ {
+		particle := Particle{
+			Position: pos,
+			Velocity: Vector2{
+				X: (rand.Float32() - 0.5) * 100,
+				Y: (rand.Float32() - 0.5) * 100,
+			},
+			Life:    1.0,
+			MaxLife: 1.0,
+			Color:   color,
+			Size:    2 + rand.Float32()*3,
+		}
+		g.Particles = append(g.Particles, particle)
+	}
+}
+
+func (g *Game) addScorePopup(pos Vector2, value int) {
+	// Drop the oldest popup when at capacity so a feeding frenzy stays readable
+	if len(g.ScorePopups) >= maxScorePopups {
+		g.ScorePopups = append(g.ScorePopups[:0], g.ScorePopups[1:]...)
+	}
+	g.ScorePopups = append(g.ScorePopups, ScorePopup{
+		Position: pos,
+		Value:    value,
+		Life:     scorePopupLife,
+		MaxLife:  scorePopupLife,
+	})
+}
+
+// scorePopupFontSize maps a consumed value to a font size so big eats read bigger
+func scorePopupFontSize(value int) int32 {
+	size := int32(scorePopupMinFont + value/3)
+	if size > scorePopupMaxFont {
+		size = scorePopupMaxFont
+	}
+	return size
+}
+
+// applyObjectAttraction pulls objects a hole can swallow toward it before
+// they are consumed. Every hole in the match pulls, ours and the other
+// players', in player ID order, and the motion is stepped at a fixed rate with
+// nothing random in it, so every peer moves the field the same way from the
+// same holes whatever its frame rate.
+func (g *Game) applyObjectAttraction(deltaTime float32) {
+	g.attractors = g.attractors[:0]
+	local := false
+	for _, player := range g.networkPlayersSnapshot() {
+		if !local && g.PlayerID < player.ID {
+			g.attractors = append(g.attractors, g.Player)
+			local = true
+		}
+		g.attractors = append(g.attractors, player.Hole)
+	}
+	if !local {
+		g.attractors = append(g.attractors, g.Player)
+	}
+
+	g.attractionTime += deltaTime
+	for g.attractionTime >= attractionStep {
+		g.attractionTime -= attractionStep
+		g.stepObjectAttraction(g.attractors, attractionStep)
+	}
+}
+
+// stepObjectAttraction advances the pull by one fixed step. Only objects near
+// a hole, found through the grid, and those still drifting are touched.
+func (g *Game) stepObjectAttraction(holes []Hole, dt float32) {
+	pull, drag := g.Physics.params()
+
+	for _, hole := range holes {
+		radius := hole.Size * attractionRadiusFactor
+		for _, i := range g.nearbyObjects(hole.Position, radius) {
+			obj := &g.Objects[i]
+			if !obj.Active || !fitsInside(hole.Size, obj) {
+				continue
+			}
+			dx := hole.Position.X - obj.Position.X
+			dy := hole.Position.Y - obj.Position.Y
+			distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+
+			falloff := attractionFalloff(distance, radius)
+			if falloff <= 0 || distance == 0 {
+				continue
+			}
+			if obj.Velocity == (Vector2{}) {
+				g.drifting = append(g.drifting, i)
+			}
+			// Bigger holes pull harder, heavier objects accelerate slower
+			accel := attractionStrength * pull * falloff * hole.Size / obj.mass()
+			obj.Velocity.X += dx / distance * accel * dt
+			obj.Velocity.Y += dy / distance * accel * dt
+		}
+	}
+
+	// Drag bleeds off speed once an object leaves the pull, until it comes to rest
+	damping := 1 - drag*dt
+	if damping < 0 {
+		damping = 0
+	}
+	moving := g.drifting[:0]
+	for _, i := range g.drifting {
+		obj := &g.Objects[i]
+		obj.Velocity.X *= damping
+		obj.Velocity.Y *= damping
+
+		speed := float32(math.Sqrt(float64(obj.Velocity.X*obj.Velocity.X + obj.Velocity.Y*obj.Velocity.Y)))
+		if !obj.Active || speed < attractionRestSpeed {
+			obj.Velocity = Vector2{}
+			continue
+		}
+		if speed > attractionMaxSpeed {
+			obj.Velocity.X *= attractionMaxSpeed / speed
+			obj.Velocity.Y *= attractionMaxSpeed / speed
+		}
+
+		from := obj.Position
+		obj.Position.X += obj.Velocity.X * dt
+		obj.Position.Y += obj.Velocity.Y * dt
+		g.objectGrid.Move(i, from, obj.Position)
+		moving = append(moving, i)
+	}
+	g.drifting = moving
+}
+
+// updateWalkers moves wandering NPCs: they amble about, turning now and then,
+// bounce off the world edges and flee when the player's hole could eat them
+func (g *Game) updateWalkers(deltaTime float32) {
+	for i := range g.Objects {
+		obj := &g.Objects[i]
+		if !obj.Active || (obj.Wander.X == 0 && obj.Wander.Y == 0) {
+			continue
+		}
+
+		if rand.Float32() < npcTurnChance*deltaTime {
+			obj.Wander = walkVelocity(rand.Float32() * 360)
+		}
+
+		velocity := obj.Wander
+		dx := obj.Position.X - g.Player.Position.X
+		dy := obj.Position.Y - g.Player.Position.Y
+		distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		if bigEnoughToEat(g.Player.Size, obj) && distance < g.Player.Size+npcFleeRadius && distance > 0 {
+			velocity = Vector2{X: dx / distance * npcFleeSpeed, Y: dy / distance * npcFleeSpeed}
+		}
+
+		from := obj.Position
+		obj.Position.X += velocity.X * deltaTime
+		obj.Position.Y += velocity.Y * deltaTime
+
+		// Bounce off the edges of the world
+		if obj.Position.X < obj.Size {
+			obj.Position.X = obj.Size
+			obj.Wander.X = float32(math.Abs(float64(obj.Wander.X)))
+		} else if obj.Position.X > g.WorldWidth-obj.Size {
+			obj.Position.X = g.WorldWidth - obj.Size
+			obj.Wander.X = -float32(math.Abs(float64(obj.Wander.X)))
+		}
+		if obj.Position.Y < obj.Size {
+			obj.Position.Y = obj.Size
+			obj.Wander.Y = float32(math.Abs(float64(obj.Wander.Y)))
+		} else if obj.Position.Y > g.WorldHeight-obj.Size {
+			obj.Position.Y = g.WorldHeight - obj.Size
+			obj.Wander.Y = -float32(math.Abs(float64(obj.Wander.Y)))
+		}
+		g.objectGrid.Move(i, from, obj.Position)
+	}
+}
+
+// nearestEdibleObject returns the index of the closest object within radius that
+// hole h can consume, or -1
+func (g *Game) nearestEdibleObject(h *Hole, radius float32) int {
+	best := -1
+	bestDist := radius * radius
+	for i := range g.Objects {
+		obj := &g.Objects[i]
+		if !obj.Active || !bigEnoughToEat(h.Size, obj) {
+			continue
+		}
+		dx := obj.Position.X - h.Position.X
+		dy := obj.Position.Y - h.Position.Y
+		dist := dx*dx + dy*dy
+		if dist < bestDist {
+			best = i
+			bestDist = dist
+		}
+	}
+	return best
+}
+
+// steerToward moves hole h toward target at its normal speed
+func steerToward(h *Hole, target Vector2, deltaTime float32) {
+	dx := target.X - h.Position.X
+	dy := target.Y - h.Position.Y
+	length := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	if length == 0 {
+		return
+	}
+
+	step := h.Speed * deltaTime
+	if step > length {
+		step = length
+	}
+	h.Position.X += dx / length * step
+	h.Position.Y += dy / length * step
+}
+
+// Gamepad input. Every gamepad check goes through IsGamepadAvailable so the
+// game falls back to keyboard and mouse alone when none is plugged in.
+const (
+	gamepadID        = 0
+	gamepadDeadzone  = 0.2 // Stick travel ignored so drift doesn't creep the hole
+	gamepadMenuFlick = 0.5 // Stick travel that counts as a menu step
+)
+
+// gamepadStick returns the left stick with the deadzone cut out and the rest
+// rescaled to 0-1, or zero when no gamepad is connected
+func gamepadStick() Vector2 {
+	if !rl.IsGamepadAvailable(gamepadID) {
+		return Vector2{}
+	}
+	x := rl.GetGamepadAxisMovement(gamepadID, rl.GamepadAxisLeftX)
+	y := rl.GetGamepadAxisMovement(gamepadID, rl.GamepadAxisLeftY)
+	length := float32(math.Sqrt(float64(x*x + y*y)))
+	if length < gamepadDeadzone {
+		return Vector2{}
+	}
+
+	magnitude := (length - gamepadDeadzone) / (1 - gamepadDeadzone)
+	if magnitude > 1 {
+		magnitude = 1
+	}
+	return Vector2{X: x / length * magnitude, Y: y / length * magnitude}
+}
+
+func gamepadButtonPressed(button int32) bool {
+	return rl.IsGamepadAvailable(gamepadID) && rl.IsGamepadButtonPressed(gamepadID, button)
+}
+
+// Keyboard and cursor calls the menus go through, so tests can drive them
+// without a window
+var (
+	keyPressed    = rl.IsKeyPressed
+	enableCursor  = rl.EnableCursor
+	disableCursor = rl.DisableCursor
+)
+
+// confirmPressed reports Enter or the gamepad A button
+func confirmPressed() bool {
+	return keyPressed(rl.KeyEnter) || gamepadButtonPressed(rl.GamepadButtonRightFaceDown)
+}
+
+// backPressed reports Escape or the gamepad B button
+func backPressed() bool {
+	return keyPressed(rl.KeyEscape) || gamepadButtonPressed(rl.GamepadButtonRightFaceRight)
+}
+
+// stickStep turns an axis into a single step when it first crosses the flick
+// threshold, so holding the stick doesn't race through a whole menu
+func stickStep(value float32, held *int) int {
+	direction := 0
+	if value < -gamepadMenuFlick {
+		direction = -1
+	} else if value > gamepadMenuFlick {
+		direction = 1
+	}
+	if direction == *held {
+		return 0
+	}
+	*held = direction
+	return direction
+}
+
+// menuStep returns -1 for up and 1 for down from the arrow keys, D-pad or stick
+func (g *Game) menuStep() int {
+	step := stickStep(gamepadStick().Y, &g.stickHeldY)
+	if rl.IsKeyPressed(rl.KeyUp) || g.actionPressed(ActionMoveUp) || gamepadButtonPressed(rl.GamepadButtonLeftFaceUp) {
+		step = -1
+	}
+	if rl.IsKeyPressed(rl.KeyDown) || g.actionPressed(ActionMoveDown) || gamepadButtonPressed(rl.GamepadButtonLeftFaceDown) {
+		step = 1
+	}
+	return step
+}
+
+// menuSideStep returns -1 for left and 1 for right from the arrow keys, D-pad or stick
+func (g *Game) menuSideStep() int {
+	step := stickStep(gamepadStick().X, &g.stickHeldX)
+	if rl.IsKeyPressed(rl.KeyLeft) || g.actionPressed(ActionMoveLeft) || gamepadButtonPressed(rl.GamepadButtonLeftFaceLeft) {
+		step = -1
+	}
+	if rl.IsKeyPressed(rl.KeyRight) || g.actionPressed(ActionMoveRight) || gamepadButtonPressed(rl.GamepadButtonLeftFaceRight) {
+		step = 1
+	}
+	return step
+}
+
+// attractModeDelay is how long the menu must sit idle before the demo starts
+const attractModeDelay = 20.0
+
+// inputIdleTimeout closes a menu text box nobody is typing in, so an
+// unattended machine doesn't sit with every key captured
+const inputIdleTimeout = 30.0
+
+// menuInputDetected reports whether the player touched keyboard or mouse this frame
+func menuInputDetected() bool {
+	mouseDelta := rl.GetMouseDelta()
+	stick := gamepadStick()
+	return rl.GetKeyPressed() != 0 ||
+		rl.GetGamepadButtonPressed() != 0 || stick.X != 0 || stick.Y != 0 ||
+		mouseDelta.X != 0 || mouseDelta.Y != 0 ||
+		rl.IsMouseButtonPressed(rl.MouseButtonLeft) ||
+		rl.IsMouseButtonPressed(rl.MouseButtonRight)
+}
+
+func (g *Game) startAttractMode() {
+	demo := &Game{
+		State:          StateGameplay,
+		NetworkPlayers: make(map[int]*NetworkPlayer),
+		Physics:        g.Physics,
+		Textures:       g.Textures,
+		Autopilot:      true,
+		WorldSize:      g.WorldSize,
+		Density:        g.Density,
+	}
+	demo.resetMatch()
+	g.attractGame = demo
+}
+
+func (g *Game) stopAttractMode() {
+	g.attractGame = nil
+	g.menuIdleTime = 0
+}
+
+// updateAttractMode runs the demo match behind an idle menu. It returns true when
+// the menu should skip its own input this frame (demo running or just dismissed).
+func (g *Game) updateAttractMode(deltaTime float32) bool {
+	if menuInputDetected() {
+		g.menuIdleTime = 0
+		if g.attractGame != nil {
+			// Swallow the input that woke the menu so it doesn't also pick an entry
+			g.stopAttractMode()
+			return true
+		}
+		return false
+	}
+
+	if g.InputActive {
+		g.menuIdleTime += deltaTime
+		if g.menuIdleTime >= inputIdleTimeout {
+			g.InputActive = false
+			g.menuIdleTime = 0
+		}
+		return false
+	}
+
+	if g.attractGame == nil {
+		g.menuIdleTime += deltaTime
+		if g.menuIdleTime < attractModeDelay {
+			return false
+		}
+		g.startAttractMode()
+	}
+
+	g.attractGame.update(deltaTime)
+	if g.attractGame.State != StateGameplay {
+		// Demo match ended - roll straight into another one
+		g.startAttractMode()
+	}
+	return true
+}
+
+// menuItemCount is the number of selectable main menu entries, counting
+// Continue when a saved game is waiting
+func (g *Game) menuItemCount() int {
+	if g.hasSave {
+		return 7
+	}
+	return 6
+}
+
+func (g *Game) handleMenuInput() {
+	menuItemCount := g.menuItemCount()
+	if g.MenuSelection >= menuItemCount {
+		g.MenuSelection = 0
+	}
+	step := g.menuStep()
+	if step < 0 {
+		g.MenuSelection--
+		if g.MenuSelection < 0 {
+			g.MenuSelection = menuItemCount - 1
+		}
+	}
+	if step > 0 {
+		g.MenuSelection++
+		if g.MenuSelection >= menuItemCount {
+			g.MenuSelection = 0
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyP) {
+		g.Physics = (g.Physics + 1) % physicsModeCount
+	}
+	if rl.IsKeyPressed(rl.KeyT) {
+		g.Mode = (g.Mode + 1) % gameModeCount
+	}
+	if rl.IsKeyPressed(rl.KeyZ) {
+		g.WorldSize = (g.WorldSize + 1) % len(worldSizes)
+	}
+	if rl.IsKeyPressed(rl.KeyO) {
+		g.Density = (g.Density + 1) % len(objectDensities)
+	}
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		g.Quit = true
+	}
+	if confirmPressed() {
+		g.MenuMessage = ""
+		selection := g.MenuSelection
+		if g.hasSave {
+			if selection == 0 {
+				g.continueSavedGame()
+				return
+			}
+			// The rest of the entries sit below Continue
+			selection--
+		}
+		switch selection {
+		case 0: // Single Player
+			g.initSinglePlayer()
+			g.State = StateGameplay
+		case 1: // Practice
+			g.initPractice()
+			g.State = StateGameplay
+		case 2: // Host Multiplayer
+			g.InputActive = true
+			g.InputTarget = InputHostPassword
+			g.InputText = ""
+		case 3: // Join Multiplayer
+			g.InputActive = true
+			g.InputTarget = InputServerIP
+			g.InputText = g.ServerIP
+		case 4: // Player name
+			g.InputActive = true
+			g.InputTarget = InputPlayerName
+			g.InputText = g.PlayerName
+		case 5: // Settings
+			g.State = StateSettings
+			g.SettingsChoice = 0
+		}
+	}
+}
+
+func (g *Game) handleLobbyInput() {
+	if g.ChatActive {
+		g.handleChatInput()
+		return
+	}
+	if g.actionPressed(ActionChat) {
+		g.ChatActive = true
+		g.chatInput = ""
+		return
+	}
+	if g.actionPressed(ActionReady) || confirmPressed() {
+		g.LobbyReady = !g.LobbyReady
+		if g.IsHost {
+			// Host can start game if minimum players reached
+			if g.networkPlayerCount()+1 >= g.MinPlayers && g.LobbyReady {
+				g.startGame()
+			}
+		}
+		g.sendLobbyUpdate()
+	}
+	if g.IsHost {
+		g.handleKickInput()
+		if rl.IsKeyPressed(rl.KeyG) {
+			g.Pace = (g.Pace + 1) % len(growthPaces)
+			g.sendLobbyUpdate()
+		}
+		// Rebuilding the map picks a new seed, which tells clients to rebuild theirs
+		if rl.IsKeyPressed(rl.KeyZ) {
+			g.WorldSize = (g.WorldSize + 1) % len(worldSizes)
+			g.resetMatch()
+			g.sendLobbyUpdate()
+		}
+		if rl.IsKeyPressed(rl.KeyO) {
+			g.Density = (g.Density + 1) % len(objectDensities)
+			g.resetMatch()
+			g.sendLobbyUpdate()
+		}
+		if rl.IsKeyPressed(rl.KeyU) {
+			g.Transport = (g.Transport + 1) % transportCount
+			g.sendLobbyUpdate()
+		}
+		if rl.IsKeyPressed(rl.KeyL) {
+			g.MatchDuration = (g.MatchDuration + 1) % len(matchDurations)
+			g.MaxGameTime = g.matchDuration()
+			g.sendLobbyUpdate()
+		}
+	}
+	if backPressed() {
+		g.leaveToMenu()
+	}
+}
+
+// Lobby chat keeps the last few lines of the current lobby session only
+const (
+	chatMaxLength = 60
+	chatHistory   = 8
+)
+
+// handleChatInput types into the lobby chat box; ENTER sends and ESC cancels
+func (g *Game) handleChatInput() {
+	key := rl.GetCharPressed()
+	for key > 0 {
+		if key >= 32 && key <= 125 && len(g.chatInput) < chatMaxLength {
+			g.chatInput += string(rune(key))
+		}
+		key = rl.GetCharPressed()
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(g.chatInput) > 0 {
+		g.chatInput = g.chatInput[:len(g.chatInput)-1]
+	}
+	if rl.IsKeyPressed(rl.KeyEnter) {
+		g.sendChat(g.chatInput)
+		g.ChatActive = false
+	}
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		g.ChatActive = false
+	}
+}
+
+// sendChat posts a line to the lobby; the host relays it to every client
+func (g *Game) sendChat(text string) {
+	chat := ChatMessage{Name: playerDisplayName(g.PlayerName, g.PlayerID), Text: sanitizeChat(text)}
+	if chat.Text == "" {
+		return
+	}
+	g.addChat(chat)
+	g.sendNetworkMessage(NetworkMessage{Type: "chat", PlayerID: g.PlayerID, Data: chat})
+}
+
+// addChat appends a line, dropping the oldest past chatHistory
+func (g *Game) addChat(chat ChatMessage) {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+
+	g.chatLog = append(g.chatLog, chat)
+	if len(g.chatLog) > chatHistory {
+		g.chatLog = g.chatLog[len(g.chatLog)-chatHistory:]
+	}
+}
+
+// sanitizeChat keeps printable ASCII, which is all the default font can draw,
+// and caps the length so a peer can't flood the lobby
+func sanitizeChat(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			b.WriteRune(r)
+		}
+		if b.Len() >= chatMaxLength {
+			break
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// handleKickInput lets the host pick a lobby row with the number keys and
+// remove that player with K
+func (g *Game) handleKickInput() {
+	for i := 1; i < g.MaxPlayers && i <= 9; i++ {
+		if rl.IsKeyPressed(rl.KeyOne + int32(i-1)) {
+			g.KickSelection = i
+		}
+	}
+
+	players := g.networkPlayersSnapshot()
+	if g.KickSelection > len(players) {
+		g.KickSelection = 0
+	}
+	if rl.IsKeyPressed(rl.KeyK) && g.KickSelection > 0 {
+		g.kickPlayer(players[g.KickSelection-1].ID)
+		g.KickSelection = 0
+	}
+}
+
+// sendToClient writes msg to one admitted client; players who have already
+// disconnected are skipped
+func (g *Game) sendToClient(id int, msg NetworkMessage) {
+	g.netMu.RLock()
+	conn := g.clientByID[id]
+	g.netMu.RUnlock()
+
+	if conn != nil {
+		conn.Write(encodeMessage(msg))
+	}
+}
+
+// kickPlayer tells a client it was kicked and closes its connection. The
+// client's handleClient goroutine then drops it from ClientConns and
+// NetworkPlayers and broadcasts player_leave.
+func (g *Game) kickPlayer(id int) {
+	g.netMu.RLock()
+	conn := g.clientByID[id]
+	g.netMu.RUnlock()
+
+	if conn == nil {
+		return
+	}
+	conn.Write(encodeMessage(NetworkMessage{Type: "kicked", PlayerID: g.PlayerID}))
+	conn.Close()
+}
+
+// hostGoneNotice is how long the host-disconnected notice stays up before
+// returning to the menu
+const hostGoneNotice = 3 * time.Second
+
+// handleDisconnect returns to the menu with the reason if the host turned us
+// away. If the host went away mid-session it first freezes the game under a
+// notice for hostGoneNotice, or until the player dismisses it.
+func (g *Game) handleDisconnect() bool {
+	g.netMu.Lock()
+	reason := g.dropReason
+	g.dropReason = ""
+	hostGone := g.hostGone
+	g.netMu.Unlock()
+
+	if hostGone {
+		if g.hostGoneAt.IsZero() {
+			g.hostGoneAt = time.Now()
+			// Don't leave the player stuck with a captured mouse under the notice
+			rl.EnableCursor()
+			return true
+		}
+		if time.Since(g.hostGoneAt) < hostGoneNotice && !backPressed() && !confirmPressed() {
+			return true
+		}
+		g.leaveToMenu()
+		g.MenuMessage = "Host disconnected"
+		return true
+	}
+
+	if reason == "" {
+		return false
+	}
+	g.leaveToMenu()
+	g.MenuMessage = reason
+	return true
+}
+
+// leaveToMenu drops back to the main menu, disconnecting from any server or
+// closing the lobby we host
+func (g *Game) leaveToMenu() {
+	// Return to menu
+	g.State = StateMenu
+	g.LobbyReady = false
+	g.GameStarted = false
+	// Release mouse cursor when returning to menu
+	enableCursor()
+	g.stopHeartbeat()
+	g.recording = nil // Only finished matches are kept as replays
+	g.ChatActive = false
+	g.hostGoneAt = time.Time{}
+	g.netMu.Lock()
+	g.chatLog = nil
+	g.hostGone = false
+	g.startPending = false
+	// Cleared first so the reader sees a deliberate close, not a drop
+	conn := g.ServerConn
+	g.ServerConn = nil
+	g.netMu.Unlock()
+	if conn != nil {
+		// Tell the host we're going rather than leave it to notice the closed socket
+		conn.Write(encodeMessage(NetworkMessage{Type: "player_leave", PlayerID: g.PlayerID}))
+		conn.Close()
+	}
+	if g.IsHost {
+		g.stopHosting()
+	}
+	if conn := g.udpConn; conn != nil {
+		g.udpConn = nil
+		conn.Close()
+	}
+}
+
+// pauseItems lists the pause menu entries; only single player can be saved
+func (g *Game) pauseItems() []string {
+	if g.inMultiplayer() {
+		return []string{"Resume", "Quit to Menu"}
+	}
+	return []string{"Resume", "Save & Quit", "Quit to Menu"}
+}
+
+func (g *Game) pause() {
+	g.State = StatePaused
+	g.PauseSelection = 0
+	enableCursor()
+}
+
+func (g *Game) resume() {
+	g.State = StateGameplay
+	g.captureCursor()
+}
+
+// captureCursor sets the cursor up for play: locked to the window and hidden,
+// or left visible for aiming when the player prefers it
+func (g *Game) captureCursor() {
+	if g.Settings.ShowCursor {
+		enableCursor()
+		return
+	}
+	disableCursor()
+}
+
+func (g *Game) handlePauseInput() {
+	if backPressed() {
+		g.resume()
+		return
+	}
+	items := g.pauseItems()
+	pauseItemCount := len(items)
+	step := g.menuStep()
+	if step < 0 {
+		g.PauseSelection--
+		if g.PauseSelection < 0 {
+			g.PauseSelection = pauseItemCount - 1
+		}
+	}
+	if step > 0 {
+		g.PauseSelection++
+		if g.PauseSelection >= pauseItemCount {
+			g.PauseSelection = 0
+		}
+	}
+	if confirmPressed() {
+		switch items[g.PauseSelection] {
+		case "Resume":
+			g.resume()
+		case "Save & Quit":
+			g.saveAndQuit()
+		case "Quit to Menu":
+			g.leaveToMenu()
+		}
+	}
+}
+
+// heartbeatInterval is how often idle peers announce they are still connected;
+// it has to stay well under the 5 second LastSeen timeout
+const heartbeatInterval = 2 * time.Second
+
+// startHeartbeat sends a heartbeat every heartbeatInterval so players sitting
+// still in the lobby aren't pruned. Clients send to conn; the host (nil conn)
+// broadcasts to every client.
+func (g *Game) startHeartbeat(conn net.Conn) {
+	g.stopHeartbeat()
+
+	stop := make(chan struct{})
+	g.netMu.Lock()
+	g.heartbeatStop = stop
+	g.netMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		msg := NetworkMessage{Type: "heartbeat", PlayerID: g.PlayerID}
+		data := encodeMessage(msg)
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !g.heartbeatActive.Load() {
+					continue
+				}
+				// Piggyback a ping on every heartbeat to keep RTTs fresh
+				ping := NetworkMessage{
+					Type:     "ping",
+					PlayerID: g.PlayerID,
+					Data:     Ping{SentAt: time.Now().UnixNano()},
+				}
+				if conn == nil {
+					g.broadcastMessage(msg)
+					g.broadcastMessage(ping)
+				} else if _, err := conn.Write(data); err != nil {
+					return
+				} else {
+					conn.Write(encodeMessage(ping))
+				}
+			}
+		}
+	}()
+}
+
+// stopHeartbeat ends the heartbeat goroutine if one is running
+func (g *Game) stopHeartbeat() {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+
+	if g.heartbeatStop != nil {
+		close(g.heartbeatStop)
+		g.heartbeatStop = nil
+	}
+}
+
+// pruneStalePlayers drops remote players that haven't been heard from recently
+func (g *Game) pruneStalePlayers() {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+
+	for id, player := range g.NetworkPlayers {
+		if time.Since(player.LastSeen) > 5*time.Second {
+			delete(g.NetworkPlayers, id)
+		}
+	}
+}
+
+// syncWorldSeed adopts the host's match settings and regenerates the object field
+// when the host has announced a different layout, so every client plays the
+// same match on the same map
+func (g *Game) syncWorldSeed() {
+	g.netMu.RLock()
+	seed := g.hostSeed
+	if seed != 0 {
+		g.Mode = g.hostMode
+		g.TargetScore = g.hostTargetScore
+		g.Pace = g.hostPace
+		g.Physics = g.hostPhysics
+		g.WorldSize = g.hostWorldSize
+		g.Density = g.hostDensity
+		g.MatchDuration = g.hostDuration
+		g.Transport = g.hostTransport
+	}
+	g.netMu.RUnlock()
+
+	if seed == 0 || seed == g.WorldSeed {
+		return
+	}
+	// The host picks a new seed whenever it changes the map size, so the
+	// dimensions only need refreshing along with the layout
+	g.WorldSeed = seed
+	g.applyWorldSize()
+	g.generateObjects(seed)
+	g.resetConsumption()
+}
+
+// resetConsumption forgets queued and predicted eats; indices on the old
+// layout mean nothing on a new one
+func (g *Game) resetConsumption() {
+	g.netMu.Lock()
+	g.remoteEaten = nil
+	g.eatRequests = nil
+	g.eatDenied = nil
+	g.swallowed = nil
+	g.killClaims = nil
+	g.netMu.Unlock()
+	g.pendingEats = make(map[int]pendingEat)
+}
+
+func (g *Game) sendLobbyUpdate() {
+	update := LobbyUpdate{
+		PlayerCount: g.networkPlayerCount() + 1,
+		GameStarted: g.GameStarted,
+		HostReady:   g.LobbyReady,
+		Name:        playerDisplayName(g.PlayerName, g.PlayerID),
+	}
+	if g.IsHost {
+		update.ServerIP = g.LocalIP + ":8080"
+		update.WorldSeed = g.WorldSeed
+		update.Mode = g.Mode
+		update.TargetScore = g.TargetScore
+		update.Pace = g.Pace
+		update.Physics = g.Physics
+		update.WorldSize = g.WorldSize
+		update.Density = g.Density
+		update.Duration = g.MatchDuration
+		update.Transport = g.Transport
+	} else {
+		update.Password = g.RoomPassword
+	}
+
+	msg := NetworkMessage{
+		Type:     "lobby_update",
+		PlayerID: g.PlayerID,
+		Data:     update,
+	}
+
+	if g.IsHost {
+		// Send to all clients
+		g.broadcastMessage(msg)
+	} else if conn := g.serverConn(); conn != nil {
+		// Send to server
+		conn.Write(encodeMessage(msg))
+	}
+}
+
+func (g *Game) startGame() {
+	g.GameStarted = true
+	g.State = StateGameplay
+	g.GameTime = 0
+	g.lastMealTime = 0
+
+	g.MaxGameTime = g.matchDuration()
+
+	startAt := time.Now().Add(matchCountdown)
+	g.netMu.Lock()
+	g.hostStartAt = startAt
+	g.hostMatchTime = g.MaxGameTime
+	g.netMu.Unlock()
+	g.scheduleMatchStart(startAt)
+
+	g.captureCursor()
+
+	g.sendLobbyUpdate()
+	g.broadcastMessage(g.gameStartMessage())
+}
+
+// gameStartMessage announces the host's match start time (host only)
+func (g *Game) gameStartMessage() NetworkMessage {
+	g.netMu.RLock()
+	startAt := g.hostStartAt
+	matchTime := g.hostMatchTime
+	g.netMu.RUnlock()
+
+	return NetworkMessage{
+		Type:     "game_start",
+		PlayerID: g.PlayerID,
+		Data: GameStart{
+			StartAt:     startAt.UnixMilli(),
+			SentAt:      time.Now().UnixMilli(),
+			MaxGameTime: matchTime,
+		},
+	}
+}
+
+// scheduleMatchStart holds gameplay at the start line until startAt
+func (g *Game) scheduleMatchStart(startAt time.Time) {
+	g.netMu.Lock()
+	g.matchStartAt = startAt
+	g.netMu.Unlock()
+}
+
+// countdownRemaining returns how long until a scheduled match start, or 0
+func (g *Game) countdownRemaining() time.Duration {
+	g.netMu.RLock()
+	defer g.netMu.RUnlock()
+	if g.matchStartAt.IsZero() {
+		return 0
+	}
+	if remaining := time.Until(g.matchStartAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// waitForMatchStart reports whether the start countdown is still running. It
+// moves a waiting lobby into gameplay once the host has started, adopts the
+// host's match length, and once the countdown ends, GameTime is snapped to the
+// time since the shared start, which also lines late joiners up with the
+// host's clock.
+func (g *Game) waitForMatchStart() bool {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+	if g.startPending {
+		g.startPending = false
+		if g.State == StateLobby {
+			g.State = StateGameplay
+			g.GameTime = 0
+			g.lastMealTime = 0
+		}
+	}
+	if g.matchStartAt.IsZero() {
+		return false
+	}
+	if g.hostMatchTime > 0 {
+		g.MaxGameTime = g.hostMatchTime
+	}
+	elapsed := time.Since(g.matchStartAt)
+	if elapsed < 0 {
+		return true
+	}
+	g.GameTime = float32(elapsed.Seconds())
+	g.lastMealTime = g.GameTime
+	g.matchStartAt = time.Time{}
+	return false
+}
+
+// inputBoxRect is where the menu text box is drawn
+func inputBoxRect() rl.Rectangle {
+	return rl.Rectangle{X: float32(screenWidth/2 - 150), Y: 513, Width: 300, Height: 40}
+}
+
+func (g *Game) handleTextInput() {
+	// Clicking anywhere else gives up on the box like ESC does
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) && !rl.CheckCollisionPointRec(rl.GetMousePosition(), inputBoxRect()) {
+		g.InputActive = false
+		return
+	}
+
+	maxLength := 20
+	if g.InputTarget == InputPlayerName {
+		maxLength = maxPlayerNameLength
+	}
+
+	key := rl.GetCharPressed()
+	for key > 0 {
+		if key >= 32 && key <= 125 && len(g.InputText) < maxLength {
+			g.InputText += string(rune(key))
+		}
+		key = rl.GetCharPressed()
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(g.InputText) > 0 {
+		g.InputText = g.InputText[:len(g.InputText)-1]
+	}
+	if confirmPressed() {
+		g.MenuMessage = ""
+		switch g.InputTarget {
+		case InputPlayerName:
+			g.PlayerName = strings.TrimSpace(g.InputText)
+		case InputHostPassword:
+			g.RoomPassword = g.InputText
+			if err := g.startServer(); err != nil {
+				fmt.Printf("Failed to start server: %v\n", err)
+				g.InputActive = false
+				g.MenuMessage = "Could not start server: " + err.Error()
+				return
+			}
+			g.prepareMatch()
+			g.State = StateLobby
+		case InputJoinPassword:
+			g.RoomPassword = g.InputText
+			g.connectToServer()
+		default:
+			addr, err := normalizeServerAddr(g.InputText)
+			if err != nil {
+				// Leave the box open so the typo can be fixed
+				g.MenuMessage = err.Error()
+				return
+			}
+			// Ask for the room password next; blank joins an open lobby
+			g.ServerIP = addr
+			g.InputTarget = InputJoinPassword
+			g.InputText = ""
+			return
+		}
+		g.InputActive = false
+	}
+	if backPressed() {
+		g.InputActive = false
+	}
+}
+
+// defaultMaxPlayers matches the size of the lobby color palette
+const defaultMaxPlayers = 6
+
+// startServer opens the lobby to clients on port 8080. It listens before
+// returning, so the main loop knows straight away whether it is hosting.
+func (g *Game) startServer() error {
+	listener, err := net.Listen("tcp", ":8080")
+	if err != nil {
+		return err
+	}
+	fmt.Println("Server started on :8080")
+	g.IsHost = true
+	g.netMu.Lock()
+	g.listener = listener
+	g.netMu.Unlock()
+	g.startHeartbeat(nil)
+
+	// Positions can come in over UDP on the same port number
+	if udpConn, err := net.ListenUDP("udp", &net.UDPAddr{Port: 8080}); err != nil {
+		fmt.Printf("Failed to open UDP port, positions stay on TCP: %v\n", err)
+	} else {
+		g.udpConn = udpConn
+		go g.readUDP(udpConn)
+	}
+
+	go g.acceptClients(listener)
+	return nil
+}
+
+// stopHosting closes the lobby: clients are told the host left and hung up
+// on, and the game forgets them so it can play single player again
+func (g *Game) stopHosting() {
+	g.broadcastMessage(NetworkMessage{Type: "host_left", PlayerID: g.PlayerID})
+
+	g.netMu.Lock()
+	listener, conns := g.listener, g.ClientConns
+	g.listener = nil
+	g.ClientConns = nil
+	g.clientByID = make(map[int]net.Conn)
+	g.udpPeers = make(map[int]*net.UDPAddr)
+	g.NetworkPlayers = make(map[int]*NetworkPlayer)
+	g.lateJoiners = nil
+	g.netMu.Unlock()
+
+	if listener != nil {
+		listener.Close()
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if g.udpConn != nil {
+		g.udpConn.Close()
+		g.udpConn = nil
+	}
+	g.IsHost = false
+}
+
+// acceptClients hands each connection on listener to handleClient with a
+// reserved lobby slot. Without a free slot the connection is still heard out,
+// since it may be a player reconnecting before we noticed them drop.
+func (g *Game) acceptClients(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		go g.handleClient(conn, g.reserveSlot())
+	}
+}
+
+// dialUDP opens the client's UDP socket to the host, used for positions when
+// the host picks the hybrid transport. Without one, positions stay on TCP.
+func (g *Game) dialUDP() {
+	addr, err := net.ResolveUDPAddr("udp", g.ServerIP)
+	if err == nil {
+		var conn *net.UDPConn
+		if conn, err = net.DialUDP("udp", nil, addr); err == nil {
+			g.udpConn = conn
+			go g.readUDP(conn)
+			return
+		}
+	}
+	fmt.Printf("Failed to open UDP socket, positions stay on TCP: %v\n", err)
+}
+
+// connectTimeout bounds the whole dial, DNS included, so a bad address fails fast
+const connectTimeout = 5 * time.Second
+
+// normalizeServerAddr turns what was typed into the IP box into a host:port,
+// adding the default port when none is given
+func normalizeServerAddr(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("Enter the host's IP address")
+	}
+
+	host, port, err := net.SplitHostPort(input)
+	if err != nil {
+		// No port (or a bare IPv6 address): use the default one
+		host, port = strings.Trim(input, "[]"), "8080"
+	}
+	if host == "" || strings.ContainsAny(host, " /\\") {
+		return "", fmt.Errorf("Invalid address: %s", input)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("Invalid port: %s", port)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+func (g *Game) connectToServer() {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+		defer cancel()
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", g.ServerIP)
+		if err != nil {
+			fmt.Printf("Failed to connect to server: %v\n", err)
+			// Shown on the menu by the main loop
+			g.netMu.Lock()
+			g.dropReason = "Could not connect to " + g.ServerIP
+			g.netMu.Unlock()
+			return
+		}
+		// The main loop owns the game state, so it takes it from here
+		g.netMu.Lock()
+		g.dialedConn = conn
+		g.netMu.Unlock()
+	}()
+}
+
+// joinDialedServer moves into the lobby over the connection connectToServer
+// opened, if one is waiting. It runs on the main loop; a connection that
+// finishes after the player has left the menu is hung up instead.
+func (g *Game) joinDialedServer() {
+	g.netMu.Lock()
+	conn := g.dialedConn
+	g.dialedConn = nil
+	join := conn != nil && g.State == StateMenu
+	if join {
+		g.ServerConn = conn
+	}
+	g.netMu.Unlock()
+	if conn == nil {
+		return
+	}
+	if !join {
+		conn.Close()
+		return
+	}
+
+	g.prepareMatch()
+	g.State = StateLobby
+	g.startHeartbeat(conn)
+	go g.handleServerMessages(conn)
+	g.dialUDP()
+	// Announce ourselves; the host admits us on this first message
+	g.sendLobbyUpdate()
+}
+
+// joinTimeout is how long a new connection may hold a lobby slot before
+// saying who it is
+const joinTimeout = 10 * time.Second
+
+// handleClient serves one client connection. It holds any slot reserved by
+// acceptClients until the client is admitted or goes away.
+func (g *Game) handleClient(conn net.Conn, reserved bool) {
+	// The first message tells us which player is on the other end
+	clientID := -1
+	rejected := false
+	defer func() {
+		if reserved {
+			g.releaseSlot()
+		}
+	}()
+	conn.SetReadDeadline(time.Now().Add(joinTimeout))
+
+	readMessages(conn, func(msg NetworkMessage) {
+		if rejected {
+			return
+		}
+		if clientID == -1 {
+			if !g.joinAllowed(msg) {
+				rejected = true
+				g.rejectJoin(conn, "Wrong password")
+				return
+			}
+			// Only admitted clients receive broadcasts
+			if !g.admitClient(conn, msg.PlayerID, reserved) {
+				rejected = true
+				g.rejectJoin(conn, "Lobby full")
+				return
+			}
+			clientID = msg.PlayerID
+			reserved = false
+			conn.SetReadDeadline(time.Time{})
+			// Send lobby state to the new client
+			g.sendLobbyUpdate()
+			if g.GameStarted {
+				// Joined mid-match: hand over the start time so their clock matches ours
+				conn.Write(encodeMessage(g.gameStartMessage()))
+			}
+			// What's been eaten so far is sent by the main loop, which owns Objects
+			g.netMu.Lock()
+			g.lateJoiners = append(g.lateJoiners, clientID)
+			g.netMu.Unlock()
+		}
+		// A connection only ever speaks for the player it was admitted as, so
+		// nobody can eat, kill, chat or leave in someone else's name
+		if msg.PlayerID != clientID {
+			return
+		}
+		if msg.Type == "player_leave" {
+			// Leaving on purpose: hang up so the cleanup below tells everyone now
+			conn.Close()
+			return
+		}
+		g.processNetworkMessage(msg)
+		// Broadcast lobby updates to all clients when someone joins
+		if msg.Type == "lobby_update" {
+			g.sendLobbyUpdate()
+		}
+	})
+	conn.Close()
+	g.removeClientConn(conn)
+
+	// Tell everyone else right away instead of waiting for the LastSeen timeout
+	if clientID != -1 {
+		g.netMu.Lock()
+		// A client that already reconnected owns the ID now; leave it be
+		current := g.clientByID[clientID] == conn
+		if current {
+			delete(g.NetworkPlayers, clientID)
+			delete(g.clientByID, clientID)
+			delete(g.udpPeers, clientID)
+		}
+		g.netMu.Unlock()
+		if current {
+			g.broadcastMessage(NetworkMessage{Type: "player_leave", PlayerID: clientID})
+			// Keep everyone's player count right
+			g.sendLobbyUpdate()
+		}
+	}
+}
+
+// joinAllowed checks a new client's first message against the room password.
+// An empty password keeps the lobby open to anyone.
+func (g *Game) joinAllowed(msg NetworkMessage) bool {
+	if g.RoomPassword == "" {
+		return true
+	}
+	if msg.Type != "lobby_update" {
+		return false
+	}
+	data, _ := json.Marshal(msg.Data)
+	var update LobbyUpdate
+	if err := json.Unmarshal(data, &update); err != nil {
+		return false
+	}
+	return update.Password == g.RoomPassword
+}
+
+// reserveSlot claims a lobby slot for a connection that hasn't introduced
+// itself yet, or reports false when the host, counted as a player, has none
+// left. Counting pending connections keeps several joining at once from
+// overfilling the lobby. Disconnected clients are removed from ClientConns,
+// freeing their slot.
+func (g *Game) reserveSlot() bool {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+	if len(g.ClientConns)+g.pendingJoins+1 >= g.MaxPlayers {
+		return false
+	}
+	g.pendingJoins++
+	return true
+}
+
+// admitClient adds conn to the lobby as player id. A player redialing from
+// the same machine takes over their old connection, which we may not have
+// noticed is dead yet, along with its slot; that works even in a full lobby.
+// Anyone else needs a reserved slot, which becomes theirs. It reports false
+// when conn has no slot to take.
+func (g *Game) admitClient(conn net.Conn, id int, reserved bool) bool {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+
+	old := g.clientByID[id]
+	rejoin := old != nil && remoteIP(old).Equal(remoteIP(conn))
+	if !reserved && !rejoin {
+		return false
+	}
+	if rejoin {
+		for i, c := range g.ClientConns {
+			if c == old {
+				g.ClientConns = append(g.ClientConns[:i], g.ClientConns[i+1:]...)
+				break
+			}
+		}
+		old.Close()
+	}
+	if reserved {
+		g.pendingJoins--
+	}
+	g.ClientConns = append(g.ClientConns, conn)
+	g.clientByID[id] = conn
+	return true
+}
+
+// remoteIP returns the address a TCP connection comes from, or nil
+func remoteIP(conn net.Conn) net.IP {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+// releaseSlot gives back a slot reserved for a connection that never joined
+func (g *Game) releaseSlot() {
+	g.netMu.Lock()
+	g.pendingJoins--
+	g.netMu.Unlock()
+}
+
+// rejectJoin tells a connecting client why it was turned away and hangs up
+func (g *Game) rejectJoin(conn net.Conn, reason string) {
+	conn.Write(encodeMessage(NetworkMessage{
+		Type:     "join_rejected",
+		PlayerID: g.PlayerID,
+		Data:     JoinRejected{Reason: reason},
+	}))
+	conn.Close()
+}
+
+// removeClientConn drops a connection from the host's client list
+func (g *Game) removeClientConn(conn net.Conn) {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+
+	for i, c := range g.ClientConns {
+		if c == conn {
+			g.ClientConns = append(g.ClientConns[:i], g.ClientConns[i+1:]...)
+			return
+		}
+	}
+}
+
+// broadcastMessage sends a message to every connected client (host only).
+// Connections that fail to accept the write are closed and pruned.
+func (g *Game) broadcastMessage(msg NetworkMessage) {
+	data := encodeMessage(msg)
+
+	// Write to a copy so slow sockets don't hold the lock
+	g.netMu.RLock()
+	conns := append([]net.Conn(nil), g.ClientConns...)
+	g.netMu.RUnlock()
+
+	var dead []net.Conn
+	for _, conn := range conns {
+		if _, err := conn.Write(data); err != nil {
+			dead = append(dead, conn)
+		}
+	}
+
+	for _, conn := range dead {
+		conn.Close()
+		g.removeClientConn(conn)
+	}
+}
+
+// broadcastPosition sends a player_update (data is msg as JSON) to every
+// client but its sender: over UDP to clients whose address we know, over TCP
+// to any that haven't sent us a datagram yet
+func (g *Game) broadcastPosition(msg NetworkMessage, data []byte) {
+	g.netMu.RLock()
+	var tcp []net.Conn
+	var udp []*net.UDPAddr
+	for id, conn := range g.clientByID {
+		if id == msg.PlayerID {
+			continue
+		}
+		if addr := g.udpPeers[id]; addr != nil {
+			udp = append(udp, addr)
+		} else {
+			tcp = append(tcp, conn)
+		}
+	}
+	g.netMu.RUnlock()
+
+	for _, addr := range udp {
+		g.udpConn.WriteToUDP(data, addr)
+	}
+	for _, conn := range tcp {
+		conn.Write(encodeMessage(msg))
+	}
+}
+
+// readUDP takes player updates off a UDP socket until it is closed. The host
+// only accepts datagrams from admitted clients, sent from the address they
+// joined from, and passes each one on to the other clients.
+func (g *Game) readUDP(conn *net.UDPConn) {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		var update udpUpdate
+		if err := json.Unmarshal(buf[:n], &update); err != nil || update.Type != "player_update" {
+			continue
+		}
+
+		if g.IsHost {
+			g.netMu.Lock()
+			client := g.clientByID[update.PlayerID]
+			allowed := client != nil && sameHost(client.RemoteAddr(), addr)
+			if allowed {
+				g.udpPeers[update.PlayerID] = addr
+			}
+			g.netMu.Unlock()
+			if !allowed {
+				continue
+			}
+		}
+
+		// Datagrams can arrive out of order; an older position isn't passed on
+		if g.applyPlayerUpdate(update.PlayerID, update.Data) && g.IsHost {
+			msg := NetworkMessage{Type: update.Type, PlayerID: update.PlayerID, Data: update.Data}
+			g.broadcastPosition(msg, buf[:n])
+		}
+	}
+}
+
+// sameHost reports whether a UDP datagram came from the machine behind a TCP address
+func sameHost(tcp net.Addr, udp *net.UDPAddr) bool {
+	addr, ok := tcp.(*net.TCPAddr)
+	return ok && addr.IP.Equal(udp.IP)
+}
+
+// Clients that lose the host redial it a few times, keeping their ID and score
+const (
+	defaultReconnectTries = 5
+	defaultReconnectDelay = 500 * time.Millisecond
+)
+
+func (g *Game) handleServerMessages(conn net.Conn) {
+	for {
+		readMessages(conn, g.processNetworkMessage)
+
+		// Left on purpose, or the host told us why it hung up or that it quit
+		g.netMu.RLock()
+		dismissed := g.dropReason != "" || g.hostGone
+		g.netMu.RUnlock()
+		if g.serverConn() != conn || dismissed {
+			return
+		}
+
+		next := g.reconnect(conn)
+		if next == nil {
+			g.netMu.Lock()
+			if g.ServerConn == conn {
+				g.hostGone = true
+			}
+			g.netMu.Unlock()
+			return
+		}
+		conn = next
+	}
+}
+
+// reconnect redials the host with exponential backoff after old dropped. It
+// returns the new connection, or nil if every attempt failed or the player
+// left in the meantime.
+func (g *Game) reconnect(old net.Conn) net.Conn {
+	g.reconnecting.Store(true)
+	defer g.reconnecting.Store(false)
+	g.stopHeartbeat()
+
+	delay := g.ReconnectDelay
+	for attempt := 0; attempt < g.ReconnectTries; attempt++ {
+		time.Sleep(delay)
+		if g.serverConn() != old {
+			return nil
+		}
+		conn, err := net.DialTimeout("tcp", g.ServerIP, delay)
+		delay *= 2
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			// Nothing listening any more: the host quit rather than dropped out
+			fmt.Printf("Host is no longer running: %v\n", err)
+			return nil
+		}
+		if err != nil {
+			fmt.Printf("Reconnect attempt %d failed: %v\n", attempt+1, err)
+			continue
+		}
+
+		// The player may have left while we were dialing
+		g.netMu.Lock()
+		current := g.ServerConn == old
+		if current {
+			g.ServerConn = conn
+		}
+		g.netMu.Unlock()
+		if !current {
+			conn.Close()
+			return nil
+		}
+
+		// Same PlayerID, so the host picks us back up where we left off. Only
+		// settings fixed for the session go in; the rest belongs to the main loop.
+		conn.Write(encodeMessage(NetworkMessage{
+			Type:     "lobby_update",
+			PlayerID: g.PlayerID,
+			Data:     LobbyUpdate{Name: playerDisplayName(g.PlayerName, g.PlayerID), Password: g.RoomPassword},
+		}))
+		g.startHeartbeat(conn)
+		return conn
+	}
+	return nil
+}
+
+// Messages travel as newline-delimited JSON: one NetworkMessage per line, each
+// sent with a single Write so concurrent senders never interleave partial lines.
+
+// maxMessageSize bounds one line; anything longer means the stream is broken
+const maxMessageSize = 64 * 1024
+
+// encodeMessage frames msg as a single line of JSON
+func encodeMessage(msg NetworkMessage) []byte {
+	data, _ := json.Marshal(msg)
+	return append(data, '\n')
+}
+
+// readMessages hands each line of r to handle until the stream ends or breaks.
+// Blank lines and lines that aren't valid JSON are skipped without losing sync.
+func readMessages(r io.Reader, handle func(NetworkMessage)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxMessageSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg NetworkMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+		handle(msg)
+	}
+	return scanner.Err()
+}
+
+// applyPlayerUpdate moves player id's hole to where update says it is. It
+// reports false for bogus updates and for ones no newer than the last applied,
+// which can turn up late after a reconnect or over UDP.
+func (g *Game) applyPlayerUpdate(id int, update PlayerUpdate) bool {
+	g.netMu.Lock()
+	defer g.netMu.Unlock()
+	if err := sanitizePlayerUpdate(&update, g.netWorldSize.X, g.netWorldSize.Y); err != nil {
+		fmt.Printf("Dropping player_update from %d: %v\n", id, err)
+		return false
+	}
+	if g.NetworkPlayers[id] == nil {
+		colors := []rl.Color{rl.Red, rl.Blue, rl.Green, rl.Yellow, rl.Purple, rl.Orange}
+		g.NetworkPlayers[id] = &NetworkPlayer{
+			ID:    id,
+			Color: colors[id%len(colors)],
+		}
+	}
+	player := g.NetworkPlayers[id]
+	if update.Seq <= player.lastSeq {
+		return false
+	}
+	player.lastSeq = update.Seq
+	player.Name = playerDisplayName(update.Name, id)
+	now := time.Now()
+	if !player.LastUpdate.IsZero() {
+		player.PrevPosition = player.Hole.Position
+		player.PrevUpdate = player.LastUpdate
+	}
+	player.LastUpdate = now
+	player.Hole.Position = update.Position
+	player.Hole.Size = update.Size
+	player.Hole.Score = update.Score
+	player.Hole.Animation = update.Animation
+	player.BiggestCatch = update.Catch
+	player.ObjectsEaten = update.Eaten
+	player.LastSeen = time.Now()
+	return true
+}
+
+func (g *Game) processNetworkMessage(msg NetworkMessage) {
+	switch msg.Type {
+	case "player_update":
+		data, _ := json.Marshal(msg.Data)
+		var update PlayerUpdate
+		if err := json.Unmarshal(data, &update); err != nil {
+			return
+		}
+		g.applyPlayerUpdate(msg.PlayerID, update)
+	case "lobby_update":
+		data, _ := json.Marshal(msg.Data)
+		var update LobbyUpdate
+		json.Unmarshal(data, &update)
+		// Add player to lobby if not already present
+		g.netMu.Lock()
+		if g.NetworkPlayers[msg.PlayerID] == nil {
+			colors := []rl.Color{rl.Red, rl.Blue, rl.Green, rl.Yellow, rl.Purple, rl.Orange}
+			g.NetworkPlayers[msg.PlayerID] = &NetworkPlayer{
+				ID:    msg.PlayerID,
+				Color: colors[msg.PlayerID%len(colors)],
+				LastSeen: time.Now(),
+			}
+		} else {
+			g.NetworkPlayers[msg.PlayerID].LastSeen = time.Now()
+		}
+		g.NetworkPlayers[msg.PlayerID].Name = playerDisplayName(update.Name, msg.PlayerID)
+		if update.WorldSeed != 0 && !g.IsHost {
+			// Applied by the main loop so Objects isn't rebuilt under its feet
+			g.hostSeed = update.WorldSeed
+			g.hostMode = update.Mode
+			g.hostTargetScore = update.TargetScore
+			g.hostPace = update.Pace
+			if update.Physics >= 0 && update.Physics < physicsModeCount {
+				g.hostPhysics = update.Physics
+			}
+			g.hostWorldSize = update.WorldSize
+			g.hostDensity = update.Density
+			g.hostDuration = update.Duration
+			g.hostTransport = update.Transport
+		}
+		// If game started, the main loop moves us to gameplay in waitForMatchStart
+		if update.GameStarted {
+			g.startPending = true
+		}
+		g.netMu.Unlock()
+	case "power_up":
+		data, _ := json.Marshal(msg.Data)
+		var pickup PowerUpPickup
+		if err := json.Unmarshal(data, &pickup); err != nil || !pickup.Kind.valid() {
+			return
+		}
+		if g.IsHost {
+			// Clients only talk to the host, so pass it on to everyone else
+			g.broadcastMessage(msg)
+		}
+		if pickup.Duration > powerUpDuration {
+			pickup.Duration = powerUpDuration
+		}
+		g.netMu.Lock()
+		if player := g.NetworkPlayers[msg.PlayerID]; player != nil {
+			player.PowerUp = pickup.Kind
+			player.PowerUpUntil = time.Now().Add(time.Duration(pickup.Duration * float32(time.Second)))
+		}
+		g.netMu.Unlock()
+	case "world_state":
+		data, _ := json.Marshal(msg.Data)
+		var state WorldState
+		if err := json.Unmarshal(data, &state); err != nil || g.IsHost {
+			return
+		}
+		// Applied by the main loop once it has built the field from the same seed
+		g.netMu.Lock()
+		g.joinState = &state
+		g.netMu.Unlock()
+	case "round_reset":
+		data, _ := json.Marshal(msg.Data)
+		var reset RoundReset
+		if err := json.Unmarshal(data, &reset); err != nil || g.IsHost {
+			return
+		}
+		// Applied by the main loop, which owns Objects and the game state
+		g.netMu.Lock()
+		g.hostSeed = reset.WorldSeed
+		g.roundReset = true
+		g.netMu.Unlock()
+	case "sudden_death":
+		data, _ := json.Marshal(msg.Data)
+		var call SuddenDeath
+		if err := json.Unmarshal(data, &call); err != nil || g.IsHost {
+			return
+		}
+		// Applied by the main loop, which owns the game state
+		g.netMu.Lock()
+		g.hostSuddenDeath = &call
+		g.netMu.Unlock()
+	case "sudden_death_over":
+		data, _ := json.Marshal(msg.Data)
+		var over SuddenDeathOver
+		if err := json.Unmarshal(data, &over); err != nil || g.IsHost {
+			return
+		}
+		g.netMu.Lock()
+		g.hostSuddenDeathOver = &over
+		g.netMu.Unlock()
+	case "game_start":
+		data, _ := json.Marshal(msg.Data)
+		var start GameStart
+		if err := json.Unmarshal(data, &start); err != nil {
+			return
+		}
+		// Rebase onto our clock using only the host's own timestamps. Late
+		// joiners get a start in the past and skip straight to the host's time.
+		// Ignore lengths we don't offer and keep the lobby's choice instead;
+		// waitForMatchStart adopts the length on the main loop
+		matchTime := start.MaxGameTime
+		if !validMatchDuration(matchTime) {
+			matchTime = 0
+		}
+		g.netMu.Lock()
+		g.hostMatchTime = matchTime
+		g.startPending = true
+		g.netMu.Unlock()
+		g.scheduleMatchStart(time.Now().Add(time.Duration(start.StartAt-start.SentAt) * time.Millisecond))
+	case "join_rejected":
+		data, _ := json.Marshal(msg.Data)
+		var rejected JoinRejected
+		json.Unmarshal(data, &rejected)
+		if rejected.Reason == "" {
+			rejected.Reason = "Join rejected"
+		}
+		// Picked up by the lobby on the main loop, which leaves to the menu
+		g.netMu.Lock()
+		g.dropReason = rejected.Reason
+		g.netMu.Unlock()
+	case "kicked":
+		g.netMu.Lock()
+		g.dropReason = "You were kicked"
+		g.netMu.Unlock()
+	case "host_left":
+		// The host quit on purpose, so there's nobody to redial
+		if !g.IsHost && msg.PlayerID != g.PlayerID {
+			g.netMu.Lock()
+			g.hostGone = true
+			g.netMu.Unlock()
+		}
+	case "heartbeat":
+		// Only refresh known players; lobby/player updates introduce new ones
+		g.netMu.Lock()
+		if player := g.NetworkPlayers[msg.PlayerID]; player != nil {
+			player.LastSeen = time.Now()
+		}
+		g.netMu.Unlock()
+	case "ping":
+		// Echo the timestamp back to whoever asked
+		pong := NetworkMessage{Type: "pong", PlayerID: g.PlayerID, Data: msg.Data}
+		if g.IsHost {
+			g.sendToClient(msg.PlayerID, pong)
+		} else {
+			g.sendNetworkMessage(pong)
+		}
+	case "pong":
+		data, _ := json.Marshal(msg.Data)
+		var ping Ping
+		if err := json.Unmarshal(data, &ping); err != nil || ping.SentAt == 0 {
+			return
+		}
+		rtt := time.Since(time.Unix(0, ping.SentAt))
+		if !g.IsHost {
+			g.hostRTT.Store(int64(rtt))
+		}
+		// The player may have left while the ping was in flight
+		g.netMu.Lock()
+		if player := g.NetworkPlayers[msg.PlayerID]; player != nil {
+			player.RTT = rtt
+		}
+		g.netMu.Unlock()
+	case "chat":
+		if msg.PlayerID == g.PlayerID {
+			// Our own line echoed back by the host
+			return
+		}
+		data, _ := json.Marshal(msg.Data)
+		var chat ChatMessage
+		if err := json.Unmarshal(data, &chat); err != nil {
+			return
+		}
+		chat.Name = sanitizeChat(chat.Name)
+		chat.Text = sanitizeChat(chat.Text)
+		if chat.Text == "" {
+			return
+		}
+		if g.IsHost {
+			// Clients only talk to the host, so pass it on to everyone else
+			g.broadcastMessage(NetworkMessage{Type: "chat", PlayerID: msg.PlayerID, Data: chat})
+		}
+		g.addChat(chat)
+	case "player_leave":
+		// Remove the departed hole immediately so it doesn't linger as a ghost
+		g.netMu.Lock()
+		delete(g.NetworkPlayers, msg.PlayerID)
+		g.netMu.Unlock()
+	case "eat_request", "object_eaten", "eat_denied":
+		data, _ := json.Marshal(msg.Data)
+		var eaten ObjectEaten
+		if err := json.Unmarshal(data, &eaten); err != nil {
+			return
+		}
+		// Objects belongs to the main loop, which picks these up
+		g.netMu.Lock()
+		switch {
+		case msg.Type == "eat_request" && g.IsHost:
+			// handleClient only passes on messages in the connection's own
+			// name, so the sender is the eater, whatever the payload claims
+			eaten.EaterID = msg.PlayerID
+			g.eatRequests = append(g.eatRequests, eaten)
+		case msg.Type == "object_eaten" && !g.IsHost:
+			g.remoteEaten = append(g.remoteEaten, eaten)
+		case msg.Type == "eat_denied" && !g.IsHost:
+			g.eatDenied = append(g.eatDenied, eaten.Index)
+		}
+		g.netMu.Unlock()
+	case "player_eaten":
+		data, _ := json.Marshal(msg.Data)
+		var eaten PlayerEaten
+		json.Unmarshal(data, &eaten)
+		if g.IsHost {
+			// A client's word alone can't take anyone's hole; the main loop
+			// checks the kill in judgeKillClaims before anyone hears of it
+			g.netMu.Lock()
+			g.killClaims = append(g.killClaims, killClaim{EaterID: msg.PlayerID, PlayerEaten: eaten})
+			g.netMu.Unlock()
+			return
+		}
+		if eaten.VictimID == g.PlayerID {
+			// The main loop owns our hole; it respawns us in applyRespawns
+			g.netMu.Lock()
+			g.swallowed = append(g.swallowed, eaten.Score)
+			g.netMu.Unlock()
+			return
 		}
-		g.Objects = append(g.Objects, obj)
+		// Keep other eaters from claiming the same hole before it respawns
+		g.netMu.Lock()
+		if player := g.NetworkPlayers[eaten.VictimID]; player != nil {
+			player.EatenAt = time.Now()
+		}
+		g.netMu.Unlock()
 	}
+}
 
-	// Generate extra large objects (medium buildings, etc.)
-	for i := 0; i < 25; i++ {
-		size := float32(48 + rand.Intn(20)) // 48-67 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 128, G: 128, B: 128, A: 255}, // Gray
-			Type:     "extra-large",
-			Value:    int(size),
-			Active:   true,
-			Rotation: rand.Float32() * 360,
-		}
-		g.Objects = append(g.Objects, obj)
+// activatePowerUp starts or extends a timed effect and lets other players know
+func (g *Game) activatePowerUp(kind PowerUp) {
+	if kind == PowerUpNone {
+		return
+	}
+	if kind == PowerUpGrowth && !g.powerUpActive(PowerUpGrowth) {
+		g.growthBonus = powerUpGrowthBonus
+		g.Player.Size += g.growthBonus
 	}
+	g.powerUpEnds[kind] = g.GameTime + powerUpDuration
 
-	// Generate huge objects (large buildings, etc.)
-	for i := 0; i < 15; i++ {
-		size := float32(68 + rand.Intn(25)) // 68-92 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 169, G: 169, B: 169, A: 255}, // Dark gray
-			Type:     "huge",
-			Value:    int(size),
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+	if g.inMultiplayer() {
+		g.sendNetworkMessage(NetworkMessage{
+			Type:     "power_up",
+			PlayerID: g.PlayerID,
+			Data:     PowerUpPickup{Kind: kind, Duration: powerUpDuration},
+		})
+	}
+}
+
+func (g *Game) powerUpActive(kind PowerUp) bool {
+	return g.powerUpEnds[kind] > g.GameTime
+}
+
+// clearPowerUps ends every effect without touching the hole
+func (g *Game) clearPowerUps() {
+	g.powerUpEnds = [powerUpCount]float32{}
+	g.growthBonus = 0
+}
+
+// updatePowerUps expires finished effects and drags edible objects toward
+// the hole while the magnet is on
+func (g *Game) updatePowerUps(deltaTime float32) {
+	for kind := PowerUpSpeed; kind < powerUpCount; kind++ {
+		if g.powerUpEnds[kind] == 0 || g.powerUpActive(kind) {
+			continue
+		}
+		g.powerUpEnds[kind] = 0
+		if kind == PowerUpGrowth {
+			g.Player.Size -= g.growthBonus
+			if g.Player.Size < holeRespawnSize {
+				g.Player.Size = holeRespawnSize
+			}
+			g.growthBonus = 0
 		}
-		g.Objects = append(g.Objects, obj)
 	}
 
-	// Generate massive objects (skyscrapers, etc.) - end game content
-	for i := 0; i < 8; i++ {
-		size := float32(93 + rand.Intn(30)) // 93-122 size
-		obj := GameObject{
-			Position: Vector2{
-				X: rand.Float32() * worldWidth,
-				Y: rand.Float32() * worldHeight,
-			},
-			Size:     size,
-			Color:    rl.Color{R: 47, G: 79, B: 79, A: 255}, // Dark slate gray
-			Type:     "massive",
-			Value:    int(size),
-			Active:   true,
-			Rotation: rand.Float32() * 360,
+	// The pull moves objects locally, and the host judges eats against its
+	// own positions, so like walkers it would desync the multiplayer field
+	if !g.powerUpActive(PowerUpMagnet) || g.inMultiplayer() {
+		return
+	}
+	for _, i := range g.nearbyObjects(g.Player.Position, g.Player.Size+magnetRadius) {
+		obj := &g.Objects[i]
+		if !obj.Active || !bigEnoughToEat(g.Player.Size, obj) {
+			continue
 		}
-		g.Objects = append(g.Objects, obj)
+		dx := g.Player.Position.X - obj.Position.X
+		dy := g.Player.Position.Y - obj.Position.Y
+		distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		if distance == 0 {
+			continue
+		}
+		step := magnetSpeed * deltaTime
+		if step > distance {
+			step = distance
+		}
+		from := obj.Position
+		obj.Position.X += dx / distance * step
+		obj.Position.Y += dy / distance * step
+		g.objectGrid.Move(i, from, obj.Position)
 	}
 }
 
-func (g *Game) addParticle(pos Vector2, color rl.Color) {
-	for i := 0; i < 3; i++ {
-		particle := Particle{
-			Position: pos,
-			Velocity: Vector2{
-				X: (rand.Float32() - 0.5) * 100,
-				Y: (rand.Float32() - 0.5) * 100,
-			},
-			Life:    1.0,
-			MaxLife: 1.0,
-			Color:   color,
-			Size:    2 + rand.Float32()*3,
-		}
-		g.Particles = append(g.Particles, particle)
+// feedPlayer grows the player's hole for an eaten object worth value
+func (g *Game) feedPlayer(value int) {
+	g.Player.Score += value
+	g.Player.Size += g.growthCurve().Growth(g.Player.Size, value)
+	g.lastMealTime = g.GameTime
+	g.objectsEaten++
+}
+
+// recordCatch keeps obj as the player's biggest catch if nothing larger has been eaten
+func (g *Game) recordCatch(obj *GameObject) {
+	if obj.Size > g.biggestCatch.Size {
+		g.biggestCatch = Catch{Type: obj.Type, Size: obj.Size}
 	}
 }
 
-func (g *Game) handleMenuInput() {
-	if rl.IsKeyPressed(rl.KeyUp) {
-		g.MenuSelection--
-		if g.MenuSelection < 0 {
-			g.MenuSelection = 2
+// applyRemoteConsumption applies the host's rulings on eaten objects. On the
+// host it judges client requests and tells late joiners what is already gone;
+// on a client it confirms or rolls back its own predicted eats and removes
+// what other players ate. Bad indices from malformed messages are ignored.
+func (g *Game) applyRemoteConsumption() {
+	g.netMu.Lock()
+	requests, eaten, denied := g.eatRequests, g.remoteEaten, g.eatDenied
+	g.eatRequests, g.remoteEaten, g.eatDenied = nil, nil, nil
+	joiners, joinState := g.lateJoiners, g.joinState
+	g.lateJoiners, g.joinState = nil, nil
+	g.netMu.Unlock()
+
+	if len(joiners) > 0 {
+		state := newWorldState(g.WorldSeed, g.Objects)
+		for _, id := range joiners {
+			g.sendToClient(id, NetworkMessage{Type: "world_state", PlayerID: g.PlayerID, Data: state})
 		}
 	}
-	if rl.IsKeyPressed(rl.KeyDown) {
-		g.MenuSelection++
-		if g.MenuSelection > 2 {
-			g.MenuSelection = 0
+	// A snapshot for another layout (sent before a round reset) is ignored
+	if joinState != nil && joinState.WorldSeed == g.WorldSeed && joinState.Count == len(g.Objects) {
+		for i := range g.Objects {
+			if obj := &g.Objects[i]; obj.Active && !joinState.active(i) {
+				obj.Active = false
+				g.objectGrid.Remove(i, obj.Position)
+			}
 		}
 	}
-	if rl.IsKeyPressed(rl.KeyEnter) {
-		switch g.MenuSelection {
-		case 0: // Single Player
-			g.initSinglePlayer()
-			g.State = StateGameplay
-		case 1: // Host Multiplayer
-			g.startServer()
-			g.initSinglePlayer()
-			g.State = StateLobby
-		case 2: // Join Multiplayer
-			g.InputActive = true
-			g.InputText = g.ServerIP
-		}
+
+	for _, req := range requests {
+		g.judgeEatRequest(req)
 	}
-}
 
-func (g *Game) handleLobbyInput() {
-	if rl.IsKeyPressed(rl.KeySpace) {
-		g.LobbyReady = !g.LobbyReady
-		if g.IsHost {
-			// Host can start game if minimum players reached
-			if len(g.NetworkPlayers)+1 >= g.MinPlayers && g.LobbyReady {
-				g.startGame()
+	for _, e := range eaten {
+		if e.Index < 0 || e.Index >= len(g.Objects) {
+			continue
+		}
+		obj := &g.Objects[e.Index]
+		pending, predicted := g.pendingEats[e.Index]
+		delete(g.pendingEats, e.Index)
+
+		if e.EaterID == g.PlayerID {
+			// Confirmed; grow now. A prediction that already timed out is
+			// back on the field, so take it off again.
+			if !predicted {
+				if !obj.Active {
+					continue
+				}
+				pending.Value = obj.Value
+				obj.Active = false
+				g.objectGrid.Remove(e.Index, obj.Position)
 			}
+			g.feedPlayer(pending.Value)
+			g.recordCatch(obj)
+			g.activatePowerUp(obj.PowerUp)
+			continue
+		}
+
+		// Someone else got it, even if we predicted it was ours
+		if obj.Active {
+			g.addParticle(obj.Position, obj.Color)
+			obj.Active = false
+			g.objectGrid.Remove(e.Index, obj.Position)
 		}
-		g.sendLobbyUpdate()
 	}
-	if rl.IsKeyPressed(rl.KeyEscape) {
-		// Return to menu
-		g.State = StateMenu
-		g.LobbyReady = false
-		g.GameStarted = false
-		// Release mouse cursor when returning to menu
-		rl.EnableCursor()
-		if g.ServerConn != nil {
-			g.ServerConn.Close()
-			g.ServerConn = nil
+
+	for _, index := range denied {
+		if _, predicted := g.pendingEats[index]; predicted {
+			g.restorePredictedEat(index)
+		}
+	}
+
+	// Give back eats the host never answered, e.g. lost across a reconnect
+	now := time.Now()
+	for index, pending := range g.pendingEats {
+		if now.Sub(pending.At) > eatVerdictTimeout {
+			g.restorePredictedEat(index)
 		}
 	}
 }
 
-func (g *Game) sendLobbyUpdate() {
-	update := LobbyUpdate{
-		PlayerCount: len(g.NetworkPlayers) + 1,
-		GameStarted: g.GameStarted,
-		HostReady:   g.LobbyReady,
+// restorePredictedEat puts an object we predicted eating back on the field
+func (g *Game) restorePredictedEat(index int) {
+	delete(g.pendingEats, index)
+	if index < 0 || index >= len(g.Objects) || g.Objects[index].Active {
+		return
+	}
+	g.Objects[index].Active = true
+	g.objectGrid.Insert(index, g.Objects[index].Position)
+}
+
+// judgeEatRequest lets a client eat an object if it is still there and the
+// client's last reported hole could reach it, then tells everyone; otherwise
+// only the requester hears it was denied
+func (g *Game) judgeEatRequest(req ObjectEaten) {
+	g.netMu.RLock()
+	var eater Hole
+	player := g.NetworkPlayers[req.EaterID]
+	if player != nil {
+		eater = player.Hole
+	}
+	g.netMu.RUnlock()
+
+	valid := player != nil && req.Index >= 0 && req.Index < len(g.Objects) && g.Objects[req.Index].Active
+	if valid {
+		obj := &g.Objects[req.Index]
+		// Positions lag a round trip behind, so allow some extra reach
+		valid = distanceBetween(eater.Position, obj.Position) < eater.Size+eatRequestSlack &&
+			bigEnoughToEat(eater.Size, obj)
+	}
+	if !valid {
+		g.sendToClient(req.EaterID, NetworkMessage{Type: "eat_denied", PlayerID: g.PlayerID, Data: req})
+		return
 	}
+
+	obj := &g.Objects[req.Index]
+	g.addParticle(obj.Position, obj.Color)
+	obj.Active = false
+	g.objectGrid.Remove(req.Index, obj.Position)
+	g.broadcastMessage(NetworkMessage{Type: "object_eaten", PlayerID: g.PlayerID, Data: req})
+	g.suddenDeathMeal(req.EaterID)
+}
+
+// serverConn returns the connection to the host, or nil when not connected
+func (g *Game) serverConn() net.Conn {
+	g.netMu.RLock()
+	defer g.netMu.RUnlock()
+	return g.ServerConn
+}
+
+// inMultiplayer reports whether the game is hosting or connected to a host
+func (g *Game) inMultiplayer() bool {
+	return g.IsHost || g.serverConn() != nil
+}
+
+// sendNetworkMessage delivers a message to every peer: broadcast when hosting,
+// otherwise to the host, which relays it where needed.
+func (g *Game) sendNetworkMessage(msg NetworkMessage) {
 	if g.IsHost {
-		update.ServerIP = g.LocalIP + ":8080"
+		g.broadcastMessage(msg)
+	} else if conn := g.serverConn(); conn != nil {
+		conn.Write(encodeMessage(msg))
 	}
+}
 
-	msg := NetworkMessage{
-		Type:     "lobby_update",
-		PlayerID: g.PlayerID,
-		Data:     update,
+// canConsume reports whether hole h is big enough and close enough to swallow obj
+func canConsume(h *Hole, obj *GameObject, physics PhysicsMode) bool {
+	distance := distanceBetween(h.Position, obj.Position)
+
+	inReach := distance < h.Size
+	if physics != PhysicsClassic {
+		// Objects have to be pulled fully inside before they drop
+		inReach = distance+obj.Size <= h.Size
 	}
+	return inReach && bigEnoughToEat(h.Size, obj)
+}
 
-	data, _ := json.Marshal(msg)
-	if g.IsHost {
-		// Send to all clients
-		for _, conn := range g.ClientConns {
-			conn.Write(data)
-			conn.Write([]byte("\n"))
+// fitsInside reports whether obj can drop entirely inside a hole of the given
+// size, which physics modes require before it is eaten. Only these objects are
+// pulled in; anything bigger would be dragged to the center and stick there.
+func fitsInside(size float32, obj *GameObject) bool {
+	return obj.Size < size && bigEnoughToEat(size, obj)
+}
+
+// bigEnoughToEat reports whether a hole of the given size can swallow obj.
+// Obstacles have to be outgrown entirely; anything else only mostly.
+func bigEnoughToEat(size float32, obj *GameObject) bool {
+	if obj.Type == obstacleType {
+		return size > obj.Size
+	}
+	return size > obj.Size*0.8
+}
+
+// Obstacles are rocks and walls that block holes until they outgrow them
+const (
+	obstacleType      = "obstacle"
+	obstacleMinSize   = 25
+	obstacleSizeRange = 36
+	obstacleClearance = 100.0 // Gap kept between obstacles and the starting spot
+)
+
+// pushOutOfObstacles moves hole h clear of every obstacle it is still too small
+// to eat, along the line between their centers
+func (g *Game) pushOutOfObstacles(h *Hole) {
+	for _, i := range g.nearbyObjects(h.Position, h.Size+obstacleMinSize+obstacleSizeRange) {
+		obj := &g.Objects[i]
+		if !obj.Active || obj.Type != obstacleType || bigEnoughToEat(h.Size, obj) {
+			continue
 		}
-	} else if g.ServerConn != nil {
-		// Send to server
-		g.ServerConn.Write(data)
-		g.ServerConn.Write([]byte("\n"))
+		dx := h.Position.X - obj.Position.X
+		dy := h.Position.Y - obj.Position.Y
+		distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		reach := h.Size + obj.Size
+		if distance >= reach {
+			continue
+		}
+		if distance == 0 {
+			dx, distance = 1, 1
+		}
+		h.Position.X = obj.Position.X + dx/distance*reach
+		h.Position.Y = obj.Position.Y + dy/distance*reach
 	}
+	g.clampToWorld(h)
 }
 
-func (g *Game) startGame() {
-	g.GameStarted = true
-	g.State = StateGameplay
-	g.GameTime = 0
+// GrowthBreakpoint scales growth down once a hole is bigger than Size.
+// Breakpoints stack, so a hole past several gets all their factors.
+type GrowthBreakpoint struct {
+	Size   float32
+	Factor float32
+}
 
-	// Lock mouse cursor to the game window during multiplayer gameplay
-	rl.DisableCursor()
+// GrowthCurve sets how fast holes grow: Rate is the size gained per point of
+// object value, with diminishing returns from the breakpoints
+type GrowthCurve struct {
+	Name        string
+	Rate        float32
+	Breakpoints []GrowthBreakpoint
+}
 
-	g.sendLobbyUpdate()
+// growthPaces are the curves the host can pick in the lobby; the first is the default
+var growthPaces = []GrowthCurve{
+	{Name: "Standard", Rate: 0.02, Breakpoints: []GrowthBreakpoint{{50, 0.7}, {100, 0.5}, {200, 0.3}}},
+	{Name: "Casual", Rate: 0.05, Breakpoints: []GrowthBreakpoint{{100, 0.7}, {200, 0.5}}},
+	{Name: "Grindy", Rate: 0.012, Breakpoints: []GrowthBreakpoint{{40, 0.6}, {80, 0.4}, {160, 0.25}}},
 }
 
-func (g *Game) handleTextInput() {
-	key := rl.GetCharPressed()
-	for key > 0 {
-		if key >= 32 && key <= 125 && len(g.InputText) < 20 {
-			g.InputText += string(rune(key))
+// Growth returns how much a hole of the given size grows from eating an object
+func (c GrowthCurve) Growth(size float32, value int) float32 {
+	growthAmount := float32(value) * c.Rate
+	// Add diminishing returns for larger holes
+	for _, bp := range c.Breakpoints {
+		if size > bp.Size {
+			growthAmount *= bp.Factor
 		}
-		key = rl.GetCharPressed()
 	}
-	if rl.IsKeyPressed(rl.KeyBackspace) && len(g.InputText) > 0 {
-		g.InputText = g.InputText[:len(g.InputText)-1]
+	return growthAmount
+}
+
+// growthCurve returns the curve for the match's pace
+func (g *Game) growthCurve() GrowthCurve {
+	if g.Pace < 0 || g.Pace >= len(growthPaces) {
+		return growthPaces[0]
 	}
-	if rl.IsKeyPressed(rl.KeyEnter) {
-		g.ServerIP = g.InputText
-		g.connectToServer()
-		g.InputActive = false
+	return growthPaces[g.Pace]
+}
+
+// matchDuration returns the match length in seconds chosen for the match
+func (g *Game) matchDuration() float32 {
+	if g.MatchDuration < 0 || g.MatchDuration >= len(matchDurations) {
+		return matchDurations[defaultMatchDuration]
 	}
-	if rl.IsKeyPressed(rl.KeyEscape) {
-		g.InputActive = false
+	return matchDurations[g.MatchDuration]
+}
+
+// worldSize returns the map size chosen for the match
+func (g *Game) worldSize() WorldSize {
+	if g.WorldSize < 0 || g.WorldSize >= len(worldSizes) {
+		return worldSizes[defaultWorldSize]
 	}
+	return worldSizes[g.WorldSize]
 }
 
-func (g *Game) startServer() {
-	go func() {
-		listener, err := net.Listen("tcp", ":8080")
-		if err != nil {
-			fmt.Printf("Failed to start server: %v\n", err)
-			return
-		}
-		defer listener.Close()
-		fmt.Println("Server started on :8080")
-		g.IsHost = true
+// objectDensity returns the object density chosen for the match
+func (g *Game) objectDensity() ObjectDensity {
+	if g.Density < 0 || g.Density >= len(objectDensities) {
+		return objectDensities[defaultObjectDensity]
+	}
+	return objectDensities[g.Density]
+}
 
-		for {
-			conn, err := listener.Accept()
-			if err != nil {
-				continue
-			}
-			g.ClientConns = append(g.ClientConns, conn)
-			go g.handleClient(conn)
-		}
-	}()
+// applyWorldSize sets the world dimensions from the chosen map size
+func (g *Game) applyWorldSize() {
+	size := g.worldSize()
+	g.WorldWidth = size.Width
+	g.WorldHeight = size.Height
+	// Network goroutines check positions against the map, so they get their own copy
+	g.netMu.Lock()
+	g.netWorldSize = Vector2{X: size.Width, Y: size.Height}
+	g.netMu.Unlock()
 }
 
-func (g *Game) connectToServer() {
-	go func() {
-		conn, err := net.Dial("tcp", g.ServerIP)
-		if err != nil {
-			fmt.Printf("Failed to connect to server: %v\n", err)
-			return
-		}
-		g.ServerConn = conn
-		g.initSinglePlayer()
-		g.State = StateLobby
-		go g.handleServerMessages()
-		// Send initial lobby update to announce joining
-		time.Sleep(100 * time.Millisecond) // Brief delay to ensure connection
-		g.sendLobbyUpdate()
-	}()
+// spawnCount scales an object count tuned for the medium map to the current
+// world area and density
+func (g *Game) spawnCount(base int) int {
+	medium := worldSizes[defaultWorldSize]
+	area := (g.WorldWidth * g.WorldHeight) / (medium.Width * medium.Height)
+	return int(float32(base)*area*g.objectDensity().Factor + 0.5)
 }
 
-func (g *Game) handleClient(conn net.Conn) {
-	// Send initial lobby state to new client
-	g.sendLobbyUpdate()
+// clampToWorld keeps hole h inside the world bounds
+func (g *Game) clampToWorld(h *Hole) {
+	clampToBounds(h, g.WorldWidth, g.WorldHeight)
+}
 
-	decoder := json.NewDecoder(conn)
-	for {
-		var msg NetworkMessage
-		if err := decoder.Decode(&msg); err != nil {
-			break
+// World-edge bounce and warning glow
+const (
+	defaultBorderBounce = 0.5
+	borderBounceDrag    = 4.0   // How fast a bounce dies away, per second
+	edgeWarningDistance = 120.0 // Gap to the edge at which the glow starts
+	edgeGlowWidth       = 40.0
+)
+
+// bounceOffWorld moves the player on by any bounce under way and, when this
+// frame's move from prev ran it into the world edge, sends it back off with
+// BorderBounce of the speed it hit at. The player is clamped afterwards, so no
+// bounce can carry it out of the world.
+func (g *Game) bounceOffWorld(prev Vector2, deltaTime float32) {
+	h := &g.Player
+	if deltaTime > 0 && g.BorderBounce > 0 {
+		vx := (h.Position.X - prev.X) / deltaTime
+		vy := (h.Position.Y - prev.Y) / deltaTime
+		// Only the frame of impact bounces; holding into the edge just rests against it
+		if (h.Position.X < h.Size && prev.X > h.Size) || (h.Position.X > g.WorldWidth-h.Size && prev.X < g.WorldWidth-h.Size) {
+			g.bounce.X = -vx * g.BorderBounce
 		}
-		g.processNetworkMessage(msg)
-		// Broadcast lobby updates to all clients when someone joins
-		if msg.Type == "lobby_update" {
-			g.sendLobbyUpdate()
+		if (h.Position.Y < h.Size && prev.Y > h.Size) || (h.Position.Y > g.WorldHeight-h.Size && prev.Y < g.WorldHeight-h.Size) {
+			g.bounce.Y = -vy * g.BorderBounce
 		}
 	}
-	conn.Close()
+
+	h.Position.X += g.bounce.X * deltaTime
+	h.Position.Y += g.bounce.Y * deltaTime
+	decay := float32(math.Exp(-borderBounceDrag * float64(deltaTime)))
+	g.bounce.X *= decay
+	g.bounce.Y *= decay
+
+	unclamped := h.Position
+	g.clampToWorld(h)
+	// A bounce that overshoots into another edge stops there
+	if h.Position.X != unclamped.X {
+		g.bounce.X = 0
+	}
+	if h.Position.Y != unclamped.Y {
+		g.bounce.Y = 0
+	}
 }
 
-func (g *Game) handleServerMessages() {
-	decoder := json.NewDecoder(g.ServerConn)
-	for {
-		var msg NetworkMessage
-		if err := decoder.Decode(&msg); err != nil {
-			break
-		}
-		g.processNetworkMessage(msg)
+// clampToBounds keeps hole h inside a world of the given size
+func clampToBounds(h *Hole, width, height float32) {
+	if h.Position.X < h.Size {
+		h.Position.X = h.Size
+	}
+	if h.Position.X > width-h.Size {
+		h.Position.X = width - h.Size
+	}
+	if h.Position.Y < h.Size {
+		h.Position.Y = h.Size
+	}
+	if h.Position.Y > height-h.Size {
+		h.Position.Y = height - h.Size
 	}
 }
 
-func (g *Game) processNetworkMessage(msg NetworkMessage) {
-	switch msg.Type {
-	case "player_update":
-		data, _ := json.Marshal(msg.Data)
-		var update PlayerUpdate
-		json.Unmarshal(data, &update)
-		if g.NetworkPlayers[msg.PlayerID] == nil {
-			colors := []rl.Color{rl.Red, rl.Blue, rl.Green, rl.Yellow, rl.Purple, rl.Orange}
-			g.NetworkPlayers[msg.PlayerID] = &NetworkPlayer{
-				ID:    msg.PlayerID,
-				Name:  fmt.Sprintf("Player %d", msg.PlayerID),
-				Color: colors[msg.PlayerID%len(colors)],
-			}
-		}
-		player := g.NetworkPlayers[msg.PlayerID]
-		player.Hole.Position = update.Position
-		player.Hole.Size = update.Size
-		player.Hole.Score = update.Score
-		player.Hole.Animation = update.Animation
-		player.LastSeen = time.Now()
-	case "lobby_update":
-		data, _ := json.Marshal(msg.Data)
-		var update LobbyUpdate
-		json.Unmarshal(data, &update)
-		// Add player to lobby if not already present
-		if g.NetworkPlayers[msg.PlayerID] == nil {
-			colors := []rl.Color{rl.Red, rl.Blue, rl.Green, rl.Yellow, rl.Purple, rl.Orange}
-			g.NetworkPlayers[msg.PlayerID] = &NetworkPlayer{
-				ID:    msg.PlayerID,
-				Name:  fmt.Sprintf("Player %d", msg.PlayerID),
-				Color: colors[msg.PlayerID%len(colors)],
-				LastSeen: time.Now(),
-			}
-		} else {
-			g.NetworkPlayers[msg.PlayerID].LastSeen = time.Now()
+func distanceBetween(a, b Vector2) float32 {
+	dx := a.X - b.X
+	dy := a.Y - b.Y
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
+
+// randomWorldPoint picks a spot at least margin away from the world edges
+func (g *Game) randomWorldPoint(margin float32) Vector2 {
+	return Vector2{
+		X: margin + rand.Float32()*(g.WorldWidth-2*margin),
+		Y: margin + rand.Float32()*(g.WorldHeight-2*margin),
+	}
+}
+
+// canSwallowHole reports whether eater is clearly bigger than victim and covers its center.
+// Near-equal holes bounce off each other rather than trading kills.
+func canSwallowHole(eater, victim Hole) bool {
+	if eater.Size <= victim.Size*holeEatRatio {
+		return false
+	}
+	return distanceBetween(eater.Position, victim.Position) < eater.Size
+}
+
+// consumeNetworkHoles swallows any smaller remote hole the player overlaps
+// and tells its owner to respawn.
+func (g *Game) consumeNetworkHoles() {
+	var eaten []PlayerEaten
+
+	g.netMu.Lock()
+	for id, player := range g.NetworkPlayers {
+		if time.Since(player.EatenAt) < holeRespawnImmunity || !canSwallowHole(g.Player, player.Hole) {
+			continue
 		}
-		// If game started, transition to gameplay
-		if update.GameStarted && g.State == StateLobby {
-			g.State = StateGameplay
-			g.GameTime = 0
+		player.EatenAt = time.Now()
+		eaten = append(eaten, PlayerEaten{
+			VictimID: id,
+			Score:    int(float32(player.Hole.Score) * holeAbsorbFraction),
+			Size:     player.Hole.Size * holeAbsorbFraction,
+		})
+		g.addParticle(player.Hole.Position, player.Color)
+	}
+	g.netMu.Unlock()
+
+	for _, e := range eaten {
+		g.Player.Score += e.Score
+		g.Player.Size += e.Size
+		g.lastMealTime = g.GameTime
+		g.addScorePopup(g.Player.Position, e.Score)
+		g.sendNetworkMessage(NetworkMessage{
+			Type:     "player_eaten",
+			PlayerID: g.PlayerID,
+			Data:     e,
+		})
+	}
+}
+
+// judgeKillClaims rules on the kills clients reported since the last frame.
+// A claim only stands if the host's own copies of both holes agree the eater
+// could swallow the victim; the host then relays it to everyone, with the
+// points worked out from its copy of the victim. Anything else is dropped.
+func (g *Game) judgeKillClaims() {
+	g.netMu.Lock()
+	claims := g.killClaims
+	g.killClaims = nil
+	g.netMu.Unlock()
+
+	for _, claim := range claims {
+		var prey Hole
+		valid := false
+		g.netMu.Lock()
+		eater, victim := g.NetworkPlayers[claim.EaterID], g.NetworkPlayers[claim.VictimID]
+		switch {
+		case eater == nil || claim.VictimID == claim.EaterID:
+		case claim.VictimID == g.PlayerID:
+			prey, valid = g.Player, true
+		case victim != nil && time.Since(victim.EatenAt) >= holeRespawnImmunity:
+			prey, valid = victim.Hole, true
+		}
+		valid = valid && canSwallowHole(eater.Hole, prey)
+		if valid && victim != nil {
+			victim.EatenAt = time.Now()
+		}
+		g.netMu.Unlock()
+		if !valid {
+			continue
+		}
+
+		eaten := PlayerEaten{
+			VictimID: claim.VictimID,
+			Score:    int(float32(prey.Score) * holeAbsorbFraction),
+			Size:     prey.Size * holeAbsorbFraction,
+		}
+		g.broadcastMessage(NetworkMessage{
+			Type:     "player_eaten",
+			PlayerID: claim.EaterID,
+			Data:     eaten,
+		})
+		if claim.VictimID == g.PlayerID {
+			g.respawnPlayer(eaten.Score)
 		}
 	}
 }
 
+// applyRespawns respawns the player for every time another player reported
+// swallowing them since the last frame
+func (g *Game) applyRespawns() {
+	g.netMu.Lock()
+	swallowed := g.swallowed
+	g.swallowed = nil
+	g.netMu.Unlock()
+	for _, lost := range swallowed {
+		g.respawnPlayer(lost)
+	}
+}
+
+// respawnPlayer drops a swallowed player back in at starting size, minus the points lost
+func (g *Game) respawnPlayer(lostScore int) {
+	g.addParticle(g.Player.Position, rl.Black)
+	g.Player.Score -= lostScore
+	if g.Player.Score < 0 {
+		g.Player.Score = 0
+	}
+	g.Player.Size = holeRespawnSize
+	g.Player.Position = g.randomWorldPoint(holeRespawnSize)
+	// The respawn size already drops any temporary growth
+	g.growthBonus = 0
+	g.slowdown = 0
+	g.bounce = Vector2{}
+}
+
+// playerUpdateInterval is how often the local hole is sent to other players
+const playerUpdateInterval = 100 * time.Millisecond
+
+// playerUpdateDue reports whether a player update should go out at now. Sends
+// are stepped on a fixed grid from lastSendTime so the average rate stays at
+// one per interval at any FPS; after a long stall the grid restarts at now
+// instead of bursting to catch up.
+func (g *Game) playerUpdateDue(now time.Time) bool {
+	if now.Sub(g.lastSendTime) < playerUpdateInterval {
+		return false
+	}
+	g.lastSendTime = g.lastSendTime.Add(playerUpdateInterval)
+	if now.Sub(g.lastSendTime) >= playerUpdateInterval {
+		g.lastSendTime = now
+	}
+	return true
+}
+
 func (g *Game) sendPlayerUpdate() {
 	update := PlayerUpdate{
 		Position:  g.Player.Position,
 		Size:      g.Player.Size,
 		Score:     g.Player.Score,
 		Animation: g.Player.Animation,
+		Name:      playerDisplayName(g.PlayerName, g.PlayerID),
+		Catch:     g.biggestCatch,
+		Eaten:     g.objectsEaten,
 	}
+	g.updateSeq++
+	update.Seq = g.updateSeq
 	msg := NetworkMessage{
 		Type:     "player_update",
 		PlayerID: g.PlayerID,
 		Data:     update,
 	}
 
-	data, _ := json.Marshal(msg)
+	udp := g.Transport == TransportHybrid && g.udpConn != nil
 	if g.IsHost {
-		// Send to all clients
-		for _, conn := range g.ClientConns {
-			conn.Write(data)
-			conn.Write([]byte("\n"))
+		if udp {
+			data, _ := json.Marshal(msg)
+			g.broadcastPosition(msg, data)
+		} else {
+			// Send to all clients
+			g.broadcastMessage(msg)
 		}
-	} else if g.ServerConn != nil {
-		// Send to server
-		g.ServerConn.Write(data)
-		g.ServerConn.Write([]byte("\n"))
+	} else if conn := g.serverConn(); conn != nil {
+		if udp {
+			data, _ := json.Marshal(msg)
+			g.udpConn.Write(data)
+		} else {
+			// Send to server
+			conn.Write(encodeMessage(msg))
+		}
+	}
+}
+
+// handleMovementInput moves the player hole from keyboard and mouse input
+func (g *Game) handleMovementInput(deltaTime float32) {
+	speed := g.Player.Speed
+	if g.powerUpActive(PowerUpSpeed) {
+		speed *= powerUpSpeedFactor
+	}
+	speed *= 1 - g.slowdown
+
+	if g.actionDown(ActionMoveUp) || rl.IsKeyDown(rl.KeyUp) {
+		g.Player.Position.Y -= speed * deltaTime
+	}
+	if g.actionDown(ActionMoveDown) || rl.IsKeyDown(rl.KeyDown) {
+		g.Player.Position.Y += speed * deltaTime
+	}
+	if g.actionDown(ActionMoveLeft) || rl.IsKeyDown(rl.KeyLeft) {
+		g.Player.Position.X -= speed * deltaTime
+	}
+	if g.actionDown(ActionMoveRight) || rl.IsKeyDown(rl.KeyRight) {
+		g.Player.Position.X += speed * deltaTime
+	}
+
+	// Left stick - deadzone already applied, so a resting stick adds nothing
+	stick := gamepadStick()
+	g.Player.Position.X += stick.X * speed * deltaTime
+	g.Player.Position.Y += stick.Y * speed * deltaTime
+
+	// Handle mouse movement; zero sensitivity leaves keyboard-only players alone
+	sensitivity := g.Settings.MouseSensitivity
+	if sensitivity <= 0 {
+		return
+	}
+	mousePos := rl.GetMousePosition()
+	if g.Settings.ShowCursor {
+		g.moveTowardCursor(mousePos, speed*sensitivity*deltaTime)
+		return
+	}
+	screenCenter := Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2}
+	direction := Vector2{
+		X: mousePos.X - screenCenter.X,
+		Y: mousePos.Y - screenCenter.Y,
+	}
+	if g.Settings.InvertY {
+		direction.Y = -direction.Y
+	}
+
+	// Normalize direction
+	length := float32(math.Sqrt(float64(direction.X*direction.X + direction.Y*direction.Y)))
+	if length > 0 {
+		direction.X /= length
+		direction.Y /= length
+
+		// Move player towards mouse
+		g.Player.Position.X += direction.X * speed * sensitivity * deltaTime
+		g.Player.Position.Y += direction.Y * speed * sensitivity * deltaTime
 	}
 }
 
+// moveTowardCursor moves the player up to step world units toward the point
+// under the mouse, stopping on it rather than overshooting
+func (g *Game) moveTowardCursor(mousePos rl.Vector2, step float32) {
+	target := rl.GetScreenToWorld2D(mousePos, g.Camera)
+	dx := target.X - g.Player.Position.X
+	dy := target.Y - g.Player.Position.Y
+	distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	if distance == 0 {
+		return
+	}
+	if step > distance {
+		step = distance
+	}
+	g.Player.Position.X += dx / distance * step
+	g.Player.Position.Y += dy / distance * step
+}
+
+// drawReticle marks where a visible cursor is steering the hole
+func drawReticle(pos rl.Vector2) {
+	color := rl.Color{R: 255, G: 255, B: 255, A: 200}
+	rl.DrawCircleLines(int32(pos.X), int32(pos.Y), 10, color)
+	rl.DrawLineEx(rl.Vector2{X: pos.X - 16, Y: pos.Y}, rl.Vector2{X: pos.X - 5, Y: pos.Y}, 2, color)
+	rl.DrawLineEx(rl.Vector2{X: pos.X + 5, Y: pos.Y}, rl.Vector2{X: pos.X + 16, Y: pos.Y}, 2, color)
+	rl.DrawLineEx(rl.Vector2{X: pos.X, Y: pos.Y - 16}, rl.Vector2{X: pos.X, Y: pos.Y - 5}, 2, color)
+	rl.DrawLineEx(rl.Vector2{X: pos.X, Y: pos.Y + 5}, rl.Vector2{X: pos.X, Y: pos.Y + 16}, 2, color)
+}
+
 func (g *Game) update(deltaTime float32) {
+	g.heartbeatActive.Store(g.State == StateLobby || g.State == StateGameplay || g.State == StatePaused || g.State == StateGameOver)
+	if g.actionPressed(ActionDebug) {
+		g.ShowDebug = !g.ShowDebug
+	}
+	g.joinDialedServer()
+
 	switch g.State {
 	case StateMenu:
+		if g.handleDisconnect() {
+			return
+		}
+		if g.updateAttractMode(deltaTime) {
+			return
+		}
 		if g.InputActive {
 			g.handleTextInput()
 		} else {
@@ -626,29 +5026,95 @@ func (g *Game) update(deltaTime float32) {
 		}
 		return
 	case StateLobby:
+		if g.handleDisconnect() {
+			return
+		}
+		g.syncWorldSeed()
+		g.pruneStalePlayers()
+		if g.waitForMatchStart(); g.State != StateLobby {
+			return
+		}
 		g.handleLobbyInput()
 		return
 	case StateGameOver:
+		if g.handleDisconnect() || g.nextRoundDue() {
+			return
+		}
+		// Our clock can end a tied match before the host's overtime call arrives
+		if g.syncSuddenDeath(); g.State != StateGameOver {
+			return
+		}
 		g.handleGameOverInput()
 		return
+	case StateSettings:
+		g.handleSettingsInput()
+		return
+	case StateControls:
+		g.handleControlsInput()
+		return
+	case StateReplay:
+		g.updateReplay(deltaTime)
+		return
+	case StatePaused:
+		g.handlePauseInput()
+		if g.State == StateGameplay {
+			// Resumed with the same back press gameplay pauses on; let it go
+			// this frame so it doesn't pause again straight away
+			return
+		}
+		if g.State == StateMenu || (g.State == StatePaused && !g.inMultiplayer()) {
+			// Single player freezes the match
+			return
+		}
+		// Multiplayer pause is local only - the match clock keeps running
+		fallthrough
 	case StateGameplay:
+		if g.handleDisconnect() || g.nextRoundDue() {
+			return
+		}
+		if g.State == StateGameplay && !g.Autopilot && (backPressed() || gamepadButtonPressed(rl.GamepadButtonMiddleRight)) {
+			if g.practice {
+				g.leaveToMenu()
+				return
+			}
+			g.pause()
+		}
+		if g.practice && g.actionPressed(ActionPracticeReset) {
+			g.resetPracticeField()
+		}
+		if g.State == StateGameplay && g.actionPressed(ActionMinimap) {
+			g.ShowMinimap = !g.ShowMinimap
+		}
+
+		// Hold everyone at the start line until the shared countdown ends
+		if g.waitForMatchStart() {
+			return
+		}
+
 		// Continue with normal game update
-		// Only update game time during gameplay
-		if g.GameTime < g.MaxGameTime {
+		// Only update game time during gameplay; only timed matches stop at the limit
+		if g.Mode != ModeTimed || g.GameTime < g.MaxGameTime {
 			g.GameTime += deltaTime
 		}
 
 		// Clean up old network players
-		for id, player := range g.NetworkPlayers {
-			if time.Since(player.LastSeen) > 5*time.Second {
-				delete(g.NetworkPlayers, id)
-			}
-		}
+		g.syncWorldSeed()
+		g.applyRemoteConsumption()
+		g.judgeKillClaims()
+		g.applyRespawns()
+		g.syncSuddenDeath()
+		g.pruneStalePlayers()
 		g.Player.Animation += deltaTime * 2.0
 
 		// Check for game over and matchmaking
-		if g.GameTime >= g.MaxGameTime {
+		if g.matchOver() && !g.startSuddenDeath() {
+			if g.inMultiplayer() {
+				// One last update so every peer's standings include our final meal
+				g.sendPlayerUpdate()
+			}
 			g.State = StateGameOver
+			g.roundOverAt = time.Now()
+			g.finishRecording()
 			// Release mouse cursor when game ends
 			rl.EnableCursor()
 			return
@@ -657,134 +5123,502 @@ func (g *Game) update(deltaTime float32) {
 		return
 	}
 
-	// Handle input
-	if rl.IsKeyDown(rl.KeyW) || rl.IsKeyDown(rl.KeyUp) {
-		g.Player.Position.Y -= g.Player.Speed * deltaTime
+	prev := g.Player.Position
+	if g.Autopilot {
+		// Demo hole chases the closest thing it can eat
+		if target := g.nearestEdibleObject(&g.Player, g.WorldWidth+g.WorldHeight); target >= 0 {
+			steerToward(&g.Player, g.Objects[target].Position, deltaTime)
+		}
+	} else if g.State != StatePaused {
+		g.handleMovementInput(deltaTime)
+	}
+
+	// Keep player in bounds, bouncing off the edges, and out of the obstacles
+	g.bounceOffWorld(prev, deltaTime)
+	g.pushOutOfObstacles(&g.Player)
+
+	g.updateCamera(deltaTime)
+	g.updateEffects(deltaTime)
+
+	// Animate object rotation; obstacles stay put
+	for i := range g.Objects {
+		if g.Objects[i].Active && g.Objects[i].Type != obstacleType {
+			g.Objects[i].Rotation += deltaTime * 30.0
+		}
+	}
+
+	// Pull nearby objects into the hole in physics map modes
+	physics := g.Physics
+	if physics != PhysicsClassic {
+		g.applyObjectAttraction(deltaTime)
+	}
+
+	// Check collisions and consume objects, only looking at the cells under the hole
+	for _, i := range g.nearbyObjects(g.Player.Position, g.Player.Size) {
+		if !g.Objects[i].Active {
+			continue
+		}
+
+		// Check if object can be consumed
+		if canConsume(&g.Player, &g.Objects[i], physics) {
+			// Add particles at consumption point
+			g.addParticle(g.Objects[i].Position, g.Objects[i].Color)
+			g.addScorePopup(g.Objects[i].Position, g.Objects[i].Value)
+			g.recordEat(i)
+			g.Sounds.PlayConsume(g.Objects[i].Type)
+
+			g.Objects[i].Active = false
+			g.objectGrid.Remove(i, g.Objects[i].Position)
+			if g.Objects[i].Size >= shakeMinObjectSize {
+				g.startShake(g.Objects[i].Size)
+			}
+			g.addWeight(g.Objects[i].Size)
+
+			if !g.IsHost && g.serverConn() != nil {
+				// Predict the eat so it feels instant; we only grow once the host confirms
+				g.pendingEats[i] = pendingEat{Value: g.Objects[i].Value, At: time.Now()}
+				g.sendNetworkMessage(NetworkMessage{
+					Type:     "eat_request",
+					PlayerID: g.PlayerID,
+					Data:     ObjectEaten{Index: i, EaterID: g.PlayerID},
+				})
+				continue
+			}
+
+			g.feedPlayer(g.Objects[i].Value)
+			g.recordCatch(&g.Objects[i])
+			g.activatePowerUp(g.Objects[i].PowerUp)
+
+			// The host's word is final: let everyone drop the same object
+			if g.IsHost {
+				g.sendNetworkMessage(NetworkMessage{
+					Type:     "object_eaten",
+					PlayerID: g.PlayerID,
+					Data:     ObjectEaten{Index: i, EaterID: g.PlayerID},
+				})
+				g.suddenDeathMeal(g.PlayerID)
+			}
+		}
+	}
+
+	g.updateNearMiss(deltaTime)
+	g.updateBots(deltaTime)
+	g.updatePowerUps(deltaTime)
+	g.updateWeight(deltaTime)
+
+	// Walking NPCs are driven by local randomness and the local hole, so like
+	// respawns they'd desync the shared multiplayer field
+	if !g.inMultiplayer() {
+		g.updateWalkers(deltaTime)
+	}
+
+	// Keep the field populated as objects get eaten. Respawns are random
+	// and local, so they'd desync the shared multiplayer field.
+	if g.RespawnEnabled && !g.inMultiplayer() {
+		g.respawnObjects(deltaTime)
+	}
+
+	inMatch := g.State == StateGameplay || g.State == StatePaused
+
+	// Swallow smaller opponents in multiplayer
+	if inMatch {
+		g.consumeNetworkHoles()
+		g.recordFrame()
+	}
+
+	// Send network updates at a fixed wall-clock rate, whatever the frame rate
+	if inMatch && g.inMultiplayer() && g.playerUpdateDue(time.Now()) {
+		g.sendPlayerUpdate()
+	}
+
+}
+
+// Manual scroll-wheel zoom, eased back to the automatic zoom when left alone
+const (
+	zoomWheelStep   = 0.25 // Powers of two per wheel notch
+	minZoomOffset   = -1.5
+	maxZoomOffset   = 1.0
+	zoomReturnDelay = 3.0 // Seconds without scrolling before the offset fades
+	zoomReturnRate  = 2.0
+)
+
+// handleZoomInput applies the mouse wheel to the manual zoom offset, and lets
+// the offset fade once the wheel has been left alone for zoomReturnDelay
+func (g *Game) handleZoomInput(deltaTime float32) {
+	if wheel := rl.GetMouseWheelMove(); wheel != 0 {
+		g.zoomOffset += wheel * zoomWheelStep
+		if g.zoomOffset < minZoomOffset {
+			g.zoomOffset = minZoomOffset
+		}
+		if g.zoomOffset > maxZoomOffset {
+			g.zoomOffset = maxZoomOffset
+		}
+		g.zoomIdle = 0
+		return
+	}
+	g.zoomIdle += deltaTime
+	if g.zoomIdle > zoomReturnDelay {
+		g.zoomOffset -= g.zoomOffset * deltaTime * zoomReturnRate
+	}
+}
+
+// updateCamera zooms out as the player grows and follows the player's hole
+func (g *Game) updateCamera(deltaTime float32) {
+	if !g.Autopilot {
+		g.handleZoomInput(deltaTime)
 	}
-	if rl.IsKeyDown(rl.KeyS) || rl.IsKeyDown(rl.KeyDown) {
-		g.Player.Position.Y += g.Player.Speed * deltaTime
+
+	// Adaptive camera zoom based on hole size
+	targetZoom := g.BaseZoom
+	if g.Player.Size > 50 {
+		// Gradually zoom out as hole gets bigger
+		zoomFactor := 50.0 / g.Player.Size
+		if zoomFactor < 0.2 {
+			zoomFactor = 0.2 // Minimum zoom
+		}
+		targetZoom = zoomFactor
 	}
-	if rl.IsKeyDown(rl.KeyA) || rl.IsKeyDown(rl.KeyLeft) {
-		g.Player.Position.X -= g.Player.Speed * deltaTime
+	if g.zoomOffset != 0 {
+		targetZoom *= float32(math.Exp2(float64(g.zoomOffset)))
 	}
-	if rl.IsKeyDown(rl.KeyD) || rl.IsKeyDown(rl.KeyRight) {
-		g.Player.Position.X += g.Player.Speed * deltaTime
+
+	// Smooth zoom transition
+	g.Camera.Zoom += (targetZoom - g.Camera.Zoom) * deltaTime * 2.0
+
+	// Update camera to follow player and handle window resizing
+	g.Camera.Offset = rl.Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2}
+	target := rl.Vector2{X: g.Player.Position.X, Y: g.Player.Position.Y}
+	if g.Settings.CameraSmoothing > 0 {
+		// Smooth follow, like the zoom
+		t := cameraFollowRate / float32(g.Settings.CameraSmoothing) * deltaTime
+		if t > 1 {
+			t = 1
+		}
+		target.X = g.Camera.Target.X + (target.X-g.Camera.Target.X)*t
+		target.Y = g.Camera.Target.Y + (target.Y-g.Camera.Target.Y)*t
+
+		// A boosted hole mustn't outrun the camera and leave the screen
+		maxLag := maxCameraLag / g.Camera.Zoom
+		dx := g.Player.Position.X - target.X
+		dy := g.Player.Position.Y - target.Y
+		if lag := float32(math.Sqrt(float64(dx*dx + dy*dy))); lag > maxLag {
+			target.X = g.Player.Position.X - dx/lag*maxLag
+			target.Y = g.Player.Position.Y - dy/lag*maxLag
+		}
 	}
+	g.Camera.Target = target
+}
 
-	// Handle mouse movement
-	mousePos := rl.GetMousePosition()
-	screenCenter := Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2}
-	direction := Vector2{
-		X: mousePos.X - screenCenter.X,
-		Y: mousePos.Y - screenCenter.Y,
+// updateEffects ages the camera shake, particles and score popups
+func (g *Game) updateEffects(deltaTime float32) {
+	// Let any camera shake die down
+	if g.shakeTime > 0 {
+		g.shakeTime -= deltaTime
 	}
 
-	// Normalize direction
-	length := float32(math.Sqrt(float64(direction.X*direction.X + direction.Y*direction.Y)))
-	if length > 0 {
-		direction.X /= length
-		direction.Y /= length
+	// Update particles
+	for i := len(g.Particles) - 1; i >= 0; i-- {
+		g.Particles[i].Life -= deltaTime
+		g.Particles[i].Position.X += g.Particles[i].Velocity.X * deltaTime
+		g.Particles[i].Position.Y += g.Particles[i].Velocity.Y * deltaTime
+		g.Particles[i].Velocity.X *= 0.98 // Damping
+		g.Particles[i].Velocity.Y *= 0.98
 
-		// Move player towards mouse
-		g.Player.Position.X += direction.X * g.Player.Speed * deltaTime
-		g.Player.Position.Y += direction.Y * g.Player.Speed * deltaTime
+		if g.Particles[i].Life <= 0 {
+			// Remove dead particle
+			g.Particles = append(g.Particles[:i], g.Particles[i+1:]...)
+		}
+	}
+
+	// Update score popups (rise and fade)
+	for i := len(g.ScorePopups) - 1; i >= 0; i-- {
+		g.ScorePopups[i].Life -= deltaTime
+		g.ScorePopups[i].Position.Y -= scorePopupRise * deltaTime
+
+		if g.ScorePopups[i].Life <= 0 {
+			g.ScorePopups = append(g.ScorePopups[:i], g.ScorePopups[i+1:]...)
+		}
+	}
+}
+
+// The near-miss outline fades in while the hole bumps into something too big
+// to eat, then quickly fades out again
+const (
+	nearMissFadeIn  = 8.0 // Outline strength gained per second of contact
+	nearMissFadeOut = 3.0 // Outline strength lost per second once contact ends
+	nearMissShake   = 1.5 // Peak sideways jitter of the outline in world units
+)
+
+// maxObjectSize is the largest object generateObjects or a respawn can produce
+func maxObjectSize() float32 {
+	tier := objectTiers[len(objectTiers)-1]
+	return float32(tier.MinSize + tier.SizeRange)
+}
+
+// updateNearMiss finds the object the player's hole overlaps most among those it
+// isn't big enough to eat yet, obstacles included, and fades the outline that says "grow first"
+func (g *Game) updateNearMiss(deltaTime float32) {
+	touching, deepest := -1, float32(0)
+	for _, i := range g.nearbyObjects(g.Player.Position, g.Player.Size+maxObjectSize()) {
+		obj := &g.Objects[i]
+		if !obj.Active || bigEnoughToEat(g.Player.Size, obj) {
+			continue
+		}
+		overlap := g.Player.Size + obj.Size - distanceBetween(g.Player.Position, obj.Position)
+		if overlap > deepest {
+			touching, deepest = i, overlap
+		}
+	}
+
+	if touching >= 0 {
+		g.nearMiss = touching
+		g.nearMissGlow += deltaTime * nearMissFadeIn
+		if g.nearMissGlow > 1 {
+			g.nearMissGlow = 1
+		}
+		return
+	}
+	g.nearMissGlow -= deltaTime * nearMissFadeOut
+	if g.nearMissGlow < 0 {
+		g.nearMissGlow = 0
+	}
+}
+
+// Swallowing something heavy slows the hole down for a moment
+const (
+	heavyObjectSize       = 33   // Large and up
+	defaultWeightPenalty  = 0.35 // Fraction of speed lost
+	defaultWeightRecovery = 1.0  // Seconds
+	maxWeightPenalty      = 0.8  // Whatever the setting, the hole keeps some speed
+)
+
+// addWeight slows the player after eating an object of the given size.
+// Overlapping meals don't stack; the bigger slowdown wins.
+func (g *Game) addWeight(objectSize float32) {
+	if objectSize < heavyObjectSize {
+		return
 	}
+	if penalty := g.weightPenalty(); penalty > g.slowdown {
+		g.slowdown = penalty
+	}
+}
+
+// weightPenalty is WeightPenalty kept within 0..maxWeightPenalty
+func (g *Game) weightPenalty() float32 {
+	if g.WeightPenalty < 0 {
+		return 0
+	}
+	if g.WeightPenalty > maxWeightPenalty {
+		return maxWeightPenalty
+	}
+	return g.WeightPenalty
+}
+
+// updateWeight wins back the speed lost to heavy meals, all of it within
+// WeightRecovery seconds of the last one
+func (g *Game) updateWeight(deltaTime float32) {
+	if g.slowdown <= 0 {
+		return
+	}
+	if g.WeightRecovery <= 0 {
+		g.slowdown = 0
+		return
+	}
+	g.slowdown -= deltaTime * g.weightPenalty() / g.WeightRecovery
+	if g.slowdown < 0 {
+		g.slowdown = 0
+	}
+}
+
+// Screen shake when swallowing something big
+const (
+	shakeMinObjectSize = 48   // Extra-large and up
+	shakeDuration      = 0.4  // Seconds
+	shakePerSize       = 0.15 // Peak offset in pixels per unit of object size
+)
 
-	// Keep player in bounds
-	if g.Player.Position.X < g.Player.Size {
-		g.Player.Position.X = g.Player.Size
+// startShake kicks off a camera shake scaled to the eaten object's size. A
+// smaller bite during a bigger shake doesn't cut it short.
+func (g *Game) startShake(objectSize float32) {
+	magnitude := objectSize * shakePerSize
+	if g.shakeTime > 0 && g.shakeMagnitude > magnitude {
+		return
+	}
+	g.shakeTime = shakeDuration
+	g.shakeMagnitude = magnitude
+}
+
+// shakeOffset returns this frame's camera jitter, fading out over the shake.
+// It is exactly zero once the shake ends.
+func (g *Game) shakeOffset() rl.Vector2 {
+	if g.shakeTime <= 0 {
+		return rl.Vector2{}
+	}
+	strength := g.shakeMagnitude * g.shakeTime / shakeDuration
+	return rl.Vector2{
+		X: (rand.Float32()*2 - 1) * strength,
+		Y: (rand.Float32()*2 - 1) * strength,
+	}
+}
+
+// matchOver reports whether the current mode's end condition has been met
+func (g *Game) matchOver() bool {
+	if g.practice || g.suddenDeath {
+		return false
 	}
-	if g.Player.Position.X > worldWidth-g.Player.Size {
-		g.Player.Position.X = worldWidth - g.Player.Size
+	switch g.Mode {
+	case ModeSurvival:
+		return g.GameTime-g.lastMealTime >= survivalStarveTime
+	case ModeTargetScore:
+		return g.leadingScore() >= g.TargetScore
+	default:
+		return g.GameTime >= g.MaxGameTime
+	}
+}
+
+// startSuddenDeath sends a timed multiplayer match into overtime when the
+// biggest holes are tied, instead of letting the standings pick a winner at
+// random. Only the host calls it, once per match; it reports whether overtime
+// began.
+func (g *Game) startSuddenDeath() bool {
+	if !g.IsHost || g.Mode != ModeTimed || g.suddenDeathPlayed || g.networkPlayerCount() == 0 {
+		return false
 	}
-	if g.Player.Position.Y < g.Player.Size {
-		g.Player.Position.Y = g.Player.Size
+
+	results := g.getGameResults()
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Size > results[j].Size })
+	var tied []int
+	for _, result := range results {
+		if results[0].Size-result.Size < suddenDeathEpsilon {
+			tied = append(tied, result.ID)
+		}
 	}
-	if g.Player.Position.Y > worldHeight-g.Player.Size {
-		g.Player.Position.Y = worldHeight - g.Player.Size
+	if len(tied) < 2 {
+		return false
 	}
 
-	// Adaptive camera zoom based on hole size
-	targetZoom := g.BaseZoom
-	if g.Player.Size > 50 {
-		// Gradually zoom out as hole gets bigger
-		zoomFactor := 50.0 / g.Player.Size
-		if zoomFactor < 0.2 {
-			zoomFactor = 0.2 // Minimum zoom
-		}
-		targetZoom = zoomFactor
-	}
+	call := SuddenDeath{Players: tied, Duration: suddenDeathTime.Milliseconds()}
+	g.broadcastMessage(NetworkMessage{Type: "sudden_death", PlayerID: g.PlayerID, Data: call})
+	g.beginSuddenDeath(call)
+	return true
+}
 
-	// Smooth zoom transition
-	g.Camera.Zoom += (targetZoom - g.Camera.Zoom) * deltaTime * 2.0
+// beginSuddenDeath starts the overtime the host called, bringing us back from
+// the standings if our own clock had already ended the match
+func (g *Game) beginSuddenDeath(call SuddenDeath) {
+	duration := time.Duration(call.Duration) * time.Millisecond
+	if duration <= 0 || duration > suddenDeathTime {
+		duration = suddenDeathTime
+	}
+	g.suddenDeath = true
+	g.suddenDeathPlayed = true
+	g.suddenDeathIDs = call.Players
+	g.suddenDeathEnds = time.Now().Add(duration)
+	g.suddenDeathWinner = 0
+	if g.State == StateGameOver {
+		g.State = StateGameplay
+		g.captureCursor()
+	}
+}
 
-	// Update camera to follow player and handle window resizing
-	g.Camera.Offset = rl.Vector2{X: float32(screenWidth) / 2, Y: float32(screenHeight) / 2}
-	g.Camera.Target = rl.Vector2{X: g.Player.Position.X, Y: g.Player.Position.Y}
+// endSuddenDeath stops overtime; the match ends on the next frame
+func (g *Game) endSuddenDeath(winnerID int) {
+	g.suddenDeath = false
+	g.suddenDeathWinner = winnerID
+}
 
-	// Update particles
-	for i := len(g.Particles) - 1; i >= 0; i-- {
-		g.Particles[i].Life -= deltaTime
-		g.Particles[i].Position.X += g.Particles[i].Velocity.X * deltaTime
-		g.Particles[i].Position.Y += g.Particles[i].Velocity.Y * deltaTime
-		g.Particles[i].Velocity.X *= 0.98 // Damping
-		g.Particles[i].Velocity.Y *= 0.98
+// finishSuddenDeath ends overtime for everyone (host only)
+func (g *Game) finishSuddenDeath(winnerID int) {
+	g.broadcastMessage(NetworkMessage{Type: "sudden_death_over", PlayerID: g.PlayerID, Data: SuddenDeathOver{WinnerID: winnerID}})
+	g.endSuddenDeath(winnerID)
+}
 
-		if g.Particles[i].Life <= 0 {
-			// Remove dead particle
-			g.Particles = append(g.Particles[:i], g.Particles[i+1:]...)
-		}
+// suddenDeathMeal hands overtime to eaterID if they are one of the tied
+// players. Only the host rules on eats, so only the host decides.
+func (g *Game) suddenDeathMeal(eaterID int) {
+	if g.IsHost && g.suddenDeath && g.inSuddenDeath(eaterID) {
+		g.finishSuddenDeath(eaterID)
 	}
+}
 
-	// Animate object rotation
-	for i := range g.Objects {
-		if g.Objects[i].Active {
-			g.Objects[i].Rotation += deltaTime * 30.0
+// syncSuddenDeath ends overtime that ran out on the host, and on a client
+// applies the host's overtime calls
+func (g *Game) syncSuddenDeath() {
+	if g.IsHost {
+		if g.suddenDeath && time.Now().After(g.suddenDeathEnds) {
+			g.finishSuddenDeath(0)
 		}
+		return
 	}
 
-	// Check collisions and consume objects
-	for i := range g.Objects {
-		if !g.Objects[i].Active {
-			continue
-		}
-
-		// Calculate distance between hole and object
-		dx := g.Player.Position.X - g.Objects[i].Position.X
-		dy := g.Player.Position.Y - g.Objects[i].Position.Y
-		distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
-
-		// Check if object can be consumed
-		if distance < g.Player.Size && g.Player.Size > g.Objects[i].Size*0.8 {
-			// Add particles at consumption point
-			g.addParticle(g.Objects[i].Position, g.Objects[i].Color)
+	g.netMu.Lock()
+	call, over := g.hostSuddenDeath, g.hostSuddenDeathOver
+	g.hostSuddenDeath, g.hostSuddenDeathOver = nil, nil
+	g.netMu.Unlock()
+	if call != nil {
+		g.beginSuddenDeath(*call)
+	}
+	if over != nil {
+		g.endSuddenDeath(over.WinnerID)
+	}
+}
 
-			g.Objects[i].Active = false
-			g.Player.Score += g.Objects[i].Value
+// clearSuddenDeath forgets any overtime, for a new match
+func (g *Game) clearSuddenDeath() {
+	g.suddenDeath = false
+	g.suddenDeathPlayed = false
+	g.suddenDeathIDs = nil
+	g.suddenDeathWinner = 0
+	g.netMu.Lock()
+	g.hostSuddenDeath = nil
+	g.hostSuddenDeathOver = nil
+	g.netMu.Unlock()
+}
 
-			// Grow the hole (heavily nerfed for longer progression)
-			growthAmount := float32(g.Objects[i].Value) * 0.02 // Reduced from 0.5 to 0.02
-			// Add diminishing returns for larger holes
-			if g.Player.Size > 50 {
-				growthAmount *= 0.7
-			}
-			if g.Player.Size > 100 {
-				growthAmount *= 0.5
-			}
-			if g.Player.Size > 200 {
-				growthAmount *= 0.3
-			}
-			g.Player.Size += growthAmount
+// inSuddenDeath reports whether id is one of the players who can win overtime
+func (g *Game) inSuddenDeath(id int) bool {
+	for _, contender := range g.suddenDeathIDs {
+		if contender == id {
+			return true
 		}
 	}
+	return false
+}
 
-	// Send network updates every 10 frames (6 times per second)
-	if g.State == StateGameplay && (g.IsHost || g.ServerConn != nil) {
-		if int(g.GameTime*60)%10 == 0 { // 60 FPS, every 10 frames
-			g.sendPlayerUpdate()
+// leadingScore returns the best score among the player, bots and network players
+func (g *Game) leadingScore() int {
+	best := g.Player.Score
+	for _, bot := range g.Bots {
+		if bot.Hole.Score > best {
+			best = bot.Hole.Score
+		}
+	}
+	for _, player := range g.networkPlayersSnapshot() {
+		if player.Hole.Score > best {
+			best = player.Hole.Score
 		}
 	}
+	return best
+}
+
+// rankMetric names what the mode's standings are ordered by
+func (g *Game) rankMetric() string {
+	if g.Mode == ModeTargetScore {
+		return "score"
+	}
+	return "size"
+}
 
+// winCondition describes how the last match was decided, for the game over screen
+func (g *Game) winCondition() string {
+	switch g.Mode {
+	case ModeSurvival:
+		return fmt.Sprintf("Survived %.0fs - ends after %.0fs without eating", g.GameTime, survivalStarveTime)
+	case ModeTargetScore:
+		return fmt.Sprintf("First to %d points wins", g.TargetScore)
+	default:
+		return fmt.Sprintf("Biggest hole after %.0f seconds wins", g.MaxGameTime)
+	}
 }
 
 func (g *Game) drawGradientCircle(x float32, y float32, radius float32, innerColor rl.Color, outerColor rl.Color) {
@@ -813,29 +5647,71 @@ func (g *Game) drawGradientCircle(x float32, y float32, radius float32, innerCol
 }
 
 type PlayerResult struct {
-	Name  string
-	Size  float32
-	Score int
+	ID           int
+	Name         string
+	Size         float32
+	Score        int
+	BiggestCatch Catch
+	ObjectsEaten int
 }
 
 func (g *Game) getGameResults() []PlayerResult {
 	results := []PlayerResult{
-		{Name: "You", Size: g.Player.Size, Score: g.Player.Score},
+		{ID: g.PlayerID, Name: "You", Size: g.Player.Size, Score: g.Player.Score, BiggestCatch: g.biggestCatch, ObjectsEaten: g.objectsEaten},
 	}
 
-	for _, player := range g.NetworkPlayers {
+	for _, player := range g.networkPlayersSnapshot() {
+		results = append(results, PlayerResult{
+			ID:           player.ID,
+			Name:         player.Name,
+			Size:         player.Hole.Size,
+			Score:        player.Hole.Score,
+			BiggestCatch: player.BiggestCatch,
+			ObjectsEaten: player.ObjectsEaten,
+		})
+	}
+	for _, bot := range g.Bots {
 		results = append(results, PlayerResult{
-			Name: player.Name,
-			Size: player.Hole.Size,
-			Score: player.Hole.Score,
+			Name:         bot.Name,
+			Size:         bot.Hole.Size,
+			Score:        bot.Hole.Score,
+			ObjectsEaten: bot.Eaten,
 		})
 	}
 
-	// Sort by size (descending)
-	for i := 0; i < len(results)-1; i++ {
-		for j := i + 1; j < len(results); j++ {
-			if results[j].Size > results[i].Size {
-				results[i], results[j] = results[j], results[i]
+	// Sort by size (descending), or by score when racing to a target, or by
+	// objects eaten when the player asked for that ranking. Ties fall back to
+	// size, then score, then player ID, so every machine in a match gives the
+	// same standings; names differ between them, since ours is always "You".
+	// Bots all have ID 0 and are told apart by name.
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if g.rankByEaten && a.ObjectsEaten != b.ObjectsEaten {
+			return a.ObjectsEaten > b.ObjectsEaten
+		}
+		if g.Mode == ModeTargetScore && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Name < b.Name
+	})
+
+	// Whoever won sudden-death overtime beats the players they were tied with
+	if g.suddenDeathWinner != 0 && !g.rankByEaten {
+		for i := range results {
+			if results[i].ID == g.suddenDeathWinner {
+				winner := results[i]
+				copy(results[1:i+1], results[:i])
+				results[0] = winner
+				break
 			}
 		}
 	}
@@ -844,40 +5720,91 @@ func (g *Game) getGameResults() []PlayerResult {
 }
 
 func (g *Game) handleGameOverInput() {
-	if rl.IsKeyPressed(rl.KeyEnter) || rl.IsKeyPressed(rl.KeySpace) {
-		// If we were in multiplayer mode, return to lobby for easy LAN party mode
-		if g.IsHost || g.ServerConn != nil {
-			g.State = StateLobby
-			// Reset game state but keep network connections
-			g.GameTime = 0
-			g.LobbyReady = false
-			g.GameStarted = false
-			// Generate new objects for next game
-			g.generateObjects()
-			// Reset player but keep network players connected
-			g.Player = Hole{
-				Position:  Vector2{X: worldWidth / 2, Y: worldHeight / 2},
-				Size:      20.0,
-				Score:     0,
-				Speed:     200.0,
-				Animation: 0.0,
-			}
-		} else {
-			// Single player mode - return to menu
-			g.State = StateMenu
-			g.MenuSelection = 0
-			// Reset for next match
-			g.GameTime = 0
-			g.NetworkPlayers = make(map[int]*NetworkPlayer)
-			g.LobbyReady = false
-			g.GameStarted = false
+	if rl.IsKeyPressed(rl.KeyTab) {
+		g.rankByEaten = !g.rankByEaten
+	}
+	if g.inMultiplayer() {
+		// Multiplayer rounds move on together when the host says so
+		if backPressed() {
+			g.leaveToMenu()
+		}
+		return
+	}
+	if rl.IsKeyPressed(rl.KeyR) && g.lastReplay != "" {
+		if err := g.watchReplay(g.lastReplay); err != nil {
+			fmt.Printf("Failed to play replay: %v\n", err)
+		}
+		return
+	}
+	if confirmPressed() || rl.IsKeyPressed(rl.KeySpace) {
+		// Single player mode - return to menu
+		g.State = StateMenu
+		g.MenuSelection = 0
+		// Reset for next match
+		g.GameTime = 0
+		g.netMu.Lock()
+		g.NetworkPlayers = make(map[int]*NetworkPlayer)
+		g.netMu.Unlock()
+		g.LobbyReady = false
+		g.GameStarted = false
+	}
+}
+
+// nextRoundDue moves a multiplayer match back to the lobby for another round.
+// The host decides once the standings have been up for roundStandingsTime and
+// sends round_reset with a new seed; clients follow when it arrives, even if
+// their own clock hadn't ended the match yet.
+func (g *Game) nextRoundDue() bool {
+	if g.IsHost {
+		if g.State != StateGameOver || time.Since(g.roundOverAt) < roundStandingsTime {
+			return false
 		}
+		seed := time.Now().UnixNano()
+		g.broadcastMessage(NetworkMessage{Type: "round_reset", PlayerID: g.PlayerID, Data: RoundReset{WorldSeed: seed}})
+		g.resetRound(seed)
+		g.sendLobbyUpdate()
+		return true
 	}
+
+	g.netMu.Lock()
+	due, seed := g.roundReset, g.hostSeed
+	g.roundReset = false
+	g.netMu.Unlock()
+	if !due {
+		return false
+	}
+	g.resetRound(seed)
+	return true
+}
+
+// resetRound returns to the lobby on a fresh field built from seed, keeping
+// every network connection
+func (g *Game) resetRound(seed int64) {
+	// A client can still be mid-match when the host moves on
+	g.finishRecording()
+	g.State = StateLobby
+	g.LobbyReady = false
+	g.GameStarted = false
+	enableCursor()
+
+	// Reset player but keep network players connected
+	g.resetMatchState(seed)
 }
 
 func (g *Game) drawMenu() {
 	rl.BeginDrawing()
 
+	if g.attractGame != nil {
+		// Attract mode - demo match playing behind a dimmed title
+		g.attractGame.drawWorld()
+		rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 120})
+		rl.DrawText("HOLE.IO CLONE", screenWidth/2-150, 100, 50, rl.White)
+		rl.DrawText("DEMO", screenWidth/2-35, 160, 25, rl.Yellow)
+		rl.DrawText("Press any key", screenWidth/2-75, screenHeight-100, 22, rl.LightGray)
+		rl.EndDrawing()
+		return
+	}
+
 	// Gradient background
 	rl.DrawRectangleGradientV(0, 0, screenWidth, screenHeight,
 		rl.Color{R: 25, G: 25, B: 112, A: 255}, // Midnight blue
@@ -888,9 +5815,20 @@ func (g *Game) drawMenu() {
 	rl.DrawText("Multiplayer Edition", screenWidth/2-120, 160, 25, rl.Gray)
 
 	// Menu options
-	menuOptions := []string{"Single Player", "Host Multiplayer", "Join Multiplayer"}
+	var menuOptions []string
+	if g.hasSave {
+		menuOptions = append(menuOptions, "Continue")
+	}
+	menuOptions = append(menuOptions,
+		"Single Player",
+		"Practice",
+		"Host Multiplayer",
+		"Join Multiplayer",
+		fmt.Sprintf("Name: %s", playerDisplayName(g.PlayerName, g.PlayerID)),
+		"Settings",
+	)
 	for i, option := range menuOptions {
-		y := 250 + i*60
+		y := 195 + i*35
 		color := rl.White
 		if i == g.MenuSelection {
 			color = rl.Yellow
@@ -899,18 +5837,44 @@ func (g *Game) drawMenu() {
 		rl.DrawText(option, screenWidth/2-150, int32(y), 30, color)
 	}
 
+	// Object physics mode
+	rl.DrawText(fmt.Sprintf("Object physics: %s (P to change)", g.Physics), screenWidth/2-150, 445, 18, rl.LightGray)
+	rl.DrawText(fmt.Sprintf("Match mode: %s (T to change)", g.Mode), screenWidth/2-150, 467, 18, rl.LightGray)
+	rl.DrawText(fmt.Sprintf("World: %s, %s objects (Z / O to change)", g.worldSize().Name, g.objectDensity().Name), screenWidth/2-150, 489, 18, rl.LightGray)
+
 	// Input text box for IP address
 	if g.InputActive {
-		rl.DrawRectangle(screenWidth/2-150, 450, 300, 40, rl.Color{R: 50, G: 50, B: 50, A: 200})
-		rl.DrawRectangleLines(screenWidth/2-150, 450, 300, 40, rl.White)
-		rl.DrawText("Server IP:", screenWidth/2-140, 460, 20, rl.White)
-		rl.DrawText(g.InputText, screenWidth/2-140, 480, 16, rl.LightGray)
-		rl.DrawText("Press ENTER to connect, ESC to cancel", screenWidth/2-120, 500, 14, rl.Gray)
+		box := inputBoxRect()
+		rl.DrawRectangleRec(box, rl.Color{R: 50, G: 50, B: 50, A: 200})
+		rl.DrawRectangleLinesEx(box, 1, rl.White)
+		label := "Server IP:"
+		hint := "Press ENTER to continue, ESC to cancel"
+		text := g.InputText
+		switch g.InputTarget {
+		case InputPlayerName:
+			label = "Player Name:"
+			hint = "Press ENTER to save, ESC to cancel"
+		case InputHostPassword:
+			label = "Room Password (blank = open):"
+			hint = "Press ENTER to host, ESC to cancel"
+			text = strings.Repeat("*", len(g.InputText))
+		case InputJoinPassword:
+			label = "Room Password:"
+			hint = "Press ENTER to connect, ESC to cancel"
+			text = strings.Repeat("*", len(g.InputText))
+		}
+		rl.DrawText(label, screenWidth/2-140, 518, 20, rl.White)
+		rl.DrawText(text, screenWidth/2-140, 538, 16, rl.LightGray)
+		rl.DrawText(hint, screenWidth/2-120, 558, 14, rl.Gray)
 	}
 
 	// Show LAN IP for hosting
-	rl.DrawText(fmt.Sprintf("Your LAN IP: %s:8080", g.LocalIP), screenWidth/2-100, 550, 18, rl.Yellow)
-	rl.DrawText("(Share this IP with friends to join your game)", screenWidth/2-140, 575, 14, rl.LightGray)
+	rl.DrawText(fmt.Sprintf("Your LAN IP: %s:8080", g.LocalIP), screenWidth/2-100, 583, 18, rl.Yellow)
+	rl.DrawText("(Share this IP with friends to join your game)", screenWidth/2-140, 608, 14, rl.LightGray)
+
+	if g.MenuMessage != "" {
+		rl.DrawText(g.MenuMessage, screenWidth/2-rl.MeasureText(g.MenuMessage, 20)/2, 638, 20, rl.Red)
+	}
 
 	// Instructions
 	rl.DrawText("Use UP/DOWN arrows and ENTER to select", screenWidth/2-160, screenHeight-100, 18, rl.Gray)
@@ -936,6 +5900,7 @@ func (g *Game) drawLobby() {
 		rl.DrawText("JOINED LOBBY", screenWidth/2-110, 50, 40, rl.Green)
 		rl.DrawText(fmt.Sprintf("Connected to: %s", g.ServerIP), screenWidth/2-120, 100, 18, rl.White)
 	}
+	rl.DrawText(fmt.Sprintf("Transport: %s", g.Transport), screenWidth/2-100, 125, 16, rl.LightGray)
 
 	// Player list
 	rl.DrawText("PLAYERS:", 50, 150, 30, rl.White)
@@ -948,25 +5913,38 @@ func (g *Game) drawLobby() {
 		readyStatus = "READY"
 		readyColor = rl.Green
 	}
-	rl.DrawText(fmt.Sprintf("You (Player %d) - %s", g.PlayerID, readyStatus), 60, int32(yPos), 24, readyColor)
+	rl.DrawText(fmt.Sprintf("You (%s) - %s", playerDisplayName(g.PlayerName, g.PlayerID), readyStatus), 60, int32(yPos), 24, readyColor)
 	yPos += 35
 
 	// Draw network players
-	for _, player := range g.NetworkPlayers {
-		rl.DrawText(fmt.Sprintf("%s - CONNECTED", player.Name), 60, int32(yPos), 24, player.Color)
+	networkPlayers := g.networkPlayersSnapshot()
+	for i, player := range networkPlayers {
+		line := fmt.Sprintf("%s - CONNECTED", player.Name)
+		if player.RTT > 0 {
+			line += fmt.Sprintf(" (%dms)", player.RTT.Milliseconds())
+		}
+		if g.IsHost {
+			// Numbered so the host can pick a player to kick
+			line = fmt.Sprintf("%d. %s", i+1, line)
+			if g.KickSelection == i+1 {
+				rl.DrawRectangleLines(55, int32(yPos)-4, 500, 32, rl.Red)
+			}
+		}
+		rl.DrawText(line, 60, int32(yPos), 24, player.Color)
 		yPos += 35
 	}
 
 	// Status and instructions
-	playerCount := len(g.NetworkPlayers) + 1
-	rl.DrawText(fmt.Sprintf("Players: %d/%d minimum", playerCount, g.MinPlayers), 50, 400, 20, rl.White)
+	playerCount := len(networkPlayers) + 1
+	rl.DrawText(fmt.Sprintf("Players: %d/%d (minimum %d)", playerCount, g.MaxPlayers, g.MinPlayers), 50, 400, 20, rl.White)
+	rl.DrawText(fmt.Sprintf("Growth pace: %s   Physics: %s   World: %s, %s objects   Match: %.0f min", g.growthCurve().Name, g.Physics, g.worldSize().Name, g.objectDensity().Name, g.matchDuration()/60), 50, 425, 18, rl.LightGray)
 
 	if g.IsHost {
 		if playerCount >= g.MinPlayers {
 			if g.LobbyReady {
 				rl.DrawText("READY TO START! Game will begin shortly...", 50, 450, 20, rl.Green)
 			} else {
-				rl.DrawText("Press SPACE to ready up and start the game", 50, 450, 20, rl.Yellow)
+				rl.DrawText(fmt.Sprintf("Press %s to ready up and start the game", keyName(g.Bindings[ActionReady])), 50, 450, 20, rl.Yellow)
 			}
 		} else {
 			rl.DrawText(fmt.Sprintf("Waiting for %d more players...", g.MinPlayers-playerCount), 50, 450, 20, rl.Orange)
@@ -975,12 +5953,18 @@ func (g *Game) drawLobby() {
 		if g.LobbyReady {
 			rl.DrawText("READY - Waiting for host to start", 50, 450, 20, rl.Green)
 		} else {
-			rl.DrawText("Press SPACE to ready up", 50, 450, 20, rl.Yellow)
+			rl.DrawText(fmt.Sprintf("Press %s to ready up", keyName(g.Bindings[ActionReady])), 50, 450, 20, rl.Yellow)
 		}
 	}
 
+	g.drawChat()
+
 	// Controls
-	rl.DrawText("SPACE - Ready/Unready", 50, screenHeight-80, 18, rl.Gray)
+	rl.DrawText(fmt.Sprintf("%s - Ready/Unready, %s - Chat", keyName(g.Bindings[ActionReady]), keyName(g.Bindings[ActionChat])), 50, screenHeight-80, 18, rl.Gray)
+	if g.IsHost {
+		rl.DrawText("1-9 - Select player, K - Kick selected, G - Growth pace", 50, screenHeight-110, 18, rl.Gray)
+		rl.DrawText("Z - World size, O - Object density, U - Transport, L - Match length", 50, screenHeight-140, 18, rl.Gray)
+	}
 	rl.DrawText("ESC - Return to Menu", 50, screenHeight-50, 18, rl.Gray)
 
 	// Connection indicator
@@ -989,16 +5973,94 @@ func (g *Game) drawLobby() {
 	} else {
 		connStatus := "◆ CONNECTED"
 		connColor := rl.Green
-		if g.ServerConn == nil {
+		if g.serverConn() == nil {
 			connStatus = "◆ DISCONNECTED"
 			connColor = rl.Red
 		}
 		rl.DrawText(connStatus, screenWidth-150, 20, 20, connColor)
 	}
 
+	g.drawDebugOverlay()
+	g.drawReconnecting()
 	rl.EndDrawing()
 }
 
+// drawChat draws the lobby chat log and, while typing, the input line
+func (g *Game) drawChat() {
+	const x, y, width = 700, 150, 450
+
+	g.netMu.RLock()
+	chatLog := append([]ChatMessage(nil), g.chatLog...)
+	g.netMu.RUnlock()
+
+	rl.DrawText("CHAT:", x, y, 24, rl.White)
+	rl.DrawRectangle(x, y+30, width, chatHistory*24+10, rl.Color{R: 0, G: 0, B: 0, A: 120})
+	for i, chat := range chatLog {
+		line := fmt.Sprintf("%s: %s", chat.Name, chat.Text)
+		rl.DrawText(line, x+8, y+36+int32(i)*24, 16, rl.LightGray)
+	}
+
+	inputY := y + 50 + chatHistory*24
+	if g.ChatActive {
+		rl.DrawRectangleLines(x, int32(inputY), width, 28, rl.White)
+		rl.DrawText(g.chatInput+"_", x+8, int32(inputY)+6, 16, rl.White)
+		rl.DrawText("ENTER to send, ESC to cancel", x, int32(inputY)+34, 14, rl.Gray)
+	}
+}
+
+// drawDebugOverlay shows frame rate, world and network stats in the top-right
+// corner when toggled with F3
+func (g *Game) drawDebugOverlay() {
+	if !g.ShowDebug {
+		return
+	}
+
+	activeObjects := 0
+	for _, obj := range g.Objects {
+		if obj.Active {
+			activeObjects++
+		}
+	}
+	rtt := "n/a"
+	if g.serverConn() != nil {
+		if d := time.Duration(g.hostRTT.Load()); d > 0 {
+			rtt = fmt.Sprintf("%dms", d.Milliseconds())
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("FPS: %d", rl.GetFPS()),
+		fmt.Sprintf("Particles: %d", len(g.Particles)),
+		fmt.Sprintf("Objects: %d", activeObjects),
+		fmt.Sprintf("Players: %d", g.networkPlayerCount()+1),
+		fmt.Sprintf("RTT: %s", rtt),
+	}
+	shadowColor := rl.Color{R: 0, G: 0, B: 0, A: 150}
+	x := screenWidth - 160
+	for i, line := range lines {
+		y := 40 + int32(i)*20
+		rl.DrawText(line, x+2, y+2, 18, shadowColor)
+		rl.DrawText(line, x, y, 18, rl.Lime)
+	}
+}
+
+// drawReconnecting covers the screen while a dropped client redials the host,
+// and with a notice once the host is gone for good
+func (g *Game) drawReconnecting() {
+	if !g.hostGoneAt.IsZero() {
+		rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 180})
+		text := "Host disconnected - returning to menu"
+		rl.DrawText(text, screenWidth/2-rl.MeasureText(text, 40)/2, screenHeight/2-20, 40, rl.Red)
+		return
+	}
+	if !g.reconnecting.Load() {
+		return
+	}
+	rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 180})
+	text := "Reconnecting..."
+	rl.DrawText(text, screenWidth/2-rl.MeasureText(text, 40)/2, screenHeight/2-20, 40, rl.Yellow)
+}
+
 func (g *Game) drawGameOver() {
 	rl.BeginDrawing()
 
@@ -1014,7 +6076,9 @@ func (g *Game) drawGameOver() {
 	results := g.getGameResults()
 
 	// Show final results
-	rl.DrawText("FINAL RESULTS", screenWidth/2-120, 120, 30, rl.Yellow)
+	rl.DrawText("FINAL RESULTS", screenWidth/2-120, 105, 30, rl.Yellow)
+	modeText := fmt.Sprintf("%s mode: %s", g.Mode, g.winCondition())
+	rl.DrawText(modeText, screenWidth/2-rl.MeasureText(modeText, 18)/2, 145, 18, rl.LightGray)
 
 	// Show top 3 players prominently
 	rl.DrawText("TOP 3 PLAYERS", screenWidth/2-100, 180, 25, rl.Yellow)
@@ -1032,6 +6096,9 @@ func (g *Game) drawGameOver() {
 		case 0:
 			rankColor = rl.Gold
 			prefix = "🥇 WINNER! "
+			if g.suddenDeathWinner != 0 && result.ID == g.suddenDeathWinner && !g.rankByEaten {
+				prefix = "🥇 SUDDEN DEATH WINNER! "
+			}
 			fontSize = 32
 		case 1:
 			rankColor = rl.Color{R: 192, G: 192, B: 192, A: 255} // Silver
@@ -1043,6 +6110,11 @@ func (g *Game) drawGameOver() {
 
 		text := fmt.Sprintf("%s%s - Size: %.1f, Score: %d", prefix, result.Name, result.Size, result.Score)
 		rl.DrawText(text, 50, int32(yPos), fontSize, rankColor)
+		details := fmt.Sprintf("Objects eaten: %d", result.ObjectsEaten)
+		if result.BiggestCatch.Type != "" {
+			details += ", Biggest catch: " + result.BiggestCatch.String()
+		}
+		rl.DrawText(details, 80, int32(yPos)+fontSize+1, 14, rl.LightGray)
 		yPos += 50
 	}
 
@@ -1051,7 +6123,10 @@ func (g *Game) drawGameOver() {
 		rl.DrawText("Other Players:", 50, int32(yPos+20), 20, rl.Gray)
 		for i := 3; i < len(results) && i < 8; i++ {
 			result := results[i]
-			text := fmt.Sprintf("%d. %s - Size: %.1f, Score: %d", i+1, result.Name, result.Size, result.Score)
+			text := fmt.Sprintf("%d. %s - Size: %.1f, Score: %d, Eaten: %d", i+1, result.Name, result.Size, result.Score, result.ObjectsEaten)
+			if result.BiggestCatch.Type != "" {
+				text += ", Biggest catch: " + result.BiggestCatch.String()
+			}
 			rl.DrawText(text, 60, int32(yPos+50+(i-3)*25), 18, rl.LightGray)
 		}
 	}
@@ -1060,18 +6135,52 @@ func (g *Game) drawGameOver() {
 	rl.DrawText("YOUR STATS:", 50, int32(yPos+40), 20, rl.Yellow)
 	rl.DrawText(fmt.Sprintf("Final Size: %.1f", g.Player.Size), 60, int32(yPos+70), 18, rl.White)
 	rl.DrawText(fmt.Sprintf("Final Score: %d", g.Player.Score), 60, int32(yPos+95), 18, rl.White)
+	rl.DrawText(fmt.Sprintf("Biggest catch: %s", g.biggestCatch), 60, int32(yPos+120), 18, rl.White)
+	eatenText := fmt.Sprintf("Objects eaten: %d", g.objectsEaten)
+	if g.objectsEaten > 0 {
+		eatenText += fmt.Sprintf(" (%.1f points each)", float32(g.Player.Score)/float32(g.objectsEaten))
+	}
+	rl.DrawText(eatenText, 60, int32(yPos+145), 18, rl.White)
 
 	// Calculate rank
 	rank := 1
 	for _, result := range results {
-		if result.Name != "You" && result.Size > g.Player.Size {
+		ahead := result.Size > g.Player.Size
+		if g.rankByEaten {
+			ahead = result.ObjectsEaten > g.objectsEaten
+		} else if g.suddenDeathWinner != 0 {
+			// Overtime settles the tie at the top
+			ahead = result.ID == g.suddenDeathWinner || (ahead && g.PlayerID != g.suddenDeathWinner)
+		}
+		if result.Name != "You" && ahead {
 			rank++
 		}
 	}
-	rl.DrawText(fmt.Sprintf("Your Rank: #%d", rank), 60, int32(yPos+120), 18, rl.Green)
+	rl.DrawText(fmt.Sprintf("Your Rank: #%d", rank), 60, int32(yPos+170), 18, rl.Green)
+
+	rankText := "TAB: rank by objects eaten"
+	if g.rankByEaten {
+		rankText = "TAB: rank by " + g.rankMetric()
+	}
+	rl.DrawText(rankText, screenWidth/2-rl.MeasureText(rankText, 18)/2, screenHeight-130, 18, rl.Gray)
 
 	// Instructions
-	rl.DrawText("Press ENTER or SPACE to return to menu", screenWidth/2-180, screenHeight-100, 20, rl.LightGray)
+	if g.inMultiplayer() {
+		remaining := roundStandingsTime - time.Since(g.roundOverAt)
+		if remaining < 0 {
+			remaining = 0
+		}
+		text := fmt.Sprintf("Back to the lobby in %d... (ESC to leave)", int(math.Ceil(remaining.Seconds())))
+		rl.DrawText(text, screenWidth/2-rl.MeasureText(text, 20)/2, screenHeight-100, 20, rl.LightGray)
+	} else {
+		rl.DrawText("Press ENTER or SPACE to return to menu", screenWidth/2-180, screenHeight-100, 20, rl.LightGray)
+		if g.lastReplay != "" {
+			text := fmt.Sprintf("Press R to watch the replay (saved to %s)", g.lastReplay)
+			rl.DrawText(text, screenWidth/2-rl.MeasureText(text, 18)/2, screenHeight-70, 18, rl.Gray)
+		}
+	}
+
+	g.drawReconnecting()
 
 	rl.EndDrawing()
 }
@@ -1089,17 +6198,90 @@ func (g *Game) draw() {
 		g.drawGameOver()
 		return
 	}
+	if g.State == StateSettings {
+		g.drawSettings()
+		return
+	}
+	if g.State == StateControls {
+		g.drawControls()
+		return
+	}
+	if g.State == StateReplay {
+		g.drawReplay()
+		return
+	}
+	rl.BeginDrawing()
+	g.drawWorld()
+	g.drawHUD()
+	if g.State == StatePaused {
+		g.drawPauseMenu()
+	}
+	g.drawDebugOverlay()
+	g.drawReconnecting()
+	rl.EndDrawing()
+}
+
+// drawReplay draws the recorded run with a playback banner instead of the HUD
+func (g *Game) drawReplay() {
 	rl.BeginDrawing()
+	g.drawWorld()
+
+	last := g.playback.Frames[len(g.playback.Frames)-1]
+	elapsed := g.playbackTime - g.playback.Frames[0].T
+	total := last.T - g.playback.Frames[0].T
+	rl.DrawRectangle(0, 0, screenWidth, 40, rl.Color{R: 0, G: 0, B: 0, A: 150})
+	rl.DrawText("REPLAY", 10, 10, 20, rl.Red)
+	rl.DrawText(fmt.Sprintf("%d:%02d / %d:%02d", int(elapsed)/60, int(elapsed)%60, int(total)/60, int(total)%60), 110, 10, 20, rl.White)
+	rl.DrawText(fmt.Sprintf("Score: %d   Size: %.1f", g.Player.Score, g.Player.Size), 260, 10, 20, rl.White)
+	if g.playback.Truncated {
+		rl.DrawText("(recording was cut short)", 520, 12, 16, rl.LightGray)
+	}
+	rl.DrawText("ESC - Stop", screenWidth-110, 10, 20, rl.Gray)
+
+	g.drawDebugOverlay()
+	rl.EndDrawing()
+}
+
+// drawPauseMenu draws the pause overlay on top of the frozen match
+func (g *Game) drawPauseMenu() {
+	rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 150})
+	rl.DrawText("PAUSED", screenWidth/2-90, screenHeight/2-120, 50, rl.White)
+	if g.inMultiplayer() {
+		rl.DrawText("The match keeps running for everyone else", screenWidth/2-170, screenHeight/2-60, 16, rl.LightGray)
+	}
+
+	for i, option := range g.pauseItems() {
+		y := screenHeight/2 - 20 + int32(i)*50
+		color := rl.White
+		if i == g.PauseSelection {
+			color = rl.Yellow
+			rl.DrawText(">", screenWidth/2-130, y, 30, rl.Yellow)
+		}
+		rl.DrawText(option, screenWidth/2-100, y, 30, color)
+	}
+}
 
+// drawWorld draws the background and everything in world space
+func (g *Game) drawWorld() {
 	// Gradient background
 	rl.DrawRectangleGradientV(0, 0, screenWidth, screenHeight,
 		rl.Color{R: 135, G: 206, B: 235, A: 255}, // Sky blue
 		rl.Color{R: 25, G: 25, B: 112, A: 255})   // Midnight blue
 
-	rl.BeginMode2D(g.Camera)
+	// Shake a copy so the jitter never accumulates into the real camera
+	camera := g.Camera
+	if g.State != StatePaused {
+		offset := g.shakeOffset()
+		camera.Target.X += offset.X / camera.Zoom
+		camera.Target.Y += offset.Y / camera.Zoom
+	}
+	rl.BeginMode2D(camera)
 
 	// Draw world bounds with thicker, more visible border
-	rl.DrawRectangleLinesEx(rl.Rectangle{X: 0, Y: 0, Width: worldWidth, Height: worldHeight}, 4, rl.White)
+	rl.DrawRectangleLinesEx(rl.Rectangle{X: 0, Y: 0, Width: g.WorldWidth, Height: g.WorldHeight}, 4, rl.White)
+	if g.EdgeGlow {
+		g.drawEdgeGlow()
+	}
 
 	// Draw objects with improved visuals
 	for _, obj := range g.Objects {
@@ -1109,6 +6291,15 @@ func (g *Game) draw() {
 				rl.Color{R: 0, G: 0, B: 0, A: 50})
 
 			// Draw main object with type-specific rendering
+			if texture, ok := g.objectTexture(obj.Type); ok {
+				rl.DrawTexturePro(texture,
+					rl.Rectangle{X: 0, Y: 0, Width: float32(texture.Width), Height: float32(texture.Height)},
+					rl.Rectangle{X: obj.Position.X, Y: obj.Position.Y, Width: obj.Size * 2, Height: obj.Size * 2},
+					rl.Vector2{X: obj.Size, Y: obj.Size},
+					obj.Rotation,
+					rl.White)
+				continue
+			}
 			switch obj.Type {
 			case "tiny":
 				// Tiny objects - draw as small diamonds
@@ -1120,6 +6311,25 @@ func (g *Game) draw() {
 					rl.Vector2{X: obj.Size/2, Y: obj.Size*0.75},
 					obj.Rotation,
 					obj.Color)
+			case "powerup":
+				// Power-ups - pulsing glow around a lettered orb
+				glow := obj.Color
+				glow.A = uint8(90 + 60*math.Sin(float64(obj.Rotation)*0.1))
+				rl.DrawCircle(int32(obj.Position.X), int32(obj.Position.Y), obj.Size*1.8, glow)
+				rl.DrawCircle(int32(obj.Position.X), int32(obj.Position.Y), obj.Size, obj.Color)
+				letter := obj.PowerUp.String()[:1]
+				rl.DrawText(letter, int32(obj.Position.X)-rl.MeasureText(letter, 12)/2, int32(obj.Position.Y)-6, 12, rl.Black)
+			case obstacleType:
+				// Rocks and walls - heavy outlined heptagons, cracked while still too big to eat
+				center := rl.Vector2{X: obj.Position.X, Y: obj.Position.Y}
+				rl.DrawPoly(center, 7, obj.Size, obj.Rotation, obj.Color)
+				rl.DrawPolyLinesEx(center, 7, obj.Size, obj.Rotation, 3, rl.Color{R: 60, G: 50, B: 40, A: 255})
+				if !bigEnoughToEat(g.Player.Size, &obj) {
+					rl.DrawLineEx(
+						rl.Vector2{X: obj.Position.X - obj.Size*0.5, Y: obj.Position.Y - obj.Size*0.2},
+						rl.Vector2{X: obj.Position.X + obj.Size*0.4, Y: obj.Position.Y + obj.Size*0.3},
+						2, rl.Color{R: 60, G: 50, B: 40, A: 200})
+				}
 			case "medium-small":
 				// Bikes, benches - draw as hexagons
 				rl.DrawPoly(rl.Vector2{X: obj.Position.X, Y: obj.Position.Y}, 6, obj.Size, obj.Rotation, obj.Color)
@@ -1137,6 +6347,14 @@ func (g *Game) draw() {
 		}
 	}
 
+	// Outline what the hole is bumping into but can't eat yet
+	if g.nearMissGlow > 0 && g.nearMiss < len(g.Objects) && g.Objects[g.nearMiss].Active {
+		obj := g.Objects[g.nearMiss]
+		shake := float32(math.Sin(float64(g.GameTime)*45)) * nearMissShake * g.nearMissGlow
+		rl.DrawRing(rl.Vector2{X: obj.Position.X + shake, Y: obj.Position.Y}, obj.Size+1, obj.Size+3, 0, 360, 36,
+			rl.Color{R: 230, G: 40, B: 40, A: uint8(160 * g.nearMissGlow)})
+	}
+
 	// Draw particles
 	for _, particle := range g.Particles {
 		alpha := uint8(255.0 * (particle.Life / particle.MaxLife))
@@ -1145,7 +6363,24 @@ func (g *Game) draw() {
 		rl.DrawCircle(int32(particle.Position.X), int32(particle.Position.Y), particle.Size, color)
 	}
 
+	// Draw score popups
+	for _, popup := range g.ScorePopups {
+		alpha := uint8(255.0 * (popup.Life / popup.MaxLife))
+		text := fmt.Sprintf("+%d", popup.Value)
+		fontSize := scorePopupFontSize(popup.Value)
+		textX := int32(popup.Position.X) - rl.MeasureText(text, fontSize)/2
+		textY := int32(popup.Position.Y) - fontSize/2
+		rl.DrawText(text, textX+2, textY+2, fontSize, rl.Color{R: 0, G: 0, B: 0, A: alpha / 2})
+		rl.DrawText(text, textX, textY, fontSize, rl.Color{R: 255, G: 255, B: 255, A: alpha})
+	}
+
 	// Draw player hole with enhanced visuals
+	for kind := PowerUpSpeed; kind < powerUpCount; kind++ {
+		if g.powerUpActive(kind) {
+			g.drawPowerUpGlow(g.Player.Position, g.Player.Size, kind)
+		}
+	}
+
 	// Event horizon effect
 	eventHorizon := g.Player.Size * 1.2
 	g.drawGradientCircle(g.Player.Position.X, g.Player.Position.Y, eventHorizon,
@@ -1168,28 +6403,76 @@ func (g *Game) draw() {
 		rl.DrawCircle(int32(x), int32(y), 2, rl.Color{R: 100, G: 100, B: 100, A: 150})
 	}
 
-	// Draw network players
-	for _, player := range g.NetworkPlayers {
-		// Draw player hole with their color
-		eventHorizon := player.Hole.Size * 1.2
-		g.drawGradientCircle(player.Hole.Position.X, player.Hole.Position.Y, eventHorizon,
-			rl.Color{R: 0, G: 0, B: 0, A: 0},
-			rl.Color{R: player.Color.R / 4, G: player.Color.G / 4, B: player.Color.B / 4, A: 150})
-
-		// Main hole with player color tint
-		pulse := 1.0 + float32(math.Sin(float64(player.Hole.Animation)*3.0))*0.1
-		g.drawGradientCircle(player.Hole.Position.X, player.Hole.Position.Y, player.Hole.Size*pulse,
-			rl.Color{R: 0, G: 0, B: 0, A: 255},
-			rl.Color{R: player.Color.R / 8, G: player.Color.G / 8, B: player.Color.B / 8, A: 255})
+	// Draw network players where they appear to be between updates
+	now := time.Now()
+	for _, player := range g.networkPlayersSnapshot() {
+		hole := player.Hole
+		hole.Position = player.renderPosition(now, g.WorldWidth, g.WorldHeight)
+		if now.Before(player.PowerUpUntil) {
+			g.drawPowerUpGlow(hole.Position, hole.Size, player.PowerUp)
+		}
+		g.drawOpponentHole(hole, player.Name, player.Color)
+	}
 
-		// Player name tag
-		nameX := player.Hole.Position.X - float32(len(player.Name)*3)
-		nameY := player.Hole.Position.Y - player.Hole.Size - 20
-		rl.DrawText(player.Name, int32(nameX), int32(nameY), 16, player.Color)
+	// Draw single-player bots
+	for _, bot := range g.Bots {
+		g.drawOpponentHole(bot.Hole, bot.Name, bot.Color)
 	}
 
 	rl.EndMode2D()
+}
+
+// drawEdgeGlow reddens the strip along each world edge the player is closing
+// in on, stronger the closer it gets
+func (g *Game) drawEdgeGlow() {
+	h := g.Player
+	glow := func(gap float32) rl.Color {
+		strength := 1 - gap/edgeWarningDistance
+		if strength <= 0 {
+			return rl.Blank
+		}
+		if strength > 1 {
+			strength = 1
+		}
+		return rl.Color{R: 230, G: 40, B: 40, A: uint8(120 * strength)}
+	}
+
+	if color := glow(h.Position.X - h.Size); color.A > 0 {
+		rl.DrawRectangleGradientH(0, 0, edgeGlowWidth, int32(g.WorldHeight), color, rl.Blank)
+	}
+	if color := glow(g.WorldWidth - h.Size - h.Position.X); color.A > 0 {
+		rl.DrawRectangleGradientH(int32(g.WorldWidth)-edgeGlowWidth, 0, edgeGlowWidth, int32(g.WorldHeight), rl.Blank, color)
+	}
+	if color := glow(h.Position.Y - h.Size); color.A > 0 {
+		rl.DrawRectangleGradientV(0, 0, int32(g.WorldWidth), edgeGlowWidth, color, rl.Blank)
+	}
+	if color := glow(g.WorldHeight - h.Size - h.Position.Y); color.A > 0 {
+		rl.DrawRectangleGradientV(0, int32(g.WorldHeight)-edgeGlowWidth, int32(g.WorldWidth), edgeGlowWidth, rl.Blank, color)
+	}
+}
+
+// drawOpponentHole draws another player's or bot's hole tinted with its color
+func (g *Game) drawOpponentHole(hole Hole, name string, color rl.Color) {
+	// Draw player hole with their color
+	eventHorizon := hole.Size * 1.2
+	g.drawGradientCircle(hole.Position.X, hole.Position.Y, eventHorizon,
+		rl.Color{R: 0, G: 0, B: 0, A: 0},
+		rl.Color{R: color.R / 4, G: color.G / 4, B: color.B / 4, A: 150})
+
+	// Main hole with player color tint
+	pulse := 1.0 + float32(math.Sin(float64(hole.Animation)*3.0))*0.1
+	g.drawGradientCircle(hole.Position.X, hole.Position.Y, hole.Size*pulse,
+		rl.Color{R: 0, G: 0, B: 0, A: 255},
+		rl.Color{R: color.R / 8, G: color.G / 8, B: color.B / 8, A: 255})
+
+	// Player name tag
+	nameX := hole.Position.X - float32(len(name)*3)
+	nameY := hole.Position.Y - hole.Size - 20
+	rl.DrawText(name, int32(nameX), int32(nameY), 16, color)
+}
 
+// drawHUD draws the screen-space gameplay overlay
+func (g *Game) drawHUD() {
 	// Enhanced UI
 	uiColor := rl.White
 	shadowColor := rl.Color{R: 0, G: 0, B: 0, A: 150}
@@ -1201,23 +6484,49 @@ func (g *Game) draw() {
 	rl.DrawText(fmt.Sprintf("Size: %.1f", g.Player.Size), 12, 42, 20, shadowColor)
 	rl.DrawText(fmt.Sprintf("Size: %.1f", g.Player.Size), 10, 40, 20, uiColor)
 
-	timeLeft := g.MaxGameTime - g.GameTime
-	if timeLeft > 0 {
-		timeColor := uiColor
-		if timeLeft < 30 {
-			// Flash red when time is running out
-			flash := float32(math.Sin(float64(g.GameTime)*10.0))
-			if flash > 0 {
-				timeColor = rl.Red
+	switch {
+	case g.practice:
+		text := fmt.Sprintf("Practice - %s to reset objects, ESC for menu", keyName(g.Bindings[ActionPracticeReset]))
+		rl.DrawText(text, 12, 72, 20, shadowColor)
+		rl.DrawText(text, 10, 70, 20, uiColor)
+	case g.Mode == ModeSurvival:
+		hunger := survivalStarveTime - (g.GameTime - g.lastMealTime)
+		hungerColor := uiColor
+		if hunger < 5 {
+			hungerColor = rl.Red
+		}
+		rl.DrawText(fmt.Sprintf("Eat within: %.1fs", hunger), 12, 72, 20, shadowColor)
+		rl.DrawText(fmt.Sprintf("Eat within: %.1fs", hunger), 10, 70, 20, hungerColor)
+	case g.Mode == ModeTargetScore:
+		rl.DrawText(fmt.Sprintf("Target: %d", g.TargetScore), 12, 72, 20, shadowColor)
+		rl.DrawText(fmt.Sprintf("Target: %d", g.TargetScore), 10, 70, 20, uiColor)
+	default:
+		timeLeft := g.MaxGameTime - g.GameTime
+		if g.suddenDeath {
+			overtime := math.Max(0, time.Until(g.suddenDeathEnds).Seconds())
+			text := fmt.Sprintf("SUDDEN DEATH: the leaders are tied (%.0fs)", overtime)
+			if g.inSuddenDeath(g.PlayerID) {
+				text = fmt.Sprintf("SUDDEN DEATH: eat anything to win! (%.0fs)", overtime)
+			}
+			rl.DrawText(text, 12, 72, 20, shadowColor)
+			rl.DrawText(text, 10, 70, 20, rl.Red)
+		} else if timeLeft > 0 {
+			timeColor := uiColor
+			if timeLeft < 30 {
+				// Flash red when time is running out
+				flash := float32(math.Sin(float64(g.GameTime)*10.0))
+				if flash > 0 {
+					timeColor = rl.Red
+				}
 			}
+			rl.DrawText(fmt.Sprintf("Time: %.1fs", timeLeft), 12, 72, 20, shadowColor)
+			rl.DrawText(fmt.Sprintf("Time: %.1fs", timeLeft), 10, 70, 20, timeColor)
+		} else {
+			// Game over screen
+			rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 150})
+			rl.DrawText("GAME OVER!", screenWidth/2-100, screenHeight/2-20, 40, rl.Red)
+			rl.DrawText(fmt.Sprintf("Final Score: %d", g.Player.Score), screenWidth/2-80, screenHeight/2+30, 20, rl.White)
 		}
-		rl.DrawText(fmt.Sprintf("Time: %.1fs", timeLeft), 12, 72, 20, shadowColor)
-		rl.DrawText(fmt.Sprintf("Time: %.1fs", timeLeft), 10, 70, 20, timeColor)
-	} else {
-		// Game over screen
-		rl.DrawRectangle(0, 0, screenWidth, screenHeight, rl.Color{R: 0, G: 0, B: 0, A: 150})
-		rl.DrawText("GAME OVER!", screenWidth/2-100, screenHeight/2-20, 40, rl.Red)
-		rl.DrawText(fmt.Sprintf("Final Score: %d", g.Player.Score), screenWidth/2-80, screenHeight/2+30, 20, rl.White)
 	}
 
 	// Zoom indicator
@@ -1226,26 +6535,134 @@ func (g *Game) draw() {
 	}
 
 	// Show multiplayer info
-	if len(g.NetworkPlayers) > 0 {
-		rl.DrawText(fmt.Sprintf("Players: %d", len(g.NetworkPlayers)+1), screenWidth-120, 12, 18, shadowColor)
-		rl.DrawText(fmt.Sprintf("Players: %d", len(g.NetworkPlayers)+1), screenWidth-122, 10, 18, uiColor)
+	if networkPlayers := g.networkPlayerCount(); networkPlayers > 0 {
+		rl.DrawText(fmt.Sprintf("Players: %d", networkPlayers+1), screenWidth-120, 12, 18, shadowColor)
+		rl.DrawText(fmt.Sprintf("Players: %d", networkPlayers+1), screenWidth-122, 10, 18, uiColor)
 	}
 
-	rl.DrawText("WASD or Mouse to move", 12, screenHeight-23, 16, shadowColor)
-	rl.DrawText("WASD or Mouse to move", 10, screenHeight-25, 16, rl.Color{R: 200, G: 200, B: 200, A: 255})
+	hint := fmt.Sprintf("%s%s%s%s or Mouse to move, %s for map",
+		keyName(g.Bindings[ActionMoveUp]), keyName(g.Bindings[ActionMoveLeft]),
+		keyName(g.Bindings[ActionMoveDown]), keyName(g.Bindings[ActionMoveRight]),
+		keyName(g.Bindings[ActionMinimap]))
+	rl.DrawText(hint, 12, screenHeight-23, 16, shadowColor)
+	rl.DrawText(hint, 10, screenHeight-25, 16, rl.Color{R: 200, G: 200, B: 200, A: 255})
 
-	rl.EndDrawing()
+	if g.ShowMinimap {
+		g.drawMinimap()
+	}
+	g.drawPowerUpIcons()
+
+	if g.Settings.ShowCursor && g.State == StateGameplay && g.Settings.MouseSensitivity > 0 {
+		drawReticle(rl.GetMousePosition())
+	}
+
+	// Multiplayer start countdown
+	if remaining := g.countdownRemaining(); remaining > 0 {
+		count := fmt.Sprintf("%d", int(math.Ceil(remaining.Seconds())))
+		rl.DrawText(count, screenWidth/2-rl.MeasureText(count, 120)/2, screenHeight/2-80, 120, rl.Yellow)
+		rl.DrawText("Get ready!", screenWidth/2-rl.MeasureText("Get ready!", 30)/2, screenHeight/2+50, 30, rl.White)
+	} else if g.inMultiplayer() && g.GameTime < 0.75 {
+		rl.DrawText("GO!", screenWidth/2-rl.MeasureText("GO!", 120)/2, screenHeight/2-80, 120, rl.Green)
+	}
+}
+
+// drawPowerUpGlow rings a hole in the color of an active power-up
+func (g *Game) drawPowerUpGlow(pos Vector2, size float32, kind PowerUp) {
+	glow := kind.Color()
+	glow.A = 120
+	g.drawGradientCircle(pos.X, pos.Y, size*1.5, rl.Color{R: glow.R, G: glow.G, B: glow.B, A: 0}, glow)
+}
+
+// drawPowerUpIcons lists the active effects with their remaining seconds
+func (g *Game) drawPowerUpIcons() {
+	x := int32(25)
+	for kind := PowerUpSpeed; kind < powerUpCount; kind++ {
+		if !g.powerUpActive(kind) {
+			continue
+		}
+		remaining := fmt.Sprintf("%.0fs", math.Ceil(float64(g.powerUpEnds[kind]-g.GameTime)))
+		letter := kind.String()[:1]
+		rl.DrawCircle(x, 115, 14, kind.Color())
+		rl.DrawText(letter, x-rl.MeasureText(letter, 16)/2, 107, 16, rl.Black)
+		rl.DrawText(remaining, x-rl.MeasureText(remaining, 14)/2+1, 135, 14, rl.Color{R: 0, G: 0, B: 0, A: 150})
+		rl.DrawText(remaining, x-rl.MeasureText(remaining, 14)/2, 134, 14, rl.White)
+		x += 40
+	}
+}
+
+// Minimap layout, in screen pixels
+const (
+	minimapWidth         = 200
+	minimapMargin        = 10
+	minimapMinObjectSize = 21 // Medium-large and up; smaller objects would just be noise
+)
+
+// drawMinimap draws the whole world scaled into the bottom-right corner
+func (g *Game) drawMinimap() {
+	scale := float32(minimapWidth) / g.WorldWidth
+	height := int32(g.WorldHeight * scale)
+	x := screenWidth - minimapWidth - minimapMargin
+	y := screenHeight - height - minimapMargin
+
+	rl.DrawRectangle(x, y, minimapWidth, height, rl.Color{R: 0, G: 0, B: 0, A: 150})
+	rl.DrawRectangleLines(x, y, minimapWidth, height, rl.White)
+
+	toMap := func(pos Vector2) (int32, int32) {
+		return x + int32(pos.X*scale), y + int32(pos.Y*scale)
+	}
+	dotSize := func(size, minRadius float32) float32 {
+		if size*scale < minRadius {
+			return minRadius
+		}
+		return size * scale
+	}
+
+	// Big objects worth heading for
+	for _, obj := range g.Objects {
+		if obj.Active && obj.Size >= minimapMinObjectSize {
+			mapX, mapY := toMap(obj.Position)
+			rl.DrawCircle(mapX, mapY, dotSize(obj.Size, 1.5), obj.Color)
+		}
+	}
+
+	// Opponents
+	for _, bot := range g.Bots {
+		mapX, mapY := toMap(bot.Hole.Position)
+		rl.DrawCircle(mapX, mapY, dotSize(bot.Hole.Size, 3), bot.Color)
+	}
+	now := time.Now()
+	for _, player := range g.networkPlayersSnapshot() {
+		mapX, mapY := toMap(player.renderPosition(now, g.WorldWidth, g.WorldHeight))
+		rl.DrawCircle(mapX, mapY, dotSize(player.Hole.Size, 3), player.Color)
+	}
+
+	// Local player on top, outlined so it stands out
+	mapX, mapY := toMap(g.Player.Position)
+	radius := dotSize(g.Player.Size, 3)
+	rl.DrawCircle(mapX, mapY, radius+1, rl.White)
+	rl.DrawCircle(mapX, mapY, radius, rl.Black)
 }
 
 func main() {
 	rl.InitWindow(screenWidth, screenHeight, "Hole.io Clone - Raylib Go")
 	rl.SetWindowState(rl.FlagWindowResizable)
-	rl.SetTargetFPS(60)
+	rl.InitAudioDevice()
 
 	game := NewGame()
+	game.loadAssets()
+
+	// Escape pauses and backs out of menus instead of closing the window
+	rl.SetExitKey(rl.KeyNull)
+
+	// A replay file on the command line is played straight away
+	if len(os.Args) > 1 {
+		if err := game.watchReplay(os.Args[1]); err != nil {
+			fmt.Printf("Failed to play replay: %v\n", err)
+		}
+	}
 
-	for !rl.WindowShouldClose() {
-		deltaTime := rl.GetFrameTime()
+	for !rl.WindowShouldClose() && !game.Quit {
+		deltaTime := clampFrameTime(rl.GetFrameTime())
 
 		// Update screen dimensions if window was resized
 		if rl.IsWindowResized() {
@@ -1259,5 +6676,8 @@ func main() {
 		game.draw()
 	}
 
+	game.unloadAssets()
+	game.Sounds.Unload()
+	rl.CloseAudioDevice()
 	rl.CloseWindow()
 }
\ No newline at end of file
@@ -0,0 +1,965 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// pressKeys makes keyPressed report the given keys and stubs out the cursor,
+// so input handling can run without a window
+func pressKeys(t *testing.T, keys ...int32) {
+	t.Helper()
+	oldPressed, oldEnable, oldDisable := keyPressed, enableCursor, disableCursor
+	t.Cleanup(func() {
+		keyPressed, enableCursor, disableCursor = oldPressed, oldEnable, oldDisable
+	})
+	keyPressed = func(key int32) bool {
+		for _, k := range keys {
+			if k == key {
+				return true
+			}
+		}
+		return false
+	}
+	enableCursor = func() {}
+	disableCursor = func() {}
+}
+
+func TestBackPressResumesFromPause(t *testing.T) {
+	pressKeys(t, rl.KeyEscape)
+	g := &Game{State: StatePaused}
+
+	g.update(1.0 / 60)
+
+	if g.State != StateGameplay {
+		t.Fatalf("state after one back press while paused = %v, want StateGameplay", g.State)
+	}
+}
+
+func TestAttractionOnlyPullsObjectsThatFit(t *testing.T) {
+	g := &Game{
+		Physics: PhysicsSlippery,
+		Player:  Hole{Position: Vector2{X: 500, Y: 500}, Size: 40, Speed: 200},
+		Objects: []GameObject{
+			{Position: Vector2{X: 560, Y: 500}, Size: 30, Active: true}, // Fits inside
+			{Position: Vector2{X: 440, Y: 500}, Size: 45, Active: true}, // Edible but wider than the hole
+		},
+	}
+	g.rebuildObjectGrid()
+
+	for i := 0; i < 600; i++ {
+		g.applyObjectAttraction(1.0 / 60)
+	}
+
+	if !canConsume(&g.Player, &g.Objects[0], g.Physics) {
+		t.Errorf("small object at %v was not pulled inside the hole", g.Objects[0].Position)
+	}
+	if g.Objects[1].Position != (Vector2{X: 440, Y: 500}) {
+		t.Errorf("object too wide to drop moved to %v", g.Objects[1].Position)
+	}
+}
+
+func TestAttractionFalloff(t *testing.T) {
+	const radius = 100
+	for _, c := range []struct {
+		distance, want float32
+	}{
+		{0, 1},
+		{25, 0.5625},
+		{50, 0.25},
+		{90, 0.01},
+		{100, 0},
+		{150, 0},
+	} {
+		if got := attractionFalloff(c.distance, radius); math.Abs(float64(got-c.want)) > 1e-6 {
+			t.Errorf("attractionFalloff(%v, %v) = %v, want %v", c.distance, radius, got, c.want)
+		}
+	}
+	if got := attractionFalloff(10, 0); got != 0 {
+		t.Errorf("a hole with no radius pulls %v", got)
+	}
+}
+
+func TestAttractionIsTheSameOnEveryPeer(t *testing.T) {
+	holes := map[int]Hole{
+		1: {Position: Vector2{X: 500, Y: 500}, Size: 40},
+		2: {Position: Vector2{X: 620, Y: 520}, Size: 60},
+	}
+	// peer builds the match as player id sees it
+	peer := func(id int) *Game {
+		g := &Game{PlayerID: id, Physics: PhysicsSlippery, Player: holes[id], NetworkPlayers: make(map[int]*NetworkPlayer)}
+		for other, hole := range holes {
+			if other != id {
+				g.NetworkPlayers[other] = &NetworkPlayer{ID: other, Hole: hole}
+			}
+		}
+		for i := 0; i < 20; i++ {
+			g.Objects = append(g.Objects, GameObject{Position: Vector2{X: 420 + float32(i)*15, Y: 470 + float32(i%4)*20}, Size: 5, Active: true})
+		}
+		g.rebuildObjectGrid()
+		return g
+	}
+
+	first, second := peer(1), peer(2)
+	for frame := 0; frame < 120; frame++ {
+		first.applyObjectAttraction(1.0 / 60)
+	}
+	// The other peer renders twice as fast
+	for frame := 0; frame < 240; frame++ {
+		second.applyObjectAttraction(1.0 / 120)
+	}
+
+	moved := false
+	for i := range first.Objects {
+		if first.Objects[i].Position != second.Objects[i].Position {
+			t.Fatalf("object %d at %v on one peer, %v on the other", i, first.Objects[i].Position, second.Objects[i].Position)
+		}
+		moved = moved || first.Objects[i].Velocity != (Vector2{})
+	}
+	if !moved {
+		t.Error("nothing was pulled")
+	}
+}
+
+func TestClientAdoptsHostPhysics(t *testing.T) {
+	c := &Game{PlayerID: 2, Physics: PhysicsClassic, NetworkPlayers: make(map[int]*NetworkPlayer)}
+	c.processNetworkMessage(NetworkMessage{Type: "lobby_update", PlayerID: 1, Data: LobbyUpdate{WorldSeed: 42, Physics: PhysicsHeavy}})
+	c.syncWorldSeed()
+	if c.Physics != PhysicsHeavy {
+		t.Errorf("client physics = %v, want the host's Heavy", c.Physics)
+	}
+}
+
+func TestMagnetOnlyPullsInSinglePlayer(t *testing.T) {
+	for _, host := range []bool{false, true} {
+		g := &Game{
+			IsHost:  host,
+			Player:  Hole{Position: Vector2{X: 500, Y: 500}, Size: 40},
+			Objects: []GameObject{{Position: Vector2{X: 600, Y: 500}, Size: 10, Active: true}},
+		}
+		g.powerUpEnds[PowerUpMagnet] = 10
+		g.rebuildObjectGrid()
+
+		g.updatePowerUps(0.1)
+
+		moved := g.Objects[0].Position != (Vector2{X: 600, Y: 500})
+		if moved == host {
+			t.Errorf("host=%v: magnet moved object = %v", host, moved)
+		}
+	}
+}
+
+func TestWorldSeedSurvivesTheWire(t *testing.T) {
+	// A realistic UnixNano seed, well past float64's 53 bits of precision
+	const seed int64 = 1760577600123456789
+
+	tests := []struct {
+		msgType string
+		data    interface{}
+		decoded func([]byte) (int64, error)
+	}{
+		{"lobby_update", LobbyUpdate{WorldSeed: seed}, func(b []byte) (int64, error) {
+			var v LobbyUpdate
+			err := json.Unmarshal(b, &v)
+			return v.WorldSeed, err
+		}},
+		{"round_reset", RoundReset{WorldSeed: seed}, func(b []byte) (int64, error) {
+			var v RoundReset
+			err := json.Unmarshal(b, &v)
+			return v.WorldSeed, err
+		}},
+		{"world_state", WorldState{WorldSeed: seed}, func(b []byte) (int64, error) {
+			var v WorldState
+			err := json.Unmarshal(b, &v)
+			return v.WorldSeed, err
+		}},
+	}
+	for _, tt := range tests {
+		wire := bytes.NewReader(encodeMessage(NetworkMessage{Type: tt.msgType, PlayerID: 1, Data: tt.data}))
+		handled := false
+		err := readMessages(wire, func(msg NetworkMessage) {
+			handled = true
+			// Decoded the way processNetworkMessage does
+			data, _ := json.Marshal(msg.Data)
+			got, err := tt.decoded(data)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.msgType, err)
+			}
+			if got != seed {
+				t.Errorf("%s: seed %d, want %d", tt.msgType, got, seed)
+			}
+		})
+		if err != nil || !handled {
+			t.Fatalf("%s: handled=%v err=%v", tt.msgType, handled, err)
+		}
+	}
+}
+
+// newTestHost returns a host game serving on a loopback listener
+func newTestHost(t *testing.T, maxPlayers int) (*Game, net.Listener) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	g := &Game{
+		IsHost:         true,
+		PlayerID:       1,
+		MaxPlayers:     maxPlayers,
+		NetworkPlayers: make(map[int]*NetworkPlayer),
+		clientByID:     make(map[int]net.Conn),
+		udpPeers:       make(map[int]*net.UDPAddr),
+	}
+	go g.acceptClients(listener)
+	return g, listener
+}
+
+func TestConcurrentJoinsDontOverfillLobby(t *testing.T) {
+	const maxPlayers = 4
+	g, listener := newTestHost(t, maxPlayers)
+
+	// Every socket is accepted before any of them says who it is
+	joiners := maxPlayers + 2
+	conns := make([]net.Conn, joiners)
+	for i := range conns {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns[i] = conn
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	replies := make([]string, joiners)
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(i int, conn net.Conn) {
+			defer wg.Done()
+			conn.Write(encodeMessage(NetworkMessage{Type: "lobby_update", PlayerID: 100 + i, Data: LobbyUpdate{Name: "joiner"}}))
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			var msg NetworkMessage
+			if line, err := bufio.NewReader(conn).ReadBytes('\n'); err == nil {
+				json.Unmarshal(line, &msg)
+			}
+			replies[i] = msg.Type
+		}(i, conn)
+	}
+	wg.Wait()
+
+	rejected := 0
+	for _, reply := range replies {
+		if reply == "join_rejected" {
+			rejected++
+		}
+	}
+	g.netMu.RLock()
+	admitted := len(g.ClientConns)
+	g.netMu.RUnlock()
+	if admitted != maxPlayers-1 || rejected != joiners-admitted {
+		t.Errorf("admitted %d and rejected %d of %d joiners, want %d admitted with the host in the last slot",
+			admitted, rejected, joiners, maxPlayers-1)
+	}
+}
+
+// joinTestHost connects to listener as player id and returns the connection
+// and the type of the host's first reply
+func joinTestHost(t *testing.T, listener net.Listener, id int) (net.Conn, string) {
+	t.Helper()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.Write(encodeMessage(NetworkMessage{Type: "lobby_update", PlayerID: id, Data: LobbyUpdate{Name: "joiner"}}))
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg NetworkMessage
+	if line, err := bufio.NewReader(conn).ReadBytes('\n'); err == nil {
+		json.Unmarshal(line, &msg)
+	}
+	conn.SetReadDeadline(time.Time{})
+	return conn, msg.Type
+}
+
+func TestReconnectTakesOverStaleSlot(t *testing.T) {
+	// Room for the host and one client
+	g, listener := newTestHost(t, 2)
+
+	old, reply := joinTestHost(t, listener, 5)
+	if reply != "lobby_update" {
+		t.Fatalf("first join got %q", reply)
+	}
+	if _, reply := joinTestHost(t, listener, 6); reply != "join_rejected" {
+		t.Errorf("another player joining a full lobby got %q, want join_rejected", reply)
+	}
+	// The host hasn't noticed player 5 drop when they redial
+	if _, reply := joinTestHost(t, listener, 5); reply != "lobby_update" {
+		t.Fatalf("reconnect into a full lobby got %q, want lobby_update", reply)
+	}
+
+	g.netMu.RLock()
+	clients := len(g.ClientConns)
+	g.netMu.RUnlock()
+	if clients != 1 {
+		t.Errorf("host has %d client connections after the reconnect, want 1", clients)
+	}
+	old.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := bufio.NewReader(old).ReadBytes('\n'); err == nil {
+		t.Error("stale connection was left open")
+	}
+}
+
+func TestLeavingWhileReconnecting(t *testing.T) {
+	pressKeys(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	// A host that keeps hanging up, so the client keeps redialing
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			bufio.NewReader(conn).ReadBytes('\n')
+			conn.Close()
+		}
+	}()
+
+	g := &Game{
+		State:          StateMenu,
+		PlayerID:       7,
+		ServerIP:       listener.Addr().String(),
+		NetworkPlayers: make(map[int]*NetworkPlayer),
+		ReconnectTries: 50,
+		ReconnectDelay: time.Millisecond,
+		WorldSize:      defaultWorldSize,
+		Density:        defaultObjectDensity,
+	}
+	g.connectToServer()
+	for deadline := time.Now().Add(2 * time.Second); g.State != StateLobby; {
+		if time.Now().After(deadline) {
+			t.Fatal("never joined the lobby")
+		}
+		g.joinDialedServer()
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	g.leaveToMenu()
+
+	if g.serverConn() != nil {
+		t.Error("still connected after leaving")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if g.serverConn() != nil {
+		t.Error("reconnect took the connection back after leaving")
+	}
+}
+
+func TestHostDropsMessagesInAnotherPlayersName(t *testing.T) {
+	g, listener := newTestHost(t, 4)
+	conn, reply := joinTestHost(t, listener, 5)
+	if reply != "lobby_update" {
+		t.Fatalf("join got %q", reply)
+	}
+
+	// Player 5 claims an eat as player 6, then asks for one of its own
+	conn.Write(encodeMessage(NetworkMessage{Type: "eat_request", PlayerID: 6, Data: ObjectEaten{Index: 1, EaterID: 6}}))
+	conn.Write(encodeMessage(NetworkMessage{Type: "eat_request", PlayerID: 5, Data: ObjectEaten{Index: 2, EaterID: 6}}))
+
+	var requests []ObjectEaten
+	for deadline := time.Now().Add(2 * time.Second); len(requests) == 0 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+		g.netMu.RLock()
+		requests = append([]ObjectEaten(nil), g.eatRequests...)
+		g.netMu.RUnlock()
+	}
+	if len(requests) != 1 || requests[0] != (ObjectEaten{Index: 2, EaterID: 5}) {
+		t.Errorf("host queued eat requests %+v, want only object 2 for player 5", requests)
+	}
+}
+
+func TestSwallowedPlayerRespawnsOnMainLoop(t *testing.T) {
+	g := &Game{
+		PlayerID:       3,
+		NetworkPlayers: make(map[int]*NetworkPlayer),
+		WorldWidth:     2400,
+		WorldHeight:    1600,
+		Player:         Hole{Position: Vector2{X: 100, Y: 100}, Size: 80, Score: 50},
+	}
+
+	// Arrives on a network goroutine while the main loop is using the hole
+	done := make(chan struct{})
+	go func() {
+		g.processNetworkMessage(NetworkMessage{Type: "player_eaten", PlayerID: 4, Data: PlayerEaten{VictimID: 3, Score: 20}})
+		close(done)
+	}()
+	<-done
+	if g.Player.Size != 80 || g.Player.Score != 50 {
+		t.Fatalf("hole changed off the main loop: size %v, score %d", g.Player.Size, g.Player.Score)
+	}
+
+	g.applyRespawns()
+	if g.Player.Size != holeRespawnSize || g.Player.Score != 30 {
+		t.Errorf("after respawn: size %v, score %d; want %v and 30", g.Player.Size, g.Player.Score, float32(holeRespawnSize))
+	}
+}
+
+func TestHostChecksKillClaims(t *testing.T) {
+	g := &Game{
+		IsHost:   true,
+		PlayerID: 1,
+		NetworkPlayers: map[int]*NetworkPlayer{
+			2: {Hole: Hole{Position: Vector2{X: 100, Y: 100}, Size: 100, Score: 80}},
+			3: {Hole: Hole{Position: Vector2{X: 1500, Y: 900}, Size: 30, Score: 40}},
+		},
+		WorldWidth:  2400,
+		WorldHeight: 1600,
+		Player:      Hole{Position: Vector2{X: 110, Y: 100}, Size: 40, Score: 60},
+	}
+	claim := func(eater, victim int) {
+		g.processNetworkMessage(NetworkMessage{Type: "player_eaten", PlayerID: eater, Data: PlayerEaten{VictimID: victim, Score: 1000}})
+	}
+
+	// Player 3 is smaller than both of its victims and nowhere near them
+	claim(3, 1)
+	claim(3, 2)
+	g.judgeKillClaims()
+	if g.Player.Size != 40 || g.Player.Score != 60 {
+		t.Errorf("bogus claim respawned the host: size %v, score %d", g.Player.Size, g.Player.Score)
+	}
+	if !g.NetworkPlayers[2].EatenAt.IsZero() {
+		t.Error("bogus claim marked player 2 as eaten")
+	}
+
+	// Player 2 really does cover the host, and loses it the host's share, not the claimed one
+	claim(2, 1)
+	g.judgeKillClaims()
+	if g.Player.Size != holeRespawnSize || g.Player.Score != 45 {
+		t.Errorf("after a valid claim: size %v, score %d; want %v and 45", g.Player.Size, g.Player.Score, float32(holeRespawnSize))
+	}
+}
+
+func TestHostLeavingClosesLobby(t *testing.T) {
+	pressKeys(t)
+	g, listener := newTestHost(t, 4)
+	g.listener = listener
+	g.State = StateLobby
+
+	client, reply := joinTestHost(t, listener, 5)
+	if reply != "lobby_update" {
+		t.Fatalf("join got %q", reply)
+	}
+
+	g.leaveToMenu()
+
+	if g.IsHost || len(g.ClientConns) != 0 || len(g.NetworkPlayers) != 0 {
+		t.Errorf("still hosting after leaving: IsHost=%v, %d clients, %d players", g.IsHost, len(g.ClientConns), len(g.NetworkPlayers))
+	}
+	// The client hears why, then the host hangs up
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	reader := bufio.NewReader(client)
+	sawHostLeft := false
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		var msg NetworkMessage
+		json.Unmarshal(line, &msg)
+		sawHostLeft = sawHostLeft || msg.Type == "host_left"
+	}
+	if !sawHostLeft {
+		t.Error("client was never told the host left")
+	}
+	if conn, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+		conn.Close()
+		t.Error("listener still accepting after the host left")
+	}
+
+	// The client side shows the host-disconnected notice instead of redialing
+	c := &Game{PlayerID: 5, NetworkPlayers: make(map[int]*NetworkPlayer)}
+	c.processNetworkMessage(NetworkMessage{Type: "host_left", PlayerID: 1})
+	if !c.hostGone {
+		t.Error("client didn't mark the host gone")
+	}
+}
+
+// originalGrowth is the hard-coded formula the Standard pace replaced
+func originalGrowth(size float32, value int) float32 {
+	growth := float32(value) * 0.02
+	if size > 50 {
+		growth *= 0.7
+	}
+	if size > 100 {
+		growth *= 0.5
+	}
+	if size > 200 {
+		growth *= 0.3
+	}
+	return growth
+}
+
+func TestStandardGrowthMatchesOriginalFormula(t *testing.T) {
+	for _, size := range []float32{10, 50, 50.1, 100, 100.1, 200, 200.1, 500} {
+		for _, value := range []int{1, 5, 25, 100} {
+			if got, want := growthPaces[0].Growth(size, value), originalGrowth(size, value); got != want {
+				t.Errorf("Growth(%v, %d) = %v, want %v", size, value, got, want)
+			}
+		}
+	}
+}
+
+func TestGrowthProgression(t *testing.T) {
+	// A run of meals from tiny objects up to buildings
+	meals := []int{1, 1, 2, 5, 5, 10, 10, 25, 25, 50, 100}
+	grow := func(growth func(size float32, value int) float32) float32 {
+		size := float32(30)
+		for i := 0; i < 400; i++ {
+			for _, value := range meals {
+				size += growth(size, value)
+			}
+		}
+		return size
+	}
+
+	standard := grow(growthPaces[0].Growth)
+	if want := grow(originalGrowth); standard != want {
+		t.Errorf("Standard pace ends at size %v after the meal sequence, want %v", standard, want)
+	}
+	// Every breakpoint kicked in along the way
+	if standard <= 200 {
+		t.Errorf("Standard pace only reached size %v; the sequence should pass every breakpoint", standard)
+	}
+	g := &Game{}
+	for pace, curve := range growthPaces {
+		g.Pace = pace
+		if g.growthCurve().Name != curve.Name {
+			t.Errorf("pace %d picks the %s curve, want %s", pace, g.growthCurve().Name, curve.Name)
+		}
+	}
+	casual, grindy := grow(growthPaces[1].Growth), grow(growthPaces[2].Growth)
+	if !(casual > standard && standard > grindy) {
+		t.Errorf("final sizes casual %v, standard %v, grindy %v; want casual > standard > grindy", casual, standard, grindy)
+	}
+	g.Pace = len(growthPaces)
+	if g.growthCurve().Name != growthPaces[0].Name {
+		t.Errorf("out-of-range pace picks %s, want the default", g.growthCurve().Name)
+	}
+}
+
+func TestReadMessagesSplitsConcatenatedMessages(t *testing.T) {
+	var wire bytes.Buffer
+	wire.Write(encodeMessage(NetworkMessage{Type: "heartbeat", PlayerID: 2}))
+	wire.Write(encodeMessage(NetworkMessage{Type: "chat", PlayerID: 3, Data: ChatMessage{Text: "hi"}}))
+
+	var got []NetworkMessage
+	if err := readMessages(&wire, func(msg NetworkMessage) { got = append(got, msg) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Type != "heartbeat" || got[0].PlayerID != 2 || got[1].Type != "chat" || got[1].PlayerID != 3 {
+		t.Fatalf("decoded %+v, want heartbeat from 2 then chat from 3", got)
+	}
+}
+
+func TestReadMessagesSurvivesPartialReadsAndGarbage(t *testing.T) {
+	var stream []byte
+	stream = append(stream, encodeMessage(NetworkMessage{Type: "ping", PlayerID: 1})...)
+	stream = append(stream, "\n{not json\n"...)
+	stream = append(stream, encodeMessage(NetworkMessage{Type: "pong", PlayerID: 1})...)
+
+	// Fed a byte at a time, the way a slow socket can deliver it
+	server, client := net.Pipe()
+	go func() {
+		for _, b := range stream {
+			client.Write([]byte{b})
+		}
+		client.Close()
+	}()
+
+	var types []string
+	readMessages(server, func(msg NetworkMessage) { types = append(types, msg.Type) })
+	if len(types) != 2 || types[0] != "ping" || types[1] != "pong" {
+		t.Errorf("decoded %v, want [ping pong]", types)
+	}
+}
+
+// collisionField returns a game with n objects spread over a large map and a
+// size-60 hole in the middle
+func collisionField(n int) *Game {
+	g := &Game{
+		WorldWidth:  3600,
+		WorldHeight: 2400,
+		Player:      Hole{Position: Vector2{X: 1800, Y: 1200}, Size: 60},
+	}
+	rng := rand.New(rand.NewSource(1))
+	g.Objects = make([]GameObject, n)
+	for i := range g.Objects {
+		g.Objects[i] = GameObject{
+			Position: Vector2{X: rng.Float32() * g.WorldWidth, Y: rng.Float32() * g.WorldHeight},
+			Size:     float32(5 + rng.Intn(40)),
+			Active:   true,
+		}
+	}
+	g.rebuildObjectGrid()
+	return g
+}
+
+// edibleSink keeps the benchmarks' checks from being optimized away
+var edibleSink int
+
+// BenchmarkCollisionScan is the collision phase as it was: every object, every frame
+func BenchmarkCollisionScan(b *testing.B) {
+	g := collisionField(5000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range g.Objects {
+			if g.Objects[i].Active && canConsume(&g.Player, &g.Objects[i], PhysicsClassic) {
+				edibleSink++
+			}
+		}
+	}
+}
+
+// BenchmarkCollisionGrid only checks objects in the grid cells under the hole
+func BenchmarkCollisionGrid(b *testing.B) {
+	g := collisionField(5000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, i := range g.nearbyObjects(g.Player.Position, g.Player.Size) {
+			if g.Objects[i].Active && canConsume(&g.Player, &g.Objects[i], PhysicsClassic) {
+				edibleSink++
+			}
+		}
+	}
+}
+
+func TestGridFindsWhatTheScanFinds(t *testing.T) {
+	g := collisionField(5000)
+	want := map[int]bool{}
+	for i := range g.Objects {
+		if canConsume(&g.Player, &g.Objects[i], PhysicsClassic) {
+			want[i] = true
+		}
+	}
+	got := map[int]bool{}
+	for _, i := range g.nearbyObjects(g.Player.Position, g.Player.Size) {
+		if canConsume(&g.Player, &g.Objects[i], PhysicsClassic) {
+			got[i] = true
+		}
+	}
+	if len(want) == 0 || len(got) != len(want) {
+		t.Fatalf("grid found %d edible objects, full scan %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i] {
+			t.Errorf("grid missed object %d", i)
+		}
+	}
+}
+
+// TestHostWithConcurrentClients is meant for go test -race: two fake clients
+// talk to a host while the test plays the main loop over the same players
+func TestHostWithConcurrentClients(t *testing.T) {
+	g, listener := newTestHost(t, 4)
+	g.applyWorldSize()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, id := range []int{2, 3} {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		go io.Copy(io.Discard, conn)
+		wg.Add(1)
+		go func(id int, conn net.Conn) {
+			defer wg.Done()
+			conn.Write(encodeMessage(NetworkMessage{Type: "lobby_update", PlayerID: id, Data: LobbyUpdate{Name: "fake"}}))
+			for seq := uint64(1); ; seq++ {
+				select {
+				case <-stop:
+					conn.Write(encodeMessage(NetworkMessage{Type: "player_leave", PlayerID: id}))
+					return
+				default:
+				}
+				conn.Write(encodeMessage(NetworkMessage{Type: "player_update", PlayerID: id, Data: PlayerUpdate{
+					Position: Vector2{X: float32(seq % 1000), Y: 100}, Size: 30, Seq: seq,
+				}}))
+				conn.Write(encodeMessage(NetworkMessage{Type: "heartbeat", PlayerID: id}))
+				time.Sleep(time.Millisecond)
+			}
+		}(id, conn)
+	}
+
+	for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); {
+		// The map can change under the readers, as it does between rounds
+		g.applyWorldSize()
+		g.pruneStalePlayers()
+		g.networkPlayersSnapshot()
+		g.networkPlayerCount()
+		g.sendLobbyUpdate()
+		time.Sleep(time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+
+	if count := g.networkPlayerCount(); count > 2 {
+		t.Errorf("host tracks %d players, want at most 2", count)
+	}
+}
+
+func TestNextRoundStartsClean(t *testing.T) {
+	pressKeys(t)
+	g := &Game{
+		State:         StateGameOver,
+		WorldSize:     defaultWorldSize,
+		Density:       defaultObjectDensity,
+		MatchDuration: 2,
+		MaxGameTime:   42,
+		BaseZoom:      0.4,
+		zoomOffset:    1.5,
+		slowdown:      0.3,
+		respawnBudget: 0.7,
+		GameTime:      119,
+		objectsEaten:  12,
+		Player:        Hole{Position: Vector2{X: 10, Y: 10}, Size: 140, Score: 900},
+	}
+	g.Camera.Zoom = 0.3
+
+	const seed int64 = 1760577600123456789
+	g.resetRound(seed)
+
+	if g.State != StateLobby || g.WorldSeed != seed || len(g.Objects) == 0 {
+		t.Fatalf("state %v, seed %d, %d objects; want the lobby on a field from the new seed", g.State, g.WorldSeed, len(g.Objects))
+	}
+	if g.Player.Size != 20 || g.Player.Score != 0 || g.GameTime != 0 || g.objectsEaten != 0 {
+		t.Errorf("player and match not reset: %+v, time %v, eaten %d", g.Player, g.GameTime, g.objectsEaten)
+	}
+	if g.Camera.Zoom != 1 || g.BaseZoom != 1 || g.zoomOffset != 0 {
+		t.Errorf("camera not reset: zoom %v, base %v, offset %v", g.Camera.Zoom, g.BaseZoom, g.zoomOffset)
+	}
+	if g.slowdown != 0 || g.respawnBudget != 0 {
+		t.Errorf("slowdown %v and respawn budget %v carried into the next round", g.slowdown, g.respawnBudget)
+	}
+	if g.MaxGameTime != matchDurations[2] {
+		t.Errorf("MaxGameTime = %v, want the lobby's %v", g.MaxGameTime, matchDurations[2])
+	}
+}
+
+func TestLoadGameDropsUnknownPowerUps(t *testing.T) {
+	save := SavedGame{
+		Version:   saveVersion,
+		WorldSize: defaultWorldSize,
+		Density:   defaultObjectDensity,
+		Objects: []GameObject{
+			{Position: Vector2{X: 100, Y: 100}, Size: powerUpSize, Active: true, PowerUp: PowerUpMagnet},
+			{Position: Vector2{X: 200, Y: 100}, Size: powerUpSize, Active: true, PowerUp: PowerUp(99)},
+			{Position: Vector2{X: 300, Y: 100}, Size: powerUpSize, Active: true, PowerUp: PowerUp(-1)},
+		},
+	}
+	data, err := json.Marshal(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Game{}
+	if err := g.LoadGame(path); err != nil {
+		t.Fatal(err)
+	}
+	if obj := g.Objects[0]; !obj.Active || obj.PowerUp != PowerUpMagnet {
+		t.Errorf("valid pickup restored as %+v", obj)
+	}
+	for _, obj := range g.Objects[1:] {
+		if obj.Active || obj.PowerUp != PowerUpNone {
+			t.Errorf("unknown pickup left on the field: %+v", obj)
+		}
+	}
+}
+
+func TestReplayCapIsMatchTime(t *testing.T) {
+	for _, fps := range []float32{30, 60, 240} {
+		g := &Game{}
+		for g.GameTime < maxReplayTime+10 {
+			g.recordFrame()
+			g.GameTime += 1 / fps
+		}
+		r := g.recording
+		if !r.Truncated {
+			t.Fatalf("%v FPS: recording not cut short", fps)
+		}
+		if span := r.Frames[len(r.Frames)-1].T - r.Frames[0].T; span < maxReplayTime-1 || span > maxReplayTime {
+			t.Errorf("%v FPS: recorded %v seconds, want about %v", fps, span, maxReplayTime)
+		}
+		if limit := int(maxReplayTime/replayFrameStep) + 1; len(r.Frames) > limit {
+			t.Errorf("%v FPS: %d frames, want at most %d", fps, len(r.Frames), limit)
+		}
+	}
+}
+
+func TestGameStartAppliesMatchLengthOnTheMainLoop(t *testing.T) {
+	c := &Game{State: StateLobby, PlayerID: 5, MaxGameTime: matchDurations[1], NetworkPlayers: make(map[int]*NetworkPlayer)}
+	now := time.Now().UnixMilli()
+	c.processNetworkMessage(NetworkMessage{Type: "game_start", PlayerID: 1, Data: GameStart{StartAt: now, SentAt: now, MaxGameTime: matchDurations[2]}})
+	if c.MaxGameTime != matchDurations[1] {
+		t.Fatalf("MaxGameTime = %v set off the main loop", c.MaxGameTime)
+	}
+	if c.waitForMatchStart(); c.MaxGameTime != matchDurations[2] {
+		t.Errorf("MaxGameTime = %v, want the host's %v", c.MaxGameTime, matchDurations[2])
+	}
+
+	// A length we don't offer leaves the lobby's choice, not the last match's
+	c.State = StateLobby
+	c.MaxGameTime = matchDurations[1]
+	c.processNetworkMessage(NetworkMessage{Type: "game_start", PlayerID: 1, Data: GameStart{StartAt: now, SentAt: now, MaxGameTime: 42}})
+	if c.waitForMatchStart(); c.MaxGameTime != matchDurations[1] {
+		t.Errorf("MaxGameTime = %v, want the lobby's %v", c.MaxGameTime, matchDurations[1])
+	}
+}
+
+func TestMatchStartMovesLobbyOnTheMainLoop(t *testing.T) {
+	for _, msg := range []NetworkMessage{
+		{Type: "game_start", PlayerID: 1, Data: GameStart{StartAt: 4000, SentAt: 1000}},
+		{Type: "lobby_update", PlayerID: 1, Data: LobbyUpdate{GameStarted: true}},
+	} {
+		c := &Game{State: StateLobby, PlayerID: 5, GameTime: 12, lastMealTime: 9, NetworkPlayers: make(map[int]*NetworkPlayer)}
+
+		// Arrives on a network goroutine while the main loop owns the state
+		done := make(chan struct{})
+		go func() {
+			c.processNetworkMessage(msg)
+			close(done)
+		}()
+		<-done
+		if c.State != StateLobby || c.GameTime != 12 || c.lastMealTime != 9 {
+			t.Fatalf("%s changed the game off the main loop: state %v, time %v", msg.Type, c.State, c.GameTime)
+		}
+
+		c.waitForMatchStart()
+		if c.State != StateGameplay || c.GameTime != 0 || c.lastMealTime != 0 {
+			t.Errorf("after %s: state %v, time %v, last meal %v; want gameplay from 0", msg.Type, c.State, c.GameTime, c.lastMealTime)
+		}
+	}
+}
+
+func TestTiedStandingsAgreeAcrossMachines(t *testing.T) {
+	names := map[int]string{1: "Zed", 2: "Amy", 3: "Bob"}
+	// viewFrom is the game as player id sees it: everyone finished level
+	viewFrom := func(id int) *Game {
+		g := &Game{PlayerID: id, Player: Hole{Size: 50, Score: 10}, NetworkPlayers: make(map[int]*NetworkPlayer)}
+		for other, name := range names {
+			if other != id {
+				g.NetworkPlayers[other] = &NetworkPlayer{ID: other, Name: name, Hole: Hole{Size: 50, Score: 10}}
+			}
+		}
+		return g
+	}
+
+	for id := range names {
+		results := viewFrom(id).getGameResults()
+		for i, want := range []int{1, 2, 3} {
+			if results[i].ID != want {
+				t.Errorf("player %d ranks %+v; want IDs 1, 2, 3", id, results)
+				break
+			}
+		}
+	}
+}
+
+func TestScorePopupsRiseAndExpire(t *testing.T) {
+	g := &Game{}
+	g.addScorePopup(Vector2{X: 10, Y: 100}, 5)
+
+	g.updateEffects(scorePopupLife / 2)
+	if len(g.ScorePopups) != 1 {
+		t.Fatalf("%d popups halfway through their life, want 1", len(g.ScorePopups))
+	}
+	if y := g.ScorePopups[0].Position.Y; y >= 100 {
+		t.Errorf("popup at y %v after rising, want above 100", y)
+	}
+	g.updateEffects(scorePopupLife/2 + 0.01)
+	if len(g.ScorePopups) != 0 {
+		t.Errorf("%d popups left after their life ran out", len(g.ScorePopups))
+	}
+
+	for i := 0; i < maxScorePopups+5; i++ {
+		g.addScorePopup(Vector2{}, i)
+	}
+	if len(g.ScorePopups) != maxScorePopups {
+		t.Fatalf("%d popups after a feeding frenzy, want the cap of %d", len(g.ScorePopups), maxScorePopups)
+	}
+	if oldest := g.ScorePopups[0].Value; oldest != 5 {
+		t.Errorf("oldest popup kept is +%d, want +5 after dropping the first five", oldest)
+	}
+}
+
+func TestScorePopupFontSize(t *testing.T) {
+	for _, tc := range []struct {
+		value int
+		want  int32
+	}{
+		{1, scorePopupMinFont},
+		{3, scorePopupMinFont + 1},
+		{30, scorePopupMinFont + 10},
+		{1000, scorePopupMaxFont},
+	} {
+		if got := scorePopupFontSize(tc.value); got != tc.want {
+			t.Errorf("scorePopupFontSize(%d) = %d, want %d", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestFrameTimeIsClampedAfterAStall(t *testing.T) {
+	if got := clampFrameTime(2); got != maxFrameTime {
+		t.Errorf("a 2s stall passes %v to update, want the cap of %v", got, maxFrameTime)
+	}
+	for _, deltaTime := range []float32{0, 1.0 / 144, 1.0 / 60, maxFrameTime} {
+		if got := clampFrameTime(deltaTime); got != deltaTime {
+			t.Errorf("clampFrameTime(%v) = %v, want it unchanged", deltaTime, got)
+		}
+	}
+}
+
+func TestEveryTierHasAConsumeSound(t *testing.T) {
+	want := map[string]string{
+		"tiny":         "tink",
+		"small":        "pop",
+		"medium-small": "pop",
+		"medium":       "thud",
+		"medium-large": "thud",
+		"large":        "crunch",
+		"extra-large":  "crunch",
+		"huge":         "crunch",
+		"massive":      "crunch",
+	}
+	for _, tier := range objectTiers {
+		if got := consumeSoundCategory(tier.Type); got != want[tier.Type] {
+			t.Errorf("consumeSoundCategory(%q) = %q, want %q", tier.Type, got, want[tier.Type])
+		}
+	}
+	if got := consumeSoundCategory("unknown"); got != defaultConsumeSound {
+		t.Errorf("unknown object type plays %q, want the default %q", got, defaultConsumeSound)
+	}
+}
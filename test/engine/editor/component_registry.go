@@ -0,0 +1,78 @@
+// Component type registry for the game engine editor
+package editor
+
+import (
+	"fmt"
+
+	"gameengine/components"
+	"gameengine/core"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ComponentTypeInfo describes a component type the editor can attach to entities
+type ComponentTypeInfo struct {
+	Type core.ComponentType
+	Name string
+	New  func() core.Component // Builds an instance with default settings
+}
+
+// Registered component types, in registration order
+var componentTypes []ComponentTypeInfo
+
+// RegisterComponentType makes a component type available to the editor, replacing any
+// earlier registration of the same type
+func RegisterComponentType(info ComponentTypeInfo) {
+	for i := range componentTypes {
+		if componentTypes[i].Type == info.Type {
+			componentTypes[i] = info
+			return
+		}
+	}
+	componentTypes = append(componentTypes, info)
+}
+
+// RegisteredComponentTypes returns every registered component type in registration order
+func RegisteredComponentTypes() []ComponentTypeInfo {
+	return append([]ComponentTypeInfo(nil), componentTypes...)
+}
+
+// componentTypeName returns the registered name of a component type
+func componentTypeName(componentType core.ComponentType) string {
+	for _, info := range componentTypes {
+		if info.Type == componentType {
+			return info.Name
+		}
+	}
+	return fmt.Sprintf("Component %d", componentType)
+}
+
+func init() {
+	RegisterComponentType(ComponentTypeInfo{
+		Type: components.TransformComponentType,
+		Name: "Transform",
+		New:  func() core.Component { return components.NewTransformComponentAt(rl.Vector3{}) },
+	})
+	RegisterComponentType(ComponentTypeInfo{
+		Type: components.MeshRendererComponentType,
+		Name: "MeshRenderer",
+		New:  func() core.Component { return &components.MeshRendererComponent{} },
+	})
+	RegisterComponentType(ComponentTypeInfo{
+		Type: components.AudioSourceComponentType,
+		Name: "AudioSource",
+		New:  func() core.Component { return components.NewAudioSourceComponent(rl.Sound{}) },
+	})
+	RegisterComponentType(ComponentTypeInfo{
+		Type: components.AudioListenerComponentType,
+		Name: "AudioListener",
+		New:  func() core.Component { return &components.AudioListenerComponent{Enabled: true} },
+	})
+	RegisterComponentType(ComponentTypeInfo{
+		Type: components.AudioReverbZoneComponentType,
+		Name: "AudioReverbZone",
+		New: func() core.Component {
+			return &components.AudioReverbZoneComponent{Enabled: true, MinDistance: 10, MaxDistance: 20}
+		},
+	})
+}
@@ -0,0 +1,126 @@
+// Log sink shown by the editor console
+package editor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// LogLevel is the severity of a console log entry
+type LogLevel int
+
+const (
+	LogLevelInfo LogLevel = iota
+	LogLevelWarn
+	LogLevelError
+)
+
+// logLevelCount is the number of log levels, for per-level tables
+const logLevelCount = 3
+
+var logLevelNames = [logLevelCount]string{"Info", "Warn", "Error"}
+
+// String returns the level's display name
+func (l LogLevel) String() string {
+	if l < 0 || int(l) >= logLevelCount {
+		return fmt.Sprintf("Level %d", int(l))
+	}
+	return logLevelNames[l]
+}
+
+// LogEntry is one message in the log
+type LogEntry struct {
+	Level   LogLevel
+	Message string
+	Time    time.Time
+}
+
+// Maximum number of entries kept; older ones are overwritten
+const logCapacity = 1000
+
+// logSink is a fixed-size ring buffer of log entries. It's locked because raylib
+// and systems may log from outside the editor's update.
+type logSink struct {
+	mu      sync.Mutex
+	entries [logCapacity]LogEntry
+	start   int    // Index of the oldest entry
+	count   int    // Number of entries held
+	version uint64 // Bumped on every change so readers can skip unchanged frames
+}
+
+var editorLog logSink
+
+// Log records a message in the editor console
+func Log(level LogLevel, msg string) {
+	editorLog.mu.Lock()
+	defer editorLog.mu.Unlock()
+
+	entry := LogEntry{Level: level, Message: msg, Time: time.Now()}
+	if editorLog.count < logCapacity {
+		editorLog.entries[(editorLog.start+editorLog.count)%logCapacity] = entry
+		editorLog.count++
+	} else {
+		editorLog.entries[editorLog.start] = entry
+		editorLog.start = (editorLog.start + 1) % logCapacity
+	}
+	editorLog.version++
+}
+
+// Logf records a formatted message in the editor console
+func Logf(level LogLevel, format string, args ...interface{}) {
+	Log(level, fmt.Sprintf(format, args...))
+}
+
+// ClearLog drops every entry from the editor console
+func ClearLog() {
+	editorLog.mu.Lock()
+	defer editorLog.mu.Unlock()
+
+	editorLog.start = 0
+	editorLog.count = 0
+	editorLog.version++
+}
+
+// LogEntries returns a copy of the held entries, oldest first
+func LogEntries() []LogEntry {
+	editorLog.mu.Lock()
+	defer editorLog.mu.Unlock()
+
+	entries := make([]LogEntry, editorLog.count)
+	for i := range entries {
+		entries[i] = editorLog.entries[(editorLog.start+i)%logCapacity]
+	}
+	return entries
+}
+
+// logVersion returns a counter that changes whenever the log does
+func logVersion() uint64 {
+	editorLog.mu.Lock()
+	defer editorLog.mu.Unlock()
+	return editorLog.version
+}
+
+// CaptureRaylibLog routes raylib's trace log into the editor console. Messages are
+// still printed to stdout, since the callback replaces raylib's own output.
+func CaptureRaylibLog() {
+	rl.SetTraceLogCallback(func(logType int, text string) {
+		level := raylibLogLevel(rl.TraceLogLevel(logType))
+		fmt.Printf("%s: %s\n", level, text)
+		Log(level, text)
+	})
+}
+
+// raylibLogLevel maps a raylib trace log level onto the console's levels
+func raylibLogLevel(level rl.TraceLogLevel) LogLevel {
+	switch {
+	case level >= rl.LogError:
+		return LogLevelError
+	case level == rl.LogWarning:
+		return LogLevelWarn
+	default:
+		return LogLevelInfo
+	}
+}
@@ -3,9 +3,17 @@ package editor
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"gameengine/components"
 	"gameengine/core"
+	"gameengine/ecs"
+	"gameengine/systems"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -23,20 +31,47 @@ type SceneHierarchyPanel struct {
 	editor          *Editor
 	scrollOffset    rl.Vector2
 	expandedNodes   map[string]bool
-	searchText      string
-	searchTextBuf   []byte
+	searchText      string                     // Filter for the entity list, matched case-insensitively
+	searchFocused   bool                       // The search field is taking typed characters
 	entityNames     map[core.EntityID]string  // Cache entity names to prevent recalculation
+	searchNames     map[core.EntityID]string   // Lowercased entityNames, for matching the search
 	lastFrameCount  uint64                     // Track frame count to know when to update cache
+	frameCount      uint64                     // Frames seen by Update, only ever increases
+	cacheInterval   uint64                     // Rebuild the name cache at most every N frames
+	cachedEntities  []core.EntityID            // Entity set the name cache was built from
+	names           map[core.EntityID]string   // Names given with SetEntityName
+	namesChanged    bool                       // A name was set since the cache was built
+	rows            []hierarchyRow             // Entities in tree order, rebuilt each frame
+	inTree          map[core.EntityID]bool     // Entities listed this frame, for finding roots
+	dragEntity      core.EntityID              // Entity being dragged onto a new parent, 0 when none
 }
 
+// hierarchyRow is one line of the entity tree
+type hierarchyRow struct {
+	entityID core.EntityID
+	depth    int
+}
+
+// Indentation per tree level in the entity list
+const hierarchyIndent = float32(12)
+
+// Default number of frames between entity name cache rebuilds
+const defaultNameCacheInterval = 30
+
+// Longest query the hierarchy search field accepts
+const maxSearchLength = 64
+
 // NewSceneHierarchyPanel creates a new scene hierarchy panel
 func NewSceneHierarchyPanel(editor *Editor) *SceneHierarchyPanel {
 	return &SceneHierarchyPanel{
 		editor:        editor,
 		expandedNodes: make(map[string]bool),
-		searchTextBuf: make([]byte, 256),
 		entityNames:   make(map[core.EntityID]string),
+		searchNames:   make(map[core.EntityID]string),
+		names:         make(map[core.EntityID]string),
+		inTree:        make(map[core.EntityID]bool),
 		lastFrameCount: 0,
+		cacheInterval: defaultNameCacheInterval,
 	}
 }
 
@@ -45,7 +80,70 @@ func (p *SceneHierarchyPanel) Initialize() error {
 }
 
 func (p *SceneHierarchyPanel) Update(deltaTime float32) {
-	// Update logic for hierarchy panel
+	p.frameCount++
+
+	// Scene file shortcuts live with the panel that owns the scene's contents
+	p.editor.handleSceneShortcuts()
+}
+
+// SetCacheInterval sets how many frames pass between entity name cache rebuilds
+func (p *SceneHierarchyPanel) SetCacheInterval(frames uint64) {
+	if frames < 1 {
+		frames = 1
+	}
+	p.cacheInterval = frames
+}
+
+// SetEntityName names an entity in the hierarchy; an empty name goes back to "Entity <id>"
+func (p *SceneHierarchyPanel) SetEntityName(entityID core.EntityID, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		delete(p.names, entityID)
+	} else {
+		p.names[entityID] = name
+	}
+	p.namesChanged = true
+}
+
+// EntityName returns the name shown for an entity
+func (p *SceneHierarchyPanel) EntityName(entityID core.EntityID) string {
+	if name, ok := p.names[entityID]; ok {
+		return name
+	}
+	return fmt.Sprintf("Entity %d", entityID)
+}
+
+// needsNameCacheRebuild reports whether the entity set or a name changed, or the cache is stale
+func (p *SceneHierarchyPanel) needsNameCacheRebuild(entities []core.EntityID) bool {
+	if p.namesChanged {
+		return true
+	}
+	if len(entities) != len(p.cachedEntities) {
+		return true
+	}
+	for i, entityID := range entities {
+		if p.cachedEntities[i] != entityID {
+			return true
+		}
+	}
+	return p.frameCount-p.lastFrameCount >= p.cacheInterval
+}
+
+// rebuildNameCache refreshes entityNames for the given entities
+func (p *SceneHierarchyPanel) rebuildNameCache(entities []core.EntityID) {
+	for entityID := range p.entityNames {
+		delete(p.entityNames, entityID)
+		delete(p.searchNames, entityID)
+	}
+	for _, entityID := range entities {
+		name := p.EntityName(entityID)
+		p.entityNames[entityID] = name
+		p.searchNames[entityID] = strings.ToLower(name)
+	}
+
+	p.cachedEntities = append(p.cachedEntities[:0], entities...)
+	p.lastFrameCount = p.frameCount
+	p.namesChanged = false
 }
 
 func (p *SceneHierarchyPanel) Render(rect rl.Rectangle) {
@@ -61,11 +159,8 @@ func (p *SceneHierarchyPanel) Render(rect rl.Rectangle) {
 
 	// Search bar
 	searchHeight := float32(25)
-	// searchRect := rl.Rectangle{X: rect.X + 5, Y: rect.Y + titleHeight + 5, Width: rect.Width - 10, Height: searchHeight}
-	// Search text box (commented out - raygui disabled)
-	// if rg.GuiTextBox(searchRect, p.searchTextBuf, 256, true) {
-	//	p.searchText = string(p.searchTextBuf[:p.findNullTerminator(p.searchTextBuf)])
-	// }
+	searchRect := rl.Rectangle{X: rect.X + 5, Y: rect.Y + titleHeight + 5, Width: rect.Width - 10, Height: searchHeight}
+	p.renderSearchField(searchRect)
 
 	// Entity list area
 	listRect := rl.Rectangle{
@@ -78,6 +173,98 @@ func (p *SceneHierarchyPanel) Render(rect rl.Rectangle) {
 	p.renderEntityList(listRect)
 }
 
+// renderSearchField draws the search box; clicking it takes focus and clicking elsewhere drops it
+func (p *SceneHierarchyPanel) renderSearchField(rect rl.Rectangle) {
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+		p.searchFocused = rl.CheckCollisionPointRec(rl.GetMousePosition(), rect)
+	}
+	if p.searchFocused {
+		p.handleSearchInput()
+	}
+
+	backgroundColor := rl.Color{R: 40, G: 40, B: 40, A: 255}
+	borderColor := rl.Color{R: 70, G: 70, B: 70, A: 255}
+	if p.searchFocused {
+		backgroundColor = rl.Color{R: 30, G: 30, B: 30, A: 255}
+		borderColor = rl.Color{R: 0, G: 120, B: 215, A: 255}
+	}
+	rl.DrawRectangleRec(rect, backgroundColor)
+	rl.DrawRectangleLinesEx(rect, 1, borderColor)
+
+	switch {
+	case p.searchFocused:
+		rl.DrawText(p.searchText + "_", int32(rect.X + 6), int32(rect.Y + 7), 10, rl.White)
+	case p.searchText != "":
+		rl.DrawText(p.searchText, int32(rect.X + 6), int32(rect.Y + 7), 10, rl.White)
+	default:
+		rl.DrawText("Search...", int32(rect.X + 6), int32(rect.Y + 7), 10, rl.Gray)
+	}
+}
+
+// handleSearchInput types into the focused search field; ENTER or ESC leave it
+func (p *SceneHierarchyPanel) handleSearchInput() {
+	key := rl.GetCharPressed()
+	for key > 0 {
+		if key >= 32 && len(p.searchText) < maxSearchLength {
+			p.searchText += string(key)
+		}
+		key = rl.GetCharPressed()
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(p.searchText) > 0 {
+		_, size := utf8.DecodeLastRuneInString(p.searchText)
+		p.searchText = p.searchText[:len(p.searchText)-size]
+	}
+
+	if rl.IsKeyPressed(rl.KeyEnter) || rl.IsKeyPressed(rl.KeyKpEnter) || rl.IsKeyPressed(rl.KeyEscape) {
+		p.searchFocused = false
+	}
+}
+
+// matchesSearch reports whether an entity's name contains the search text, ignoring case
+func (p *SceneHierarchyPanel) matchesSearch(entityID core.EntityID, query string) bool {
+	return query == "" || strings.Contains(p.searchNames[entityID], query)
+}
+
+// buildRows orders entities as a tree, each parent followed by its children. Entities whose
+// parent isn't listed are shown as roots.
+func (p *SceneHierarchyPanel) buildRows(entities []core.EntityID, hierarchy *systems.TransformHierarchy) {
+	for entityID := range p.inTree {
+		delete(p.inTree, entityID)
+	}
+	for _, entityID := range entities {
+		p.inTree[entityID] = true
+	}
+
+	p.rows = p.rows[:0]
+	for _, entityID := range entities {
+		if !p.inTree[hierarchy.GetParent(entityID)] {
+			p.appendRows(entityID, 0, hierarchy)
+		}
+	}
+}
+
+// appendRows adds entityID and its listed descendants to rows
+func (p *SceneHierarchyPanel) appendRows(entityID core.EntityID, depth int, hierarchy *systems.TransformHierarchy) {
+	p.rows = append(p.rows, hierarchyRow{entityID: entityID, depth: depth})
+	for _, child := range hierarchy.GetChildren(entityID) {
+		if p.inTree[child] {
+			p.appendRows(child, depth+1, hierarchy)
+		}
+	}
+}
+
+// dropEntity parents the dragged entity to target, or makes it a root when target is 0
+func (p *SceneHierarchyPanel) dropEntity(target core.EntityID, hierarchy *systems.TransformHierarchy) {
+	dragged := p.dragEntity
+	p.dragEntity = 0
+	if dragged == target || hierarchy.GetParent(dragged) == target {
+		return
+	}
+	if err := hierarchy.SetParent(dragged, target); err != nil {
+		Logf(LogLevelWarn, "Can't reparent: %v", err)
+	}
+}
+
 func (p *SceneHierarchyPanel) renderEntityList(rect rl.Rectangle) {
 	// Clear the list area background first to prevent flickering
 	rl.DrawRectangleRec(rect, rl.Color{R: 50, G: 50, B: 50, A: 255})
@@ -92,13 +279,28 @@ func (p *SceneHierarchyPanel) renderEntityList(rect rl.Rectangle) {
 	world := activeScene.GetWorld()
 	entities := world.GetEntitiesWithComponent(components.TransformComponentType)
 
+	// Only rebuild names on the cache cadence or when entities come and go
+	if p.needsNameCacheRebuild(entities) {
+		p.rebuildNameCache(entities)
+	}
+
+	hierarchy := systems.TransformHierarchyOf(world)
+	p.buildRows(entities, hierarchy)
+
 	itemHeight := float32(20)
 	y := rect.Y
+	query := strings.ToLower(strings.TrimSpace(p.searchText))
+	matched := 0
+	mousePos := rl.GetMousePosition()
+	released := p.dragEntity != 0 && rl.IsMouseButtonReleased(rl.MouseButtonLeft)
+
+	for _, row := range p.rows {
+		entityID := row.entityID
+		if !p.matchesSearch(entityID, query) {
+			continue
+		}
+		matched++
 
-	// Static entity names to completely eliminate dynamic string operations
-	staticNames := []string{"Entity 1", "Entity 2", "Entity 3"}
-
-	for i, entityID := range entities {
 		if y + itemHeight > rect.Y + rect.Height {
 			break // Don't render beyond panel bounds
 		}
@@ -117,36 +319,51 @@ func (p *SceneHierarchyPanel) renderEntityList(rect rl.Rectangle) {
 		// Draw item background
 		rl.DrawRectangleRec(itemRect, backgroundColor)
 
-		// Handle click (check mouse position only when clicking)
-		if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
-			mousePos := rl.GetMousePosition()
-			if rl.CheckCollisionPointRec(mousePos, itemRect) {
-				p.editor.SetSelectedEntity(entityID)
-			}
+		// Handle click (check mouse position only when clicking); a press also starts a drag
+		hovered := rl.CheckCollisionPointRec(mousePos, itemRect)
+		if rl.IsMouseButtonPressed(rl.MouseButtonLeft) && hovered {
+			p.editor.SetSelectedEntity(entityID)
+			p.dragEntity = entityID
 		}
 
-		// Use static names to eliminate ALL dynamic text operations
-		var entityName string
-		if i < len(staticNames) {
-			entityName = staticNames[i]
-		} else {
-			entityName = "Entity N"
+		// Outline the entity a drag would drop onto; releasing over the dragged entity itself
+		// is just a click
+		if p.dragEntity != 0 && hovered {
+			if p.dragEntity != entityID {
+				rl.DrawRectangleLinesEx(itemRect, 1, rl.Color{R: 230, G: 200, B: 110, A: 255})
+			}
+			if released {
+				if p.dragEntity != entityID {
+					p.dropEntity(entityID, hierarchy)
+				}
+				released = false
+			}
 		}
 
+		// Use cached names so no strings are built per frame
+		entityName := p.entityNames[entityID]
+
 		// Draw text with fixed positioning
-		rl.DrawText(entityName, int32(rect.X + 10), int32(y + 2), 10, rl.White)
+		indent := float32(row.depth) * hierarchyIndent
+		rl.DrawText(entityName, int32(rect.X + 10 + indent), int32(y + 2), 10, rl.White)
 
 		y += itemHeight
 	}
-}
 
-func (p *SceneHierarchyPanel) findNullTerminator(buf []byte) int {
-	for i, b := range buf {
-		if b == 0 {
-			return i
+	if matched == 0 && query != "" {
+		rl.DrawText("No matches", int32(rect.X + 10), int32(rect.Y + 2), 10, rl.Gray)
+	}
+
+	// Dropping on empty space in the list detaches the entity; anywhere else cancels the drag
+	if released {
+		if rl.CheckCollisionPointRec(mousePos, rect) {
+			p.dropEntity(0, hierarchy)
 		}
+		p.dragEntity = 0
+	}
+	if !rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+		p.dragEntity = 0
 	}
-	return len(buf)
 }
 
 func (p *SceneHierarchyPanel) Shutdown() {
@@ -158,8 +375,15 @@ type InspectorPanel struct {
 	editor        *Editor
 	scrollOffset  rl.Vector2
 	textBuffers   map[string][]byte
+	editField     string // Key of the number field being typed into, "" when none
+	editText      string
+	addMenuOpen   bool   // The Add Component dropdown is showing
+	addMenuSearch string // Filter typed into the Add Component dropdown
 }
 
+// Longest text a number field accepts
+const maxNumberFieldLength = 24
+
 // NewInspectorPanel creates a new inspector panel
 func NewInspectorPanel(editor *Editor) *InspectorPanel {
 	return &InspectorPanel{
@@ -195,6 +419,11 @@ func (p *InspectorPanel) Render(rect rl.Rectangle) {
 		Height: rect.Height - titleHeight - 10,
 	}
 
+	// A click anywhere drops an unfinished edit; the field clicked, if any, takes focus below
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+		p.editField = ""
+	}
+
 	if p.editor.selectedEntity == 0 {
 		rl.DrawText("No entity selected", int32(contentRect.X + 10), int32(contentRect.Y + 10), 10, rl.Gray)
 		return
@@ -220,7 +449,7 @@ func (p *InspectorPanel) renderEntityInspector(rect rl.Rectangle) {
 	entityName := fmt.Sprintf("Entity %d", entityID)
 	rl.DrawText(entityName, int32(rect.X + 10), int32(y + 5), 14, rl.White)
 
-	// Active checkbox (commented out - raygui disabled)
+	// Active checkbox (commented out - the World has no enabled state to toggle yet)
 	// activeRect := rl.Rectangle{X: rect.X + rect.Width - 60, Y: y + 5, Width: 50, Height: 20}
 	// entityActive := true // Would get from entity state
 	// rg.GuiCheckBox(activeRect, "Active", &entityActive)
@@ -232,23 +461,146 @@ func (p *InspectorPanel) renderEntityInspector(rect rl.Rectangle) {
 		y = p.renderTransformComponent(rect, y, transform.(*components.TransformComponent))
 	}
 
-	// Render other components (simplified - just show that there are components)
-	// componentTypes := world.GetEntityComponentTypes(entityID)  // Method doesn't exist
-	// for _, componentType := range componentTypes {
-	if false { // Disabled for now
-		// if componentType == components.TransformComponentType {
-		//	continue // Already rendered
-		// }
+	// Render the other components the inspector knows about. They can't be removed: the
+	// World has no RemoveComponent.
+	for _, componentType := range inspectorComponentTypes {
+		component, ok := world.GetComponent(entityID, componentType)
+		if !ok {
+			continue
+		}
+
+		y = p.renderGenericComponent(rect, y, componentType, component)
+
+		if y > rect.Y + rect.Height {
+			break // Don't render beyond panel bounds
+		}
+	}
+
+	p.renderAddComponentMenu(rect, world, entityID)
+}
+
+// renderAddComponentMenu draws the Add Component button pinned to the bottom of the inspector
+// and, while open, a searchable list of registered types above it. Types the entity already
+// has are greyed out.
+func (p *InspectorPanel) renderAddComponentMenu(rect rl.Rectangle, world *ecs.World, entityID core.EntityID) {
+	buttonRect := rl.Rectangle{X: rect.X + 10, Y: rect.Y + rect.Height - 25, Width: rect.Width - 20, Height: 22}
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseButtonLeft)
+
+	buttonColor := rl.Color{R: 65, G: 65, B: 65, A: 255}
+	if p.addMenuOpen || rl.CheckCollisionPointRec(mousePos, buttonRect) {
+		buttonColor = rl.Color{R: 85, G: 85, B: 85, A: 255}
+	}
+	rl.DrawRectangleRec(buttonRect, buttonColor)
+	rl.DrawText("Add Component", int32(buttonRect.X + buttonRect.Width / 2 - 35), int32(buttonRect.Y + 6), 10, rl.White)
 
-		// component, _ := world.GetComponent(entityID, componentType)
-		// y = p.renderGenericComponent(rect, y, componentType, component)
+	if clicked && rl.CheckCollisionPointRec(mousePos, buttonRect) {
+		p.addMenuOpen = !p.addMenuOpen
+		p.addMenuSearch = ""
+		return
+	}
+	if !p.addMenuOpen {
+		return
+	}
+
+	// Collect the types matching the search
+	query := strings.ToLower(strings.TrimSpace(p.addMenuSearch))
+	var matches []ComponentTypeInfo
+	for _, info := range RegisteredComponentTypes() {
+		if query == "" || strings.Contains(strings.ToLower(info.Name), query) {
+			matches = append(matches, info)
+		}
+	}
+
+	itemHeight := float32(20)
+	listHeight := float32(len(matches)) * itemHeight
+	if len(matches) == 0 {
+		listHeight = itemHeight
+	}
+	menuRect := rl.Rectangle{
+		X: buttonRect.X,
+		Y: buttonRect.Y - listHeight - itemHeight - 4,
+		Width: buttonRect.Width,
+		Height: listHeight + itemHeight + 4,
+	}
+
+	if clicked && !rl.CheckCollisionPointRec(mousePos, menuRect) {
+		p.addMenuOpen = false
+		return
+	}
+	p.handleAddMenuInput()
+	if !p.addMenuOpen {
+		return
+	}
+
+	rl.DrawRectangleRec(menuRect, rl.Color{R: 40, G: 40, B: 40, A: 255})
+	rl.DrawRectangleLinesEx(menuRect, 1, rl.Color{R: 0, G: 120, B: 215, A: 255})
+
+	// Search line at the top of the menu
+	searchText := p.addMenuSearch + "_"
+	searchColor := rl.White
+	if p.addMenuSearch == "" {
+		searchText = "Search..."
+		searchColor = rl.Gray
+	}
+	rl.DrawText(searchText, int32(menuRect.X + 6), int32(menuRect.Y + 6), 10, searchColor)
+
+	y := menuRect.Y + itemHeight + 4
+	if len(matches) == 0 {
+		rl.DrawText("No matches", int32(menuRect.X + 6), int32(y + 5), 10, rl.Gray)
+		return
+	}
+
+	for _, info := range matches {
+		itemRect := rl.Rectangle{X: menuRect.X, Y: y, Width: menuRect.Width, Height: itemHeight}
+		_, hasComponent := world.GetComponent(entityID, info.Type)
+		hovered := !hasComponent && rl.CheckCollisionPointRec(mousePos, itemRect)
+
+		if hovered {
+			rl.DrawRectangleRec(itemRect, rl.Color{R: 0, G: 120, B: 215, A: 255})
+		}
+		textColor := rl.White
+		if hasComponent {
+			textColor = rl.Gray
+		}
+		rl.DrawText(info.Name, int32(itemRect.X + 10), int32(y + 5), 10, textColor)
+
+		if hovered && clicked {
+			world.AddComponent(entityID, info.New())
+			p.addMenuOpen = false
+			return
+		}
+
+		y += itemHeight
+	}
+}
 
-		// if y > rect.Y + rect.Height {
-		//	break // Don't render beyond panel bounds
-		// }
+// handleAddMenuInput types into the Add Component search; ESC closes the menu
+func (p *InspectorPanel) handleAddMenuInput() {
+	key := rl.GetCharPressed()
+	for key > 0 {
+		if key >= 32 && len(p.addMenuSearch) < maxSearchLength {
+			p.addMenuSearch += string(key)
+		}
+		key = rl.GetCharPressed()
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(p.addMenuSearch) > 0 {
+		_, size := utf8.DecodeLastRuneInString(p.addMenuSearch)
+		p.addMenuSearch = p.addMenuSearch[:len(p.addMenuSearch)-size]
+	}
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		p.addMenuOpen = false
 	}
 }
 
+// inspectorComponentTypes are the components the inspector lists under Transform
+var inspectorComponentTypes = []core.ComponentType{
+	components.MeshRendererComponentType,
+	components.AudioSourceComponentType,
+	components.AudioListenerComponentType,
+	components.AudioReverbZoneComponentType,
+}
+
 func (p *InspectorPanel) renderTransformComponent(rect rl.Rectangle, y float32, transform *components.TransformComponent) float32 {
 	// sectionHeight := float32(120)  // Unused variable
 
@@ -263,9 +615,11 @@ func (p *InspectorPanel) renderTransformComponent(rect rl.Rectangle, y float32,
 	rl.DrawText("Position", int32(rect.X + 10), int32(y), 10, rl.LightGray)
 	y += 15
 
+	// Edits move by whole grid cells while snapping is on
 	pos := transform.Position
 	p.renderVector3Input(rect, y, "position", &pos)
-	transform.SetPosition(pos)
+	moved := p.editor.SnapTranslation(rl.Vector3Subtract(pos, transform.Position))
+	transform.SetPosition(rl.Vector3Add(transform.Position, moved))
 	y += 25
 
 	// Rotation
@@ -274,7 +628,8 @@ func (p *InspectorPanel) renderTransformComponent(rect rl.Rectangle, y float32,
 
 	rot := transform.Rotation
 	p.renderVector3Input(rect, y, "rotation", &rot)
-	transform.SetRotation(rot)
+	turned := p.editor.SnapRotation(rl.Vector3Subtract(rot, transform.Rotation))
+	transform.SetRotation(rl.Vector3Add(transform.Rotation, turned))
 	y += 25
 
 	// Scale
@@ -293,44 +648,66 @@ func (p *InspectorPanel) renderVector3Input(rect rl.Rectangle, y float32, name s
 	fieldWidth := (rect.Width - 30) / 3
 	spacing := float32(5)
 
-	// X (text input disabled - raygui not available)
-	// xBuf := p.getOrCreateTextBuffer(name+"_x", fmt.Sprintf("%.2f", vec.X))
-	// xRect := rl.Rectangle{X: rect.X + 10, Y: y, Width: fieldWidth, Height: 20}
-
-	// Draw current values as text instead
-	rl.DrawText(fmt.Sprintf("X: %.2f", vec.X), int32(rect.X + 10), int32(y), 10, rl.White)
-	// X input field (commented out - raygui disabled)
-	// if rg.GuiTextBox(xRect, xBuf, 32, true) {
-	//	if val, err := strconv.ParseFloat(strings.TrimSpace(string(xBuf[:p.findNullTerminator(xBuf)])), 32); err == nil {
-	//		vec.X = float32(val)
-	//	}
-	// }
-
-	// Y (text input disabled - raygui not available)
-	// yBuf := p.getOrCreateTextBuffer(name+"_y", fmt.Sprintf("%.2f", vec.Y))
-	// yRect := rl.Rectangle{X: rect.X + 10 + fieldWidth + spacing, Y: y, Width: fieldWidth, Height: 20}
-
-	// Draw current values as text instead
-	rl.DrawText(fmt.Sprintf("Y: %.2f", vec.Y), int32(rect.X + 10 + fieldWidth + spacing), int32(y), 10, rl.White)
-	// Y input field (commented out - raygui disabled)
-	// if rg.GuiTextBox(yRect, yBuf, 32, true) {
-	//	if val, err := strconv.ParseFloat(strings.TrimSpace(string(yBuf[:p.findNullTerminator(yBuf)])), 32); err == nil {
-	//		vec.Y = float32(val)
-	//	}
-	// }
-
-	// Z (text input disabled - raygui not available)
-	// zBuf := p.getOrCreateTextBuffer(name+"_z", fmt.Sprintf("%.2f", vec.Z))
-	// zRect := rl.Rectangle{X: rect.X + 10 + 2*(fieldWidth + spacing), Y: y, Width: fieldWidth, Height: 20}
-
-	// Draw current values as text instead
-	rl.DrawText(fmt.Sprintf("Z: %.2f", vec.Z), int32(rect.X + 10 + 2*(fieldWidth + spacing)), int32(y), 10, rl.White)
-	// Z input field (commented out - raygui disabled)
-	// if rg.GuiTextBox(zRect, zBuf, 32, true) {
-	//	if val, err := strconv.ParseFloat(strings.TrimSpace(string(zBuf[:p.findNullTerminator(zBuf)])), 32); err == nil {
-	//		vec.Z = float32(val)
-	//	}
-	// }
+	xRect := rl.Rectangle{X: rect.X + 10, Y: y, Width: fieldWidth, Height: 16}
+	yRect := rl.Rectangle{X: rect.X + 10 + fieldWidth + spacing, Y: y, Width: fieldWidth, Height: 16}
+	zRect := rl.Rectangle{X: rect.X + 10 + 2*(fieldWidth + spacing), Y: y, Width: fieldWidth, Height: 16}
+
+	p.renderNumberField(xRect, name+"_x", "X", &vec.X)
+	p.renderNumberField(yRect, name+"_y", "Y", &vec.Y)
+	p.renderNumberField(zRect, name+"_z", "Z", &vec.Z)
+}
+
+// renderNumberField draws an editable number. Clicking it starts an edit, ENTER stores the
+// typed number in value, and ESC, a click elsewhere or text that isn't a number leaves
+// value as it was.
+func (p *InspectorPanel) renderNumberField(fieldRect rl.Rectangle, key string, label string, value *float32) {
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) && rl.CheckCollisionPointRec(rl.GetMousePosition(), fieldRect) {
+		p.editField = key
+		p.editText = strconv.FormatFloat(float64(*value), 'f', 2, 32)
+	}
+
+	focused := p.editField == key
+	if focused {
+		p.handleNumberFieldInput(value)
+		focused = p.editField == key
+	}
+
+	backgroundColor := rl.Color{R: 40, G: 40, B: 40, A: 255}
+	borderColor := rl.Color{R: 70, G: 70, B: 70, A: 255}
+	text := fmt.Sprintf("%s: %.2f", label, *value)
+	if focused {
+		backgroundColor = rl.Color{R: 30, G: 30, B: 30, A: 255}
+		borderColor = rl.Color{R: 0, G: 120, B: 215, A: 255}
+		text = fmt.Sprintf("%s: %s_", label, p.editText)
+	}
+	rl.DrawRectangleRec(fieldRect, backgroundColor)
+	rl.DrawRectangleLinesEx(fieldRect, 1, borderColor)
+	rl.DrawText(text, int32(fieldRect.X + 4), int32(fieldRect.Y + 3), 10, rl.White)
+}
+
+// handleNumberFieldInput types into the focused number field and applies it on ENTER
+func (p *InspectorPanel) handleNumberFieldInput(value *float32) {
+	key := rl.GetCharPressed()
+	for key > 0 {
+		if strings.ContainsRune("0123456789.-+eE", key) && len(p.editText) < maxNumberFieldLength {
+			p.editText += string(key)
+		}
+		key = rl.GetCharPressed()
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(p.editText) > 0 {
+		p.editText = p.editText[:len(p.editText)-1]
+	}
+
+	if rl.IsKeyPressed(rl.KeyEnter) || rl.IsKeyPressed(rl.KeyKpEnter) {
+		// Anything that doesn't parse is dropped and the old value stays
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(p.editText), 32); err == nil {
+			*value = float32(parsed)
+		}
+		p.editField = ""
+	}
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		p.editField = ""
+	}
 }
 
 func (p *InspectorPanel) renderGenericComponent(rect rl.Rectangle, y float32, componentType core.ComponentType, component interface{}) float32 {
@@ -338,7 +715,7 @@ func (p *InspectorPanel) renderGenericComponent(rect rl.Rectangle, y float32, co
 	headerRect := rl.Rectangle{X: rect.X, Y: y, Width: rect.Width, Height: 25}
 	rl.DrawRectangleRec(headerRect, rl.Color{R: 65, G: 65, B: 65, A: 255})
 
-	componentName := fmt.Sprintf("Component %d", componentType)
+	componentName := componentTypeName(componentType)
 	rl.DrawText(componentName, int32(rect.X + 10), int32(y + 5), 12, rl.White)
 
 	y += 30
@@ -379,13 +756,93 @@ type ViewportPanel struct {
 	editor         *Editor
 	renderTexture  rl.RenderTexture2D
 	viewportSize   rl.Vector2
+	models         map[string]rl.Model        // Loaded models by asset path, shared between entities
+	failedModels   map[string]bool            // Paths that couldn't be loaded, so they aren't retried every frame
+	entityModels   map[core.EntityID]string   // Model asset assigned to each mesh renderer
+	entityColors   map[core.EntityID]rl.Color // Material tint per mesh renderer; white when unset
+	placeholder    rl.Model                   // Unit cube drawn for entities without a model
+	hasPlaceholder bool
 }
 
 // NewViewportPanel creates a new viewport panel
 func NewViewportPanel(editor *Editor) *ViewportPanel {
 	return &ViewportPanel{
-		editor: editor,
+		editor:       editor,
+		models:       make(map[string]rl.Model),
+		failedModels: make(map[string]bool),
+		entityModels: make(map[core.EntityID]string),
+		entityColors: make(map[core.EntityID]rl.Color),
+	}
+}
+
+// SetEntityModel assigns the model at path to an entity's mesh renderer; "" goes back to the
+// placeholder cube. Models are loaded the first time they're drawn.
+func (p *ViewportPanel) SetEntityModel(entityID core.EntityID, path string) {
+	if path == "" {
+		delete(p.entityModels, entityID)
+		return
+	}
+	p.entityModels[entityID] = path
+}
+
+// SetEntityColor sets the material tint an entity's mesh is drawn with
+func (p *ViewportPanel) SetEntityColor(entityID core.EntityID, color rl.Color) {
+	p.entityColors[entityID] = color
+}
+
+// LoadModel returns the model at path, loading it on first use. It reports false when the
+// file is missing or has no meshes, and doesn't try the same path again.
+func (p *ViewportPanel) LoadModel(path string) (rl.Model, bool) {
+	if model, ok := p.models[path]; ok {
+		return model, true
+	}
+	if p.failedModels[path] {
+		return rl.Model{}, false
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		Logf(LogLevelWarn, "Model %s not found", path)
+		p.failedModels[path] = true
+		return rl.Model{}, false
+	}
+	model := rl.LoadModel(path)
+	if model.MeshCount == 0 {
+		Logf(LogLevelError, "Failed to load model %s", path)
+		rl.UnloadModel(model)
+		p.failedModels[path] = true
+		return rl.Model{}, false
+	}
+
+	p.models[path] = model
+	return model, true
+}
+
+// placeholderModel returns the unit cube drawn in place of missing models, building it on first use
+func (p *ViewportPanel) placeholderModel() rl.Model {
+	if !p.hasPlaceholder {
+		p.placeholder = rl.LoadModelFromMesh(rl.GenMeshCube(1, 1, 1))
+		p.hasPlaceholder = true
 	}
+	return p.placeholder
+}
+
+// MeshAssignment returns the model path and tint an entity's mesh renderer is drawn with;
+// the path is "" for the placeholder cube
+func (p *ViewportPanel) MeshAssignment(entityID core.EntityID) (string, rl.Color) {
+	color := rl.White
+	if tint, ok := p.entityColors[entityID]; ok {
+		color = tint
+	}
+	return p.entityModels[entityID], color
+}
+
+// GetModel returns the model assigned to an entity, if it has one that loaded
+func (p *ViewportPanel) GetModel(entityID core.EntityID) (rl.Model, bool) {
+	path, ok := p.entityModels[entityID]
+	if !ok {
+		return rl.Model{}, false
+	}
+	return p.LoadModel(path)
 }
 
 func (p *ViewportPanel) Initialize() error {
@@ -396,7 +853,7 @@ func (p *ViewportPanel) Initialize() error {
 }
 
 func (p *ViewportPanel) Update(deltaTime float32) {
-	// Update viewport logic
+	p.editor.handleSnapShortcut()
 }
 
 func (p *ViewportPanel) Render(rect rl.Rectangle) {
@@ -410,6 +867,14 @@ func (p *ViewportPanel) Render(rect rl.Rectangle) {
 	rl.DrawRectangleRec(titleRect, rl.Color{R: 60, G: 60, B: 60, A: 255})
 	rl.DrawText("Scene View", int32(rect.X + 10), int32(rect.Y + 5), 12, rl.White)
 
+	// Current snap setting at the right of the title bar
+	snapLabel := p.editor.snapLabel()
+	snapColor := rl.Gray
+	if SnapEnabled() {
+		snapColor = rl.Color{R: 230, G: 200, B: 110, A: 255}
+	}
+	rl.DrawText(snapLabel, int32(rect.X + rect.Width) - rl.MeasureText(snapLabel, 10) - 10, int32(rect.Y + 7), 10, snapColor)
+
 	// Define viewport area
 	viewportRect := rl.Rectangle{
 		X: rect.X + 5,
@@ -418,6 +883,11 @@ func (p *ViewportPanel) Render(rect rl.Rectangle) {
 		Height: rect.Height - titleHeight - 10,
 	}
 
+	// Select whatever is clicked in the viewport
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) && rl.CheckCollisionPointRec(rl.GetMousePosition(), viewportRect) {
+		p.pickEntity(rl.GetMousePosition())
+	}
+
 	// Draw viewport background
 	rl.DrawRectangleRec(viewportRect, rl.Color{R: 100, G: 149, B: 237, A: 255}) // Sky blue
 
@@ -459,6 +929,8 @@ func (p *ViewportPanel) renderSceneEntities() {
 
 	world := activeScene.GetWorld()
 
+	hierarchy := systems.TransformHierarchyOf(world)
+
 	// Get entities with mesh renderer components
 	entities := world.GetEntitiesWithComponents(components.TransformComponentType, components.MeshRendererComponentType)
 
@@ -467,21 +939,73 @@ func (p *ViewportPanel) renderSceneEntities() {
 		meshRenderer, _ := world.GetComponent(entityID, components.MeshRendererComponentType)
 
 		if transform != nil && meshRenderer != nil {
-			transformComp := transform.(*components.TransformComponent)
-			// meshComp := meshRenderer.(*components.MeshRendererComponent)  // Unused
+			_, color := p.MeshAssignment(entityID)
 
-			// Set up transform matrix
-			position := transformComp.Position
-			// rotation := transformComp.Rotation  // Unused
-			scale := transformComp.Scale
+			// Draw the assigned model, or a unit cube when there's none, through the entity's
+			// full transform composed with its parents', so tilted entities render tilted
+			model, ok := p.GetModel(entityID)
+			if !ok {
+				model = p.placeholderModel()
+			}
+			model.Transform = rl.MatrixMultiply(model.Transform, hierarchy.WorldMatrix(entityID))
+			rl.DrawModel(model, rl.Vector3{}, 1, color)
+		}
+	}
+}
+
+// pickEntity selects the nearest rendered entity under mousePos, or clears the selection if
+// there is none. The scene is projected over the whole window and clipped to the viewport
+// by the scissor, so the window mouse position maps straight onto the camera without
+// subtracting the viewport's offset.
+func (p *ViewportPanel) pickEntity(mousePos rl.Vector2) {
+	activeScene := p.editor.gameEngine.GetSceneManager().GetActiveScene()
+	if activeScene == nil {
+		return
+	}
 
-			// Draw the model (simplified - would use proper rendering system)
-			// model := meshComp.GetModel()  // Method doesn't exist yet
-			// rl.DrawModelEx(model, position, rl.Vector3{X: 0, Y: 1, Z: 0}, rotation.Y, scale, rl.White)
-			// For now, just draw a cube placeholder
-			rl.DrawCube(position, scale.X, scale.Y, scale.Z, rl.White)
+	world := activeScene.GetWorld()
+	hierarchy := systems.TransformHierarchyOf(world)
+	ray := rl.GetMouseRay(mousePos, *p.editor.GetEditorCamera())
+
+	var picked core.EntityID
+	nearest := float32(-1)
+	entities := world.GetEntitiesWithComponents(components.TransformComponentType, components.MeshRendererComponentType)
+	for _, entityID := range entities {
+		hit := rl.GetRayCollisionBox(ray, entityBounds(hierarchy.WorldMatrix(entityID)))
+		if hit.Hit && (nearest < 0 || hit.Distance < nearest) {
+			picked = entityID
+			nearest = hit.Distance
+		}
+	}
+
+	p.editor.SetSelectedEntity(picked)
+}
+
+// entityBounds boxes the unit cube renderSceneEntities draws for an entity with the given
+// world matrix
+func entityBounds(matrix rl.Matrix) rl.BoundingBox {
+	var bounds rl.BoundingBox
+	for i := 0; i < 8; i++ {
+		corner := rl.Vector3{X: -0.5, Y: -0.5, Z: -0.5}
+		if i&1 != 0 {
+			corner.X = 0.5
+		}
+		if i&2 != 0 {
+			corner.Y = 0.5
+		}
+		if i&4 != 0 {
+			corner.Z = 0.5
 		}
+		corner = rl.Vector3Transform(corner, matrix)
+
+		if i == 0 {
+			bounds = rl.BoundingBox{Min: corner, Max: corner}
+			continue
+		}
+		bounds.Min = rl.Vector3Min(bounds.Min, corner)
+		bounds.Max = rl.Vector3Max(bounds.Max, corner)
 	}
+	return bounds
 }
 
 func (p *ViewportPanel) renderGizmos() {
@@ -492,9 +1016,8 @@ func (p *ViewportPanel) renderGizmos() {
 
 	world := activeScene.GetWorld()
 
-	if transform, ok := world.GetComponent(p.editor.selectedEntity, components.TransformComponentType); ok {
-		transformComp := transform.(*components.TransformComponent)
-		position := transformComp.Position
+	if _, ok := world.GetComponent(p.editor.selectedEntity, components.TransformComponentType); ok {
+		position := systems.TransformHierarchyOf(world).GetWorldPosition(p.editor.selectedEntity)
 
 		// Simple gizmo rendering (basic axes)
 		gizmoSize := float32(1.0)
@@ -513,17 +1036,72 @@ func (p *ViewportPanel) renderGizmos() {
 
 func (p *ViewportPanel) Shutdown() {
 	rl.UnloadRenderTexture(p.renderTexture)
+
+	for path, model := range p.models {
+		rl.UnloadModel(model)
+		delete(p.models, path)
+	}
+	if p.hasPlaceholder {
+		rl.UnloadModel(p.placeholder)
+		p.hasPlaceholder = false
+	}
 }
 
 // ProjectBrowserPanel shows project files and assets
 type ProjectBrowserPanel struct {
-	editor *Editor
+	editor        *Editor
+	rootDir       string         // Top of the browsable tree; Back stops here
+	currentDir    string         // Directory being listed
+	entries       []projectEntry // Listing of currentDir, folders first
+	needsRefresh  bool           // currentDir or the filter changed since the last listing
+	listError     string         // Why the last listing failed, if it did
+	filter        AssetFilter
+	scrollOffset  rl.Vector2
+	selectedAsset string // Path of the asset file picked last
+	lastClickPath string // Entry clicked last, for double-click detection
+	lastClickTime float64
+}
+
+// projectEntry is one file or folder in the project browser listing
+type projectEntry struct {
+	name  string
+	path  string
+	isDir bool
+}
+
+// AssetFilter limits which files the project browser lists
+type AssetFilter int
+
+const (
+	AssetFilterAll AssetFilter = iota
+	AssetFilterModels
+	AssetFilterTextures
+	AssetFilterAudio
+)
+
+// Project browser defaults
+const (
+	defaultProjectDir   = "assets"
+	projectItemHeight   = float32(20)
+	doubleClickInterval = 0.4 // Seconds between clicks that count as a double-click
+)
+
+var assetFilterNames = []string{"All", "Models", "Textures", "Audio"}
+
+// assetExtensions lists the file extensions each filter shows
+var assetExtensions = map[AssetFilter][]string{
+	AssetFilterModels:   {".obj", ".gltf", ".glb", ".iqm", ".vox", ".m3d"},
+	AssetFilterTextures: {".png", ".jpg", ".jpeg", ".bmp", ".tga", ".gif", ".hdr", ".dds"},
+	AssetFilterAudio:    {".wav", ".ogg", ".mp3", ".flac", ".xm", ".mod", ".qoa"},
 }
 
 // NewProjectBrowserPanel creates a new project browser panel
 func NewProjectBrowserPanel(editor *Editor) *ProjectBrowserPanel {
 	return &ProjectBrowserPanel{
-		editor: editor,
+		editor:       editor,
+		rootDir:      defaultProjectDir,
+		currentDir:   defaultProjectDir,
+		needsRefresh: true,
 	}
 }
 
@@ -532,7 +1110,91 @@ func (p *ProjectBrowserPanel) Initialize() error {
 }
 
 func (p *ProjectBrowserPanel) Update(deltaTime float32) {
-	// Update logic
+	if p.needsRefresh {
+		p.refresh()
+	}
+}
+
+// SetProjectDir makes dir the top of the browsable tree and lists it
+func (p *ProjectBrowserPanel) SetProjectDir(dir string) {
+	p.rootDir = dir
+	p.changeDir(dir)
+}
+
+// SetFilter limits the listing to one kind of asset
+func (p *ProjectBrowserPanel) SetFilter(filter AssetFilter) {
+	if filter != p.filter {
+		p.filter = filter
+		p.needsRefresh = true
+	}
+}
+
+// SelectedAsset returns the path of the asset file picked last, or "" if none
+func (p *ProjectBrowserPanel) SelectedAsset() string {
+	return p.selectedAsset
+}
+
+// changeDir switches the listing to dir
+func (p *ProjectBrowserPanel) changeDir(dir string) {
+	p.currentDir = dir
+	p.scrollOffset = rl.Vector2{}
+	p.needsRefresh = true
+}
+
+// goBack moves up one directory, stopping at the project root
+func (p *ProjectBrowserPanel) goBack() {
+	if filepath.Clean(p.currentDir) == filepath.Clean(p.rootDir) {
+		return
+	}
+	p.changeDir(filepath.Dir(p.currentDir))
+}
+
+// refresh reads currentDir into entries, folders first and then files matching the filter
+func (p *ProjectBrowserPanel) refresh() {
+	p.needsRefresh = false
+	p.entries = p.entries[:0]
+	p.listError = ""
+
+	dirEntries, err := os.ReadDir(p.currentDir)
+	if err != nil {
+		p.listError = err.Error()
+		return
+	}
+
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name(), ".") {
+			continue // Skip hidden files
+		}
+		if !dirEntry.IsDir() && !p.matchesFilter(dirEntry.Name()) {
+			continue
+		}
+		p.entries = append(p.entries, projectEntry{
+			name:  dirEntry.Name(),
+			path:  filepath.Join(p.currentDir, dirEntry.Name()),
+			isDir: dirEntry.IsDir(),
+		})
+	}
+
+	sort.Slice(p.entries, func(i, j int) bool {
+		if p.entries[i].isDir != p.entries[j].isDir {
+			return p.entries[i].isDir
+		}
+		return strings.ToLower(p.entries[i].name) < strings.ToLower(p.entries[j].name)
+	})
+}
+
+// matchesFilter reports whether a file named name passes the current filter
+func (p *ProjectBrowserPanel) matchesFilter(name string) bool {
+	if p.filter == AssetFilterAll {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, allowed := range assetExtensions[p.filter] {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *ProjectBrowserPanel) Render(rect rl.Rectangle) {
@@ -546,8 +1208,139 @@ func (p *ProjectBrowserPanel) Render(rect rl.Rectangle) {
 	rl.DrawRectangleRec(titleRect, rl.Color{R: 60, G: 60, B: 60, A: 255})
 	rl.DrawText("Project", int32(rect.X + 10), int32(rect.Y + 5), 12, rl.White)
 
-	// Content area
-	rl.DrawText("Project browser coming soon...", int32(rect.X + 10), int32(rect.Y + titleHeight + 10), 10, rl.Gray)
+	// Toolbar with back button, filters and the current path
+	toolbarHeight := float32(25)
+	p.renderToolbar(rl.Rectangle{X: rect.X + 5, Y: rect.Y + titleHeight + 3, Width: rect.Width - 10, Height: toolbarHeight - 6})
+
+	listRect := rl.Rectangle{
+		X: rect.X + 5,
+		Y: rect.Y + titleHeight + toolbarHeight + 5,
+		Width: rect.Width - 10,
+		Height: rect.Height - titleHeight - toolbarHeight - 10,
+	}
+	p.renderEntryList(listRect)
+}
+
+// renderToolbar draws the back button, the filter buttons and the current directory
+func (p *ProjectBrowserPanel) renderToolbar(rect rl.Rectangle) {
+	x := rect.X
+
+	atRoot := filepath.Clean(p.currentDir) == filepath.Clean(p.rootDir)
+	if renderToolbarButton(rl.Rectangle{X: x, Y: rect.Y, Width: 40, Height: rect.Height}, "Back", false, !atRoot) {
+		p.goBack()
+	}
+	x += 45
+
+	for i, name := range assetFilterNames {
+		buttonRect := rl.Rectangle{X: x, Y: rect.Y, Width: 55, Height: rect.Height}
+		if renderToolbarButton(buttonRect, name, p.filter == AssetFilter(i), true) {
+			p.SetFilter(AssetFilter(i))
+		}
+		x += 60
+	}
+
+	rl.DrawText(p.currentDir, int32(x + 5), int32(rect.Y + 4), 10, rl.LightGray)
+}
+
+// renderToolbarButton draws a small panel button and reports whether it was clicked
+func renderToolbarButton(rect rl.Rectangle, label string, active bool, enabled bool) bool {
+	hovered := enabled && rl.CheckCollisionPointRec(rl.GetMousePosition(), rect)
+
+	color := rl.Color{R: 65, G: 65, B: 65, A: 255}
+	if active {
+		color = rl.Color{R: 0, G: 120, B: 215, A: 255}
+	} else if hovered {
+		color = rl.Color{R: 85, G: 85, B: 85, A: 255}
+	}
+	textColor := rl.White
+	if !enabled {
+		textColor = rl.Gray
+	}
+
+	rl.DrawRectangleRec(rect, color)
+	rl.DrawText(label, int32(rect.X + 5), int32(rect.Y + 4), 10, textColor)
+	return hovered && rl.IsMouseButtonPressed(rl.MouseButtonLeft)
+}
+
+// renderEntryList draws the scrollable listing and handles selection and double-clicks
+func (p *ProjectBrowserPanel) renderEntryList(rect rl.Rectangle) {
+	if p.listError != "" {
+		rl.DrawText(p.listError, int32(rect.X + 5), int32(rect.Y + 5), 10, rl.Red)
+		return
+	}
+	if len(p.entries) == 0 {
+		rl.DrawText("Empty folder", int32(rect.X + 5), int32(rect.Y + 5), 10, rl.Gray)
+		return
+	}
+
+	mousePos := rl.GetMousePosition()
+	hovered := rl.CheckCollisionPointRec(mousePos, rect)
+
+	// Scroll with the mouse wheel, keeping the last entry at the bottom edge at most
+	if hovered {
+		p.scrollOffset.Y -= rl.GetMouseWheelMove() * projectItemHeight * 3
+	}
+	maxScroll := float32(len(p.entries)) * projectItemHeight - rect.Height
+	if p.scrollOffset.Y > maxScroll {
+		p.scrollOffset.Y = maxScroll
+	}
+	if p.scrollOffset.Y < 0 {
+		p.scrollOffset.Y = 0
+	}
+
+	rl.BeginScissorMode(int32(rect.X), int32(rect.Y), int32(rect.Width), int32(rect.Height))
+	defer rl.EndScissorMode()
+
+	var opened *projectEntry
+	for i := range p.entries {
+		entry := &p.entries[i]
+		y := rect.Y + float32(i) * projectItemHeight - p.scrollOffset.Y
+		if y + projectItemHeight < rect.Y {
+			continue
+		}
+		if y > rect.Y + rect.Height {
+			break
+		}
+
+		itemRect := rl.Rectangle{X: rect.X, Y: y, Width: rect.Width, Height: projectItemHeight}
+		if entry.path == p.selectedAsset {
+			rl.DrawRectangleRec(itemRect, rl.Color{R: 0, G: 120, B: 215, A: 255})
+		}
+
+		if hovered && rl.IsMouseButtonPressed(rl.MouseButtonLeft) && rl.CheckCollisionPointRec(mousePos, itemRect) {
+			now := rl.GetTime()
+			doubleClick := entry.path == p.lastClickPath && now - p.lastClickTime <= doubleClickInterval
+			p.lastClickPath = entry.path
+			p.lastClickTime = now
+
+			if entry.isDir && doubleClick {
+				opened = entry
+			} else if !entry.isDir {
+				p.selectedAsset = entry.path
+				// Double-clicking a prefab places a copy at the scene origin
+				if doubleClick && isPrefabPath(entry.path) {
+					p.lastClickPath = ""
+					if err := p.editor.InstantiatePrefabFile(entry.path, rl.Vector3{}); err != nil {
+						Logf(LogLevelError, "Failed to instantiate %s: %v", entry.name, err)
+					}
+				}
+			}
+		}
+
+		label := entry.name
+		labelColor := rl.White
+		if entry.isDir {
+			label = "[" + entry.name + "]"
+			labelColor = rl.Color{R: 230, G: 200, B: 110, A: 255}
+		}
+		rl.DrawText(label, int32(rect.X + 10), int32(y + 5), 10, labelColor)
+	}
+
+	// Descend after drawing so the listing isn't swapped out mid-loop
+	if opened != nil {
+		p.lastClickPath = ""
+		p.changeDir(opened.path)
+	}
 }
 
 func (p *ProjectBrowserPanel) Shutdown() {
@@ -556,22 +1349,64 @@ func (p *ProjectBrowserPanel) Shutdown() {
 
 // ConsolePanel shows debug console and logs
 type ConsolePanel struct {
-	editor *Editor
+	editor       *Editor
+	showLevels   [logLevelCount]bool // Levels the filter lets through
+	scrollOffset rl.Vector2
+	autoScroll   bool       // Follow the newest entry; off once the user scrolls up
+	entries      []LogEntry // Filtered copy of the log, rebuilt when it changes
+	seenVersion  uint64     // Log version entries was built from
+	filterDirty  bool       // The level filter changed since entries was built
+}
+
+// Height of one console line
+const consoleLineHeight = float32(14)
+
+var logLevelColors = [logLevelCount]rl.Color{
+	LogLevelInfo:  rl.LightGray,
+	LogLevelWarn:  rl.Color{R: 230, G: 200, B: 80, A: 255},
+	LogLevelError: rl.Color{R: 230, G: 90, B: 90, A: 255},
 }
 
 // NewConsolePanel creates a new console panel
 func NewConsolePanel(editor *Editor) *ConsolePanel {
 	return &ConsolePanel{
-		editor: editor,
+		editor:      editor,
+		showLevels:  [logLevelCount]bool{true, true, true},
+		autoScroll:  true,
+		filterDirty: true,
 	}
 }
 
 func (p *ConsolePanel) Initialize() error {
+	CaptureRaylibLog()
 	return nil
 }
 
 func (p *ConsolePanel) Update(deltaTime float32) {
-	// Update logic
+	if version := logVersion(); version != p.seenVersion || p.filterDirty {
+		p.seenVersion = version
+		p.filterDirty = false
+		p.rebuildEntries()
+	}
+}
+
+// SetLevelShown shows or hides entries of one level
+func (p *ConsolePanel) SetLevelShown(level LogLevel, shown bool) {
+	if level < 0 || int(level) >= logLevelCount || p.showLevels[level] == shown {
+		return
+	}
+	p.showLevels[level] = shown
+	p.filterDirty = true
+}
+
+// rebuildEntries copies the log entries that pass the level filter
+func (p *ConsolePanel) rebuildEntries() {
+	p.entries = p.entries[:0]
+	for _, entry := range LogEntries() {
+		if entry.Level >= 0 && int(entry.Level) < logLevelCount && p.showLevels[entry.Level] {
+			p.entries = append(p.entries, entry)
+		}
+	}
 }
 
 func (p *ConsolePanel) Render(rect rl.Rectangle) {
@@ -585,10 +1420,74 @@ func (p *ConsolePanel) Render(rect rl.Rectangle) {
 	rl.DrawRectangleRec(titleRect, rl.Color{R: 60, G: 60, B: 60, A: 255})
 	rl.DrawText("Console", int32(rect.X + 10), int32(rect.Y + 5), 12, rl.White)
 
-	// Content area
-	rl.DrawText("Console output will appear here...", int32(rect.X + 10), int32(rect.Y + titleHeight + 10), 10, rl.Gray)
+	// Clear button and level filter in the title bar
+	x := rect.X + 80
+	buttonY := rect.Y + 3
+	if renderToolbarButton(rl.Rectangle{X: x, Y: buttonY, Width: 45, Height: 19}, "Clear", false, true) {
+		ClearLog()
+		p.scrollOffset = rl.Vector2{}
+		p.autoScroll = true
+	}
+	x += 55
+	for i := 0; i < logLevelCount; i++ {
+		level := LogLevel(i)
+		if renderToolbarButton(rl.Rectangle{X: x, Y: buttonY, Width: 45, Height: 19}, level.String(), p.showLevels[level], true) {
+			p.SetLevelShown(level, !p.showLevels[level])
+		}
+		x += 50
+	}
+
+	listRect := rl.Rectangle{
+		X: rect.X + 5,
+		Y: rect.Y + titleHeight + 5,
+		Width: rect.Width - 10,
+		Height: rect.Height - titleHeight - 10,
+	}
+	p.renderEntries(listRect)
+}
+
+// renderEntries draws the filtered log, following the newest entry unless scrolled up
+func (p *ConsolePanel) renderEntries(rect rl.Rectangle) {
+	if len(p.entries) == 0 {
+		rl.DrawText("No messages", int32(rect.X + 5), int32(rect.Y + 5), 10, rl.Gray)
+		return
+	}
+
+	maxScroll := float32(len(p.entries)) * consoleLineHeight - rect.Height
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), rect) {
+		if wheel := rl.GetMouseWheelMove(); wheel != 0 {
+			p.scrollOffset.Y -= wheel * consoleLineHeight * 3
+			// Scrolling back down to the bottom resumes following new entries
+			p.autoScroll = p.scrollOffset.Y >= maxScroll
+		}
+	}
+	if p.autoScroll || p.scrollOffset.Y > maxScroll {
+		p.scrollOffset.Y = maxScroll
+	}
+	if p.scrollOffset.Y < 0 {
+		p.scrollOffset.Y = 0
+	}
+
+	rl.BeginScissorMode(int32(rect.X), int32(rect.Y), int32(rect.Width), int32(rect.Height))
+	defer rl.EndScissorMode()
+
+	first := int(p.scrollOffset.Y / consoleLineHeight)
+	for i := first; i < len(p.entries); i++ {
+		y := rect.Y + float32(i) * consoleLineHeight - p.scrollOffset.Y
+		if y > rect.Y + rect.Height {
+			break
+		}
+
+		entry := p.entries[i]
+		line := fmt.Sprintf("[%s] %s", entry.Time.Format("15:04:05"), entry.Message)
+		rl.DrawText(line, int32(rect.X + 5), int32(y + 2), 10, logLevelColors[entry.Level])
+	}
 }
 
 func (p *ConsolePanel) Shutdown() {
 	// Cleanup
-}
\ No newline at end of file
+}
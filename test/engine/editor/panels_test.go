@@ -0,0 +1,54 @@
+package editor
+
+import (
+	"testing"
+
+	"gameengine/core"
+)
+
+func TestNameCacheRebuildsOnItsCadence(t *testing.T) {
+	p := NewSceneHierarchyPanel(nil)
+	p.SetCacheInterval(10)
+	entities := []core.EntityID{1, 2, 3}
+
+	// One frame of the panel: Update counts it, Render checks the cache
+	rebuilds := 0
+	frame := func() {
+		p.frameCount++
+		if p.needsNameCacheRebuild(entities) {
+			p.rebuildNameCache(entities)
+			rebuilds++
+		}
+	}
+
+	for i := 0; i < 30; i++ {
+		frame()
+	}
+	if rebuilds != 3 {
+		t.Errorf("%d rebuilds over 30 frames with an interval of 10, want 3", rebuilds)
+	}
+
+	// A new entity or a rename shows up on the next frame, not the next interval
+	entities = append(entities, 4)
+	frame()
+	if rebuilds != 4 || p.entityNames[4] == "" {
+		t.Errorf("new entity not picked up on the next frame (%d rebuilds)", rebuilds)
+	}
+	p.SetEntityName(2, "Crate")
+	frame()
+	if rebuilds != 5 || p.entityNames[2] != "Crate" {
+		t.Errorf("rename not picked up on the next frame: %q (%d rebuilds)", p.entityNames[2], rebuilds)
+	}
+
+	// Unchanged frames after that wait out the full interval again
+	for i := 0; i < 9; i++ {
+		frame()
+	}
+	if rebuilds != 5 {
+		t.Errorf("%d rebuilds within the interval after a rename, want 5", rebuilds)
+	}
+	frame()
+	if rebuilds != 6 {
+		t.Errorf("no rebuild once the interval elapsed (%d rebuilds)", rebuilds)
+	}
+}
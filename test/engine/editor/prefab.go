@@ -0,0 +1,126 @@
+// Prefabs: reusable entity templates for the game engine editor
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gameengine/components"
+	"gameengine/core"
+	"gameengine/ecs"
+	"gameengine/systems"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// prefabExtension marks prefab files in the project browser
+const prefabExtension = ".prefab.json"
+
+// Prefab is a saved set of components that Instantiate copies onto new entities. The
+// components are held in their JSON form, so every instance gets its own copy.
+type Prefab struct {
+	Version    int                        `json:"version"`
+	Name       string                     `json:"name"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// NewPrefabFromEntity captures an entity's registered components as a prefab
+func NewPrefabFromEntity(world *ecs.World, entityID core.EntityID, name string) (*Prefab, error) {
+	saved, err := marshalEntity(world, entityID)
+	if err != nil {
+		return nil, err
+	}
+	prefab := &Prefab{Version: sceneFileVersion, Name: name, Components: saved}
+
+	if len(prefab.Components) == 0 {
+		return nil, fmt.Errorf("entity %d has no components that can be saved", entityID)
+	}
+	return prefab, nil
+}
+
+// Instantiate creates an entity in world from prefab, places it at position and returns its
+// ID. An entity from a prefab without a transform gets one. The components are all decoded
+// before the entity is created, so a prefab that fails to load adds nothing to world.
+func Instantiate(world *ecs.World, prefab *Prefab, position rl.Vector3) (core.EntityID, error) {
+	decoded, err := decodeComponents(prefab.Components)
+	if err != nil {
+		return 0, fmt.Errorf("prefab %q: %w", prefab.Name, err)
+	}
+
+	var transform *components.TransformComponent
+	for _, component := range decoded {
+		if t, ok := component.(*components.TransformComponent); ok {
+			transform = t
+		}
+	}
+	if transform == nil {
+		transform = components.NewTransformComponentAt(position)
+		decoded = append(decoded, transform)
+	}
+	transform.SetPosition(position)
+
+	entity := world.CreateEntity()
+	for _, component := range decoded {
+		entity.AddComponent(component)
+	}
+	entityID, _ := systems.FindEntity(world, components.TransformComponentType, transform)
+	return entityID, nil
+}
+
+// SavePrefab writes prefab to path as JSON
+func SavePrefab(prefab *Prefab, path string) error {
+	data, err := json.MarshalIndent(prefab, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadPrefab reads a prefab written by SavePrefab
+func LoadPrefab(path string) (*Prefab, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefab Prefab
+	if err := json.Unmarshal(data, &prefab); err != nil {
+		return nil, fmt.Errorf("failed to parse prefab: %w", err)
+	}
+	if prefab.Version != sceneFileVersion {
+		return nil, fmt.Errorf("unsupported prefab version %d", prefab.Version)
+	}
+	if prefab.Name == "" {
+		prefab.Name = strings.TrimSuffix(filepath.Base(path), prefabExtension)
+	}
+	return &prefab, nil
+}
+
+// isPrefabPath reports whether path names a prefab file
+func isPrefabPath(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), prefabExtension)
+}
+
+// InstantiatePrefabFile loads the prefab at path into the active scene at position and
+// selects the new entity
+func (e *Editor) InstantiatePrefabFile(path string, position rl.Vector3) error {
+	activeScene := e.gameEngine.GetSceneManager().GetActiveScene()
+	if activeScene == nil {
+		return fmt.Errorf("no active scene to add the prefab to")
+	}
+
+	prefab, err := LoadPrefab(path)
+	if err != nil {
+		return err
+	}
+	entityID, err := Instantiate(activeScene.GetWorld(), prefab, position)
+	if err != nil {
+		return err
+	}
+
+	e.SetSelectedEntity(entityID)
+	return nil
+}
@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gameengine/components"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestInstantiatePlacesPrefab(t *testing.T) {
+	world := ecs.NewWorld()
+	prefab := &Prefab{Name: "crate", Components: map[string]json.RawMessage{
+		transformComponentName: json.RawMessage(`{"position":{"X":9,"Y":9,"Z":9},"rotation":{"X":0,"Y":90,"Z":0},"scale":{"X":2,"Y":2,"Z":2}}`),
+		"MeshRenderer":         json.RawMessage(`{}`),
+	}}
+
+	entityID, err := Instantiate(world, prefab, rl.Vector3{X: 1, Y: 0, Z: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	component, ok := world.GetComponent(entityID, components.TransformComponentType)
+	if !ok {
+		t.Fatal("instance has no transform")
+	}
+	transform := component.(*components.TransformComponent)
+	if transform.Position != (rl.Vector3{X: 1, Y: 0, Z: 3}) || transform.Rotation.Y != 90 || transform.Scale.X != 2 {
+		t.Errorf("instance transform = %+v", *transform)
+	}
+	if _, ok := world.GetComponent(entityID, components.MeshRendererComponentType); !ok {
+		t.Error("instance has no mesh renderer")
+	}
+}
+
+func TestFailedInstantiateAddsNothing(t *testing.T) {
+	world := ecs.NewWorld()
+	broken := map[string]map[string]json.RawMessage{
+		"an unknown component": {
+			transformComponentName: json.RawMessage(`{}`),
+			"NoSuchComponent":      json.RawMessage(`{}`),
+		},
+		"a bad component": {
+			transformComponentName: json.RawMessage(`{}`),
+			"AudioSource":          json.RawMessage(`{"volume":"loud"}`),
+		},
+	}
+
+	for name, saved := range broken {
+		if _, err := Instantiate(world, &Prefab{Name: name, Components: saved}, rl.Vector3{}); err == nil {
+			t.Errorf("prefab with %s instantiated", name)
+		}
+	}
+	if left := world.GetEntitiesWithComponent(components.TransformComponentType); len(left) != 0 {
+		t.Errorf("failed instances left %d entities behind", len(left))
+	}
+}
+
+func TestInstancesDontShareComponents(t *testing.T) {
+	world := ecs.NewWorld()
+	speaker := world.CreateEntity()
+	speaker.AddComponent(components.NewTransformComponentAt(rl.Vector3{}))
+	audio := components.NewAudioSourceComponent(rl.Sound{})
+	audio.Volume = 0.6
+	speaker.AddComponent(audio)
+
+	entities := world.GetEntitiesWithComponent(components.AudioSourceComponentType)
+	prefab, err := NewPrefabFromEntity(world, entities[0], "speaker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := json.Marshal(prefab.Components)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := Instantiate(world, prefab, rl.Vector3{X: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Instantiate(world, prefab, rl.Vector3{X: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Edit the first instance the way the inspector would
+	component, _ := world.GetComponent(first, components.AudioSourceComponentType)
+	component.(*components.AudioSourceComponent).Volume = 0.1
+	component, _ = world.GetComponent(first, components.TransformComponentType)
+	component.(*components.TransformComponent).SetScale(rl.Vector3{X: 3, Y: 3, Z: 3})
+
+	component, _ = world.GetComponent(second, components.AudioSourceComponentType)
+	if volume := component.(*components.AudioSourceComponent).Volume; volume != 0.6 {
+		t.Errorf("second instance volume = %v after editing the first, want 0.6", volume)
+	}
+	component, _ = world.GetComponent(second, components.TransformComponentType)
+	if scale := component.(*components.TransformComponent).Scale; scale.X != 1 {
+		t.Errorf("second instance scale = %+v after editing the first", scale)
+	}
+	if audio.Volume != 0.6 {
+		t.Errorf("source entity volume = %v after editing an instance, want 0.6", audio.Volume)
+	}
+	if after, _ := json.Marshal(prefab.Components); string(after) != string(saved) {
+		t.Errorf("prefab changed by editing an instance:\n%s\nwant\n%s", after, saved)
+	}
+}
@@ -0,0 +1,145 @@
+// Scene export for the game engine editor
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+
+	"gameengine/components"
+	"gameengine/core"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// exportedComponentTypes lists the component types ExportSceneAsGo knows how to emit
+var exportedComponentTypes = []core.ComponentType{
+	components.TransformComponentType,
+	components.MeshRendererComponentType,
+	components.AudioSourceComponentType,
+	components.AudioListenerComponentType,
+	components.AudioReverbZoneComponentType,
+}
+
+// meshSource reports the model and tint the editor draws an entity's mesh renderer with.
+// The component doesn't hold them; the viewport does.
+type meshSource interface {
+	MeshAssignment(entityID core.EntityID) (string, rl.Color)
+}
+
+// ExportSceneAsGo writes the active scene as Go source that rebuilds it through the World API,
+// along with the model and tint this viewport draws each mesh renderer with
+func (p *ViewportPanel) ExportSceneAsGo(w io.Writer) error {
+	activeScene := p.editor.gameEngine.GetSceneManager().GetActiveScene()
+	if activeScene == nil {
+		return fmt.Errorf("no active scene to export")
+	}
+
+	return exportWorldAsGo(activeScene.GetWorld(), p, w)
+}
+
+// exportWorldAsGo emits a gofmt'd BuildScene function reconstructing every exportable entity.
+// BuildScene returns the model and tint of each mesh renderer for the caller's renderer to load.
+func exportWorldAsGo(world *ecs.World, meshes meshSource, w io.Writer) error {
+	var body bytes.Buffer
+
+	for i, entityID := range collectExportEntities(world) {
+		name := fmt.Sprintf("entity%d", i+1)
+		fmt.Fprintf(&body, "\n\t// Entity %d\n", entityID)
+		fmt.Fprintf(&body, "\t%s := world.CreateEntity()\n", name)
+
+		for _, componentType := range exportedComponentTypes {
+			component, ok := world.GetComponent(entityID, componentType)
+			if !ok {
+				continue
+			}
+			writeComponentAsGo(&body, name, component)
+			if componentType == components.MeshRendererComponentType {
+				model, tint := meshes.MeshAssignment(entityID)
+				fmt.Fprintf(&body, "\tmeshes[%s] = MeshRenderer{Model: %q, Tint: rl.Color{R: %d, G: %d, B: %d, A: %d}}\n",
+					name, model, tint.R, tint.G, tint.B, tint.A)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by the game engine editor. DO NOT EDIT.\n\n")
+	buf.WriteString("package scene\n\n")
+	buf.WriteString("import (\n")
+	buf.WriteString("\t\"gameengine/components\"\n")
+	buf.WriteString("\t\"gameengine/ecs\"\n")
+	buf.WriteString("\n\trl \"github.com/gen2brain/raylib-go/raylib\"\n")
+	buf.WriteString(")\n\n")
+	buf.WriteString("// MeshRenderer is the model and tint an exported mesh renderer was drawn with in the editor\n")
+	buf.WriteString("type MeshRenderer struct {\n")
+	buf.WriteString("\tModel string // Asset path; \"\" was drawn as a placeholder cube\n")
+	buf.WriteString("\tTint rl.Color\n")
+	buf.WriteString("}\n\n")
+	buf.WriteString("// BuildScene recreates the exported entities in world and returns each mesh renderer's\n")
+	buf.WriteString("// model and tint for the caller to load and assign\n")
+	buf.WriteString("func BuildScene(world *ecs.World) map[*ecs.Entity]MeshRenderer {\n")
+	buf.WriteString("\tmeshes := make(map[*ecs.Entity]MeshRenderer)\n")
+	buf.Write(body.Bytes())
+	buf.WriteString("\n\treturn meshes\n")
+	buf.WriteString("}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format exported scene: %w", err)
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+// collectExportEntities returns every entity holding an exportable component, in ID order
+func collectExportEntities(world *ecs.World) []core.EntityID {
+	seen := make(map[core.EntityID]bool)
+	var entities []core.EntityID
+
+	for _, componentType := range exportedComponentTypes {
+		for _, entityID := range world.GetEntitiesWithComponent(componentType) {
+			if !seen[entityID] {
+				seen[entityID] = true
+				entities = append(entities, entityID)
+			}
+		}
+	}
+
+	sort.Slice(entities, func(i, j int) bool { return entities[i] < entities[j] })
+	return entities
+}
+
+// writeComponentAsGo emits the statements that attach one component to the named entity
+func writeComponentAsGo(buf *bytes.Buffer, entity string, component interface{}) {
+	switch c := component.(type) {
+	case *components.TransformComponent:
+		fmt.Fprintf(buf, "\t%sTransform := components.NewTransformComponentAt(%#v)\n", entity, c.Position)
+		fmt.Fprintf(buf, "\t%sTransform.SetRotation(%#v)\n", entity, c.Rotation)
+		fmt.Fprintf(buf, "\t%sTransform.SetScale(%#v)\n", entity, c.Scale)
+		fmt.Fprintf(buf, "\t%s.AddComponent(%sTransform)\n", entity, entity)
+	case *components.MeshRendererComponent:
+		fmt.Fprintf(buf, "\t%s.AddComponent(&components.MeshRendererComponent{})\n", entity)
+	case *components.AudioSourceComponent:
+		// Sounds are runtime resources; the caller loads and assigns the clip
+		fmt.Fprintf(buf, "\t%sAudio := components.NewAudioSourceComponent(rl.Sound{})\n", entity)
+		fmt.Fprintf(buf, "\t%sAudio.Volume = %#v\n", entity, c.Volume)
+		fmt.Fprintf(buf, "\t%sAudio.Pitch = %#v\n", entity, c.Pitch)
+		fmt.Fprintf(buf, "\t%sAudio.IsLooping = %t\n", entity, c.IsLooping)
+		fmt.Fprintf(buf, "\t%sAudio.Is3D = %t\n", entity, c.Is3D)
+		fmt.Fprintf(buf, "\t%sAudio.SpatialBlend = %#v\n", entity, c.SpatialBlend)
+		fmt.Fprintf(buf, "\t%sAudio.DopplerFactor = %#v\n", entity, c.DopplerFactor)
+		fmt.Fprintf(buf, "\t%sAudio.Priority = %d\n", entity, c.Priority)
+		fmt.Fprintf(buf, "\t%sAudio.PlayOnAwake = %t\n", entity, c.PlayOnAwake)
+		fmt.Fprintf(buf, "\t%s.AddComponent(%sAudio)\n", entity, entity)
+	case *components.AudioListenerComponent:
+		fmt.Fprintf(buf, "\t%s.AddComponent(&components.AudioListenerComponent{SpeedOfSound: %#v, DopplerLevel: %#v})\n",
+			entity, c.SpeedOfSound, c.DopplerLevel)
+	case *components.AudioReverbZoneComponent:
+		fmt.Fprintf(buf, "\t%s.AddComponent(&components.AudioReverbZoneComponent{Enabled: %t, MinDistance: %#v, MaxDistance: %#v})\n",
+			entity, c.Enabled, c.MinDistance, c.MaxDistance)
+	}
+}
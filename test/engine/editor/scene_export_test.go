@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"gameengine/components"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestExportedSceneMatchesWorld(t *testing.T) {
+	world := ecs.NewWorld()
+
+	cube := world.CreateEntity()
+	cube.AddComponent(components.NewTransformComponentAt(rl.Vector3{X: 1, Y: 2, Z: 3}))
+	cube.AddComponent(&components.MeshRendererComponent{})
+
+	speaker := world.CreateEntity()
+	speaker.AddComponent(components.NewTransformComponentAt(rl.Vector3{}))
+	speaker.AddComponent(components.NewAudioSourceComponent(rl.Sound{}))
+
+	viewport := NewViewportPanel(nil)
+	cubeID := world.GetEntitiesWithComponent(components.MeshRendererComponentType)[0]
+	viewport.SetEntityModel(cubeID, "assets/crate.obj")
+	viewport.SetEntityColor(cubeID, rl.Color{R: 200, G: 40, B: 40, A: 255})
+
+	var out bytes.Buffer
+	if err := exportWorldAsGo(world, viewport, &out); err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "scene.go", out.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("exported scene doesn't parse: %v\n%s", err, out.String())
+	}
+
+	calls := make(map[string]int)
+	var models []string
+	var tints int
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok {
+				calls[sel.Sel.Name]++
+			}
+		case *ast.KeyValueExpr:
+			key, _ := n.Key.(*ast.Ident)
+			switch {
+			case key == nil:
+			case key.Name == "Model":
+				if lit, ok := n.Value.(*ast.BasicLit); ok {
+					model, _ := strconv.Unquote(lit.Value)
+					models = append(models, model)
+				}
+			case key.Name == "Tint":
+				tints++
+			}
+		}
+		return true
+	})
+
+	if calls["CreateEntity"] != 2 {
+		t.Errorf("%d entities created, want 2", calls["CreateEntity"])
+	}
+	if calls["AddComponent"] != 4 {
+		t.Errorf("%d components added, want 4", calls["AddComponent"])
+	}
+	if len(models) != 1 || models[0] != "assets/crate.obj" || tints != 1 {
+		t.Errorf("mesh renderer exported with models %q and %d tints, want the crate once", models, tints)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("rl.Color{R: 200, G: 40, B: 40, A: 255}")) {
+		t.Errorf("mesh tint missing from the export:\n%s", out.String())
+	}
+}
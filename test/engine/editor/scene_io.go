@@ -0,0 +1,361 @@
+// Scene save and load for the game engine editor
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"gameengine/components"
+	"gameengine/core"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// sceneFileVersion is bumped when the scene file layout changes incompatibly
+const sceneFileVersion = 1
+
+// defaultScenePath is where the editor's save and load shortcuts read and write
+const defaultScenePath = "scene.json"
+
+// sceneFile is the JSON layout of a saved scene
+type sceneFile struct {
+	Version  int           `json:"version"`
+	Entities []sceneEntity `json:"entities"`
+}
+
+// sceneEntity holds one entity's components keyed by codec name
+type sceneEntity struct {
+	ID         core.EntityID              `json:"id"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// ComponentCodec converts one component type to and from its JSON form.
+// Marshal gets the component as stored in the World; Unmarshal rebuilds one from its JSON form.
+type ComponentCodec struct {
+	Name      string
+	Marshal   func(component interface{}) (interface{}, error)
+	Unmarshal func(data json.RawMessage) (core.Component, error)
+}
+
+var (
+	componentCodecs     = make(map[core.ComponentType]ComponentCodec)
+	componentCodecOrder []core.ComponentType // Registration order, so saved files are stable
+)
+
+// RegisterComponentCodec lets a component type be saved with scenes
+func RegisterComponentCodec(componentType core.ComponentType, codec ComponentCodec) {
+	if _, exists := componentCodecs[componentType]; !exists {
+		componentCodecOrder = append(componentCodecOrder, componentType)
+	}
+	componentCodecs[componentType] = codec
+}
+
+// codecByName finds the registered codec saved under name
+func codecByName(name string) (ComponentCodec, bool) {
+	for _, componentType := range componentCodecOrder {
+		if codec := componentCodecs[componentType]; codec.Name == name {
+			return codec, true
+		}
+	}
+	return ComponentCodec{}, false
+}
+
+// SaveScene writes the active scene to path as JSON
+func (e *Editor) SaveScene(path string) error {
+	activeScene := e.gameEngine.GetSceneManager().GetActiveScene()
+	if activeScene == nil {
+		return fmt.Errorf("no active scene to save")
+	}
+
+	data, err := marshalWorld(activeScene.GetWorld())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadScene adds the entities in the file at path to the active scene. The World can't
+// remove entities, so the scene must not have any saved entities yet.
+func (e *Editor) LoadScene(path string) error {
+	activeScene := e.gameEngine.GetSceneManager().GetActiveScene()
+	if activeScene == nil {
+		return fmt.Errorf("no active scene to load into")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var scene sceneFile
+	if err := json.Unmarshal(data, &scene); err != nil {
+		return fmt.Errorf("failed to parse scene: %w", err)
+	}
+	if scene.Version != sceneFileVersion {
+		return fmt.Errorf("unsupported scene version %d", scene.Version)
+	}
+
+	world := activeScene.GetWorld()
+	if n := len(collectSavedEntities(world)); n > 0 {
+		return fmt.Errorf("the scene already has %d entities, and they can't be removed to make way", n)
+	}
+	e.SetSelectedEntity(0)
+
+	return unmarshalEntities(world, scene.Entities)
+}
+
+// marshalWorld encodes every entity holding a registered component
+func marshalWorld(world *ecs.World) ([]byte, error) {
+	scene := sceneFile{Version: sceneFileVersion}
+
+	for _, entityID := range collectSavedEntities(world) {
+		saved, err := marshalEntity(world, entityID)
+		if err != nil {
+			return nil, err
+		}
+		scene.Entities = append(scene.Entities, sceneEntity{ID: entityID, Components: saved})
+	}
+
+	return json.MarshalIndent(scene, "", "  ")
+}
+
+// marshalEntity encodes an entity's registered components keyed by codec name
+func marshalEntity(world *ecs.World, entityID core.EntityID) (map[string]json.RawMessage, error) {
+	saved := make(map[string]json.RawMessage)
+
+	for _, componentType := range componentCodecOrder {
+		component, ok := world.GetComponent(entityID, componentType)
+		if !ok {
+			continue
+		}
+		codec := componentCodecs[componentType]
+		value, err := codec.Marshal(component)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save %s of entity %d: %w", codec.Name, entityID, err)
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save %s of entity %d: %w", codec.Name, entityID, err)
+		}
+		saved[codec.Name] = raw
+	}
+	return saved, nil
+}
+
+// unmarshalEntities creates an entity in world for each saved one. IDs are assigned by the
+// World, so they can differ from the saved ones. Every entity is decoded before any is
+// created, so a file that fails to load leaves world as it was.
+func unmarshalEntities(world *ecs.World, entities []sceneEntity) error {
+	decoded := make([][]core.Component, len(entities))
+	for i, saved := range entities {
+		loaded, err := decodeComponents(saved.Components)
+		if err != nil {
+			return fmt.Errorf("entity %d: %w", saved.ID, err)
+		}
+		decoded[i] = loaded
+	}
+
+	for _, loaded := range decoded {
+		entity := world.CreateEntity()
+		for _, component := range loaded {
+			entity.AddComponent(component)
+		}
+	}
+	return nil
+}
+
+// decodeComponents rebuilds the components saved under their codec names, in registration order
+func decodeComponents(saved map[string]json.RawMessage) ([]core.Component, error) {
+	for name := range saved {
+		if _, ok := codecByName(name); !ok {
+			return nil, fmt.Errorf("unknown component %q", name)
+		}
+	}
+
+	var decoded []core.Component
+	for _, componentType := range componentCodecOrder {
+		codec := componentCodecs[componentType]
+		raw, ok := saved[codec.Name]
+		if !ok {
+			continue
+		}
+		component, err := codec.Unmarshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", codec.Name, err)
+		}
+		decoded = append(decoded, component)
+	}
+	return decoded, nil
+}
+
+// collectSavedEntities returns every entity holding a registered component, in ID order
+func collectSavedEntities(world *ecs.World) []core.EntityID {
+	seen := make(map[core.EntityID]bool)
+	var entities []core.EntityID
+
+	for _, componentType := range componentCodecOrder {
+		for _, entityID := range world.GetEntitiesWithComponent(componentType) {
+			if !seen[entityID] {
+				seen[entityID] = true
+				entities = append(entities, entityID)
+			}
+		}
+	}
+
+	sort.Slice(entities, func(i, j int) bool { return entities[i] < entities[j] })
+	return entities
+}
+
+// handleSceneShortcuts saves the scene on Ctrl+S and loads it back on Ctrl+O
+func (e *Editor) handleSceneShortcuts() {
+	ctrl := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+	if !ctrl {
+		return
+	}
+
+	if rl.IsKeyPressed(rl.KeyS) {
+		if err := e.SaveScene(defaultScenePath); err != nil {
+			Logf(LogLevelError, "Failed to save scene: %v", err)
+		} else {
+			Logf(LogLevelInfo, "Scene saved to %s", defaultScenePath)
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyO) {
+		if err := e.LoadScene(defaultScenePath); err != nil {
+			Logf(LogLevelError, "Failed to load scene: %v", err)
+		} else {
+			Logf(LogLevelInfo, "Scene loaded from %s", defaultScenePath)
+		}
+	}
+}
+
+// Codecs for the built-in components. Sounds and other runtime resources aren't saved;
+// the game assigns them after loading, as with exported scenes.
+
+// transformComponentName is the Transform codec's name in scene and prefab files
+const transformComponentName = "Transform"
+
+type transformData struct {
+	Position rl.Vector3 `json:"position"`
+	Rotation rl.Vector3 `json:"rotation"`
+	Scale    rl.Vector3 `json:"scale"`
+}
+
+type audioSourceData struct {
+	Volume        float32 `json:"volume"`
+	Pitch         float32 `json:"pitch"`
+	IsLooping     bool    `json:"is_looping"`
+	Is3D          bool    `json:"is_3d"`
+	SpatialBlend  float32 `json:"spatial_blend"`
+	DopplerFactor float32 `json:"doppler_factor"`
+	Priority      int     `json:"priority"`
+	PlayOnAwake   bool    `json:"play_on_awake"`
+}
+
+type audioListenerData struct {
+	Enabled      bool    `json:"enabled"`
+	SpeedOfSound float32 `json:"speed_of_sound"`
+	DopplerLevel float32 `json:"doppler_level"`
+}
+
+type audioReverbZoneData struct {
+	Enabled     bool    `json:"enabled"`
+	MinDistance float32 `json:"min_distance"`
+	MaxDistance float32 `json:"max_distance"`
+}
+
+func init() {
+	RegisterComponentCodec(components.TransformComponentType, ComponentCodec{
+		Name: transformComponentName,
+		Marshal: func(component interface{}) (interface{}, error) {
+			c := component.(*components.TransformComponent)
+			return transformData{Position: c.Position, Rotation: c.Rotation, Scale: c.Scale}, nil
+		},
+		Unmarshal: func(data json.RawMessage) (core.Component, error) {
+			var d transformData
+			if err := json.Unmarshal(data, &d); err != nil {
+				return nil, err
+			}
+			transform := components.NewTransformComponentAt(d.Position)
+			transform.SetRotation(d.Rotation)
+			transform.SetScale(d.Scale)
+			return transform, nil
+		},
+	})
+
+	RegisterComponentCodec(components.MeshRendererComponentType, ComponentCodec{
+		Name: "MeshRenderer",
+		Marshal: func(component interface{}) (interface{}, error) {
+			return struct{}{}, nil
+		},
+		Unmarshal: func(data json.RawMessage) (core.Component, error) {
+			return &components.MeshRendererComponent{}, nil
+		},
+	})
+
+	RegisterComponentCodec(components.AudioSourceComponentType, ComponentCodec{
+		Name: "AudioSource",
+		Marshal: func(component interface{}) (interface{}, error) {
+			c := component.(*components.AudioSourceComponent)
+			return audioSourceData{
+				Volume:        c.Volume,
+				Pitch:         c.Pitch,
+				IsLooping:     c.IsLooping,
+				Is3D:          c.Is3D,
+				SpatialBlend:  c.SpatialBlend,
+				DopplerFactor: c.DopplerFactor,
+				Priority:      c.Priority,
+				PlayOnAwake:   c.PlayOnAwake,
+			}, nil
+		},
+		Unmarshal: func(data json.RawMessage) (core.Component, error) {
+			var d audioSourceData
+			if err := json.Unmarshal(data, &d); err != nil {
+				return nil, err
+			}
+			audio := components.NewAudioSourceComponent(rl.Sound{})
+			audio.Volume = d.Volume
+			audio.Pitch = d.Pitch
+			audio.IsLooping = d.IsLooping
+			audio.Is3D = d.Is3D
+			audio.SpatialBlend = d.SpatialBlend
+			audio.DopplerFactor = d.DopplerFactor
+			audio.Priority = d.Priority
+			audio.PlayOnAwake = d.PlayOnAwake
+			return audio, nil
+		},
+	})
+
+	RegisterComponentCodec(components.AudioListenerComponentType, ComponentCodec{
+		Name: "AudioListener",
+		Marshal: func(component interface{}) (interface{}, error) {
+			c := component.(*components.AudioListenerComponent)
+			return audioListenerData{Enabled: c.Enabled, SpeedOfSound: c.SpeedOfSound, DopplerLevel: c.DopplerLevel}, nil
+		},
+		Unmarshal: func(data json.RawMessage) (core.Component, error) {
+			var d audioListenerData
+			if err := json.Unmarshal(data, &d); err != nil {
+				return nil, err
+			}
+			return &components.AudioListenerComponent{Enabled: d.Enabled, SpeedOfSound: d.SpeedOfSound, DopplerLevel: d.DopplerLevel}, nil
+		},
+	})
+
+	RegisterComponentCodec(components.AudioReverbZoneComponentType, ComponentCodec{
+		Name: "AudioReverbZone",
+		Marshal: func(component interface{}) (interface{}, error) {
+			c := component.(*components.AudioReverbZoneComponent)
+			return audioReverbZoneData{Enabled: c.Enabled, MinDistance: c.MinDistance, MaxDistance: c.MaxDistance}, nil
+		},
+		Unmarshal: func(data json.RawMessage) (core.Component, error) {
+			var d audioReverbZoneData
+			if err := json.Unmarshal(data, &d); err != nil {
+				return nil, err
+			}
+			return &components.AudioReverbZoneComponent{Enabled: d.Enabled, MinDistance: d.MinDistance, MaxDistance: d.MaxDistance}, nil
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gameengine/components"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSceneRoundTrip(t *testing.T) {
+	world := ecs.NewWorld()
+
+	crate := world.CreateEntity()
+	transform := components.NewTransformComponentAt(rl.Vector3{X: 1, Y: 2, Z: 3})
+	transform.SetRotation(rl.Vector3{Y: 45})
+	transform.SetScale(rl.Vector3{X: 2, Y: 2, Z: 2})
+	crate.AddComponent(transform)
+	crate.AddComponent(&components.MeshRendererComponent{})
+
+	speaker := world.CreateEntity()
+	speaker.AddComponent(components.NewTransformComponentAt(rl.Vector3{X: -4}))
+	audio := components.NewAudioSourceComponent(rl.Sound{})
+	audio.Volume = 0.6
+	audio.Pitch = 1.2
+	audio.IsLooping = true
+	audio.Is3D = true
+	audio.SpatialBlend = 0.8
+	audio.Priority = 3
+	speaker.AddComponent(audio)
+
+	room := world.CreateEntity()
+	room.AddComponent(components.NewTransformComponentAt(rl.Vector3{}))
+	room.AddComponent(&components.AudioListenerComponent{Enabled: true, SpeedOfSound: 343, DopplerLevel: 0.5})
+	room.AddComponent(&components.AudioReverbZoneComponent{Enabled: true, MinDistance: 5, MaxDistance: 20})
+
+	data, err := marshalWorld(world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved sceneFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Entities) != 3 {
+		t.Fatalf("%d entities saved, want 3", len(saved.Entities))
+	}
+
+	loaded := ecs.NewWorld()
+	if err := unmarshalEntities(loaded, saved.Entities); err != nil {
+		t.Fatal(err)
+	}
+	again, err := marshalWorld(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resaved sceneFile
+	if err := json.Unmarshal(again, &resaved); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resaved.Entities) != len(saved.Entities) {
+		t.Fatalf("%d entities after loading, want %d", len(resaved.Entities), len(saved.Entities))
+	}
+	for i := range saved.Entities {
+		if !reflect.DeepEqual(resaved.Entities[i].Components, saved.Entities[i].Components) {
+			t.Errorf("entity %d loaded as %s, saved as %s", saved.Entities[i].ID,
+				resaved.Entities[i].Components, saved.Entities[i].Components)
+		}
+	}
+
+	for _, entityID := range loaded.GetEntitiesWithComponent(components.AudioSourceComponentType) {
+		component, _ := loaded.GetComponent(entityID, components.AudioSourceComponentType)
+		if got := component.(*components.AudioSourceComponent); got.Volume != 0.6 || got.Priority != 3 || !got.IsLooping {
+			t.Errorf("audio source loaded as %+v", *got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Grid snapping for transform edits in the game engine editor
+package editor
+
+import (
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Default rotation snap step, in degrees
+const defaultSnapAngle = 15
+
+// GridSnap holds the editor's snapping settings. Translation snaps to the editor's grid
+// spacing; rotation snaps to AngleStep.
+type GridSnap struct {
+	Enabled   bool
+	AngleStep float32 // Degrees
+}
+
+var gridSnap = GridSnap{AngleStep: defaultSnapAngle}
+
+// SetSnapEnabled turns snapping of transform edits on or off
+func SetSnapEnabled(enabled bool) {
+	gridSnap.Enabled = enabled
+}
+
+// SnapEnabled reports whether transform edits are snapped
+func SnapEnabled() bool {
+	return gridSnap.Enabled
+}
+
+// SetSnapAngle sets the rotation snap step in degrees; steps of zero or less are ignored
+func SetSnapAngle(degrees float32) {
+	if degrees > 0 {
+		gridSnap.AngleStep = degrees
+	}
+}
+
+// SnapAngle returns the rotation snap step in degrees
+func SnapAngle() float32 {
+	return gridSnap.AngleStep
+}
+
+// snapStep rounds value to the nearest multiple of step
+func snapStep(value, step float32) float32 {
+	if step <= 0 {
+		return value
+	}
+	return float32(math.Round(float64(value/step))) * step
+}
+
+// translationSnapStep is the grid spacing translate edits snap to
+func (e *Editor) translationSnapStep() float32 {
+	if e.gridSpacing > 0 {
+		return e.gridSpacing
+	}
+	return 1
+}
+
+// SnapTranslation rounds a translate delta to whole grid cells when snapping is on
+func (e *Editor) SnapTranslation(delta rl.Vector3) rl.Vector3 {
+	if !gridSnap.Enabled {
+		return delta
+	}
+	step := e.translationSnapStep()
+	return rl.Vector3{X: snapStep(delta.X, step), Y: snapStep(delta.Y, step), Z: snapStep(delta.Z, step)}
+}
+
+// SnapRotation rounds a rotation delta in degrees to the angle step when snapping is on
+func (e *Editor) SnapRotation(delta rl.Vector3) rl.Vector3 {
+	if !gridSnap.Enabled {
+		return delta
+	}
+	step := gridSnap.AngleStep
+	return rl.Vector3{X: snapStep(delta.X, step), Y: snapStep(delta.Y, step), Z: snapStep(delta.Z, step)}
+}
+
+// snapLabel describes the current snap setting for the viewport's title bar
+func (e *Editor) snapLabel() string {
+	if !gridSnap.Enabled {
+		return "Snap: Off (Ctrl+G)"
+	}
+	return fmt.Sprintf("Snap: %g units, %.0f deg (Ctrl+G)", e.translationSnapStep(), gridSnap.AngleStep)
+}
+
+// handleSnapShortcut toggles snapping on Ctrl+G
+func (e *Editor) handleSnapShortcut() {
+	ctrl := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+	if ctrl && rl.IsKeyPressed(rl.KeyG) {
+		SetSnapEnabled(!gridSnap.Enabled)
+	}
+}
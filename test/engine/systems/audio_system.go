@@ -3,6 +3,8 @@ package systems
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"gameengine/components"
 	"gameengine/core"
 	"gameengine/ecs"
@@ -11,19 +13,37 @@ import (
 
 // AudioSystem handles 3D audio processing and playback
 type AudioSystem struct {
-	world           *ecs.World
-	masterVolume    float32
-	listenerEntity  core.EntityID
-	maxAudioSources int
+	world              *ecs.World
+	masterVolume       float32
+	listenerEntity     core.EntityID
+	activeListener     core.EntityID // Listener picked with SetActiveListener, 0 to use the first found
+	maxAudioSources    int
 	activeAudioSources []ActiveAudioSource
-	reverbZones     []ReverbZoneData
-	initialized     bool
-	audioDevice     bool
-	sampleRate      int
-	bufferSize      int
-	channels        int
-	distanceModel   DistanceModel
-	dopplerEnabled  bool
+	reverbZones        []ReverbZoneData
+	initialized        bool
+	audioDevice        bool
+	sampleRate         int
+	bufferSize         int
+	channels           int
+	distanceModel      DistanceModel
+	rolloffFactor      float32
+	dopplerEnabled     bool
+	sourceCones        map[core.EntityID]AudioCone
+	music              map[string]*MusicTrack
+	groupVolumes       map[string]float32
+	sourceGroups       map[core.EntityID]string
+	oneShots           []OneShot
+	idleOneShots       []OneShot              // Finished one-shot entities, reused by PlayOneShot
+	idleOneShotIDs     map[core.EntityID]bool // Entities in idleOneShots, kept out of the mix
+	paused             bool
+	pausedSources      []*components.AudioSourceComponent
+	pausedMusic        []*MusicTrack
+	sourceFades        map[core.EntityID]*Fade
+	occludable         map[core.EntityID]bool
+	occlusionGain      float32
+	occluders          []Occluder
+	listenerPosition   rl.Vector3 // World position of listenerEntity this frame
+	listenerVelocities map[core.EntityID]ListenerVelocity
 }
 
 // ActiveAudioSource tracks currently playing audio sources
@@ -33,10 +53,27 @@ type ActiveAudioSource struct {
 	Transform    *components.TransformComponent
 	Priority     int
 	Distance     float32
-	Volume       float32
+	Volume       float32 // Estimated from distance for culling, then the level actually applied
 	IsAudible    bool
 	LastPosition rl.Vector3
 	Velocity     rl.Vector3
+	ReverbWet    float32    // Wet send level from the reverb zones around the listener, 0 is dry
+	Position     rl.Vector3 // World position this frame, after parent transforms
+}
+
+// OneShot tracks an entity spawned by PlayOneShot until its sound finishes
+type OneShot struct {
+	EntityID    core.EntityID
+	Transform   *components.TransformComponent
+	AudioSource *components.AudioSourceComponent
+	Length      float32 // Clip length in seconds, 0 if unknown
+	Elapsed     float32
+}
+
+// Occluder is a mesh entity that can block sound, boxed by its transform
+type Occluder struct {
+	EntityID core.EntityID
+	Box      rl.BoundingBox
 }
 
 // ReverbZoneData contains reverb zone information
@@ -47,6 +84,98 @@ type ReverbZoneData struct {
 	Influence   float32
 }
 
+// AudioCone describes a directional source. Angles are full cone widths in degrees;
+// inside the inner cone the source plays at full volume, outside the outer cone it
+// plays at OuterGain, and the gain is interpolated in between.
+type AudioCone struct {
+	InnerAngle float32
+	OuterAngle float32
+	OuterGain  float32
+}
+
+// OmnidirectionalCone is the default cone: every direction plays at full volume
+var OmnidirectionalCone = AudioCone{InnerAngle: 360, OuterAngle: 360, OuterGain: 1}
+
+// ListenerVelocityMode chooses where a listener's velocity for Doppler comes from
+type ListenerVelocityMode int
+
+const (
+	ListenerVelocityAuto     ListenerVelocityMode = iota // Derived from how far the listener moved since last frame
+	ListenerVelocityExplicit                             // Supplied with SetListenerVelocity
+)
+
+// ListenerVelocity is a listener's velocity setting. Velocity is used only in
+// ListenerVelocityExplicit mode.
+type ListenerVelocity struct {
+	Mode     ListenerVelocityMode
+	Velocity rl.Vector3
+}
+
+// Audio groups mix under the master volume so one kind of sound can be turned down
+// without the others. Sources are in AudioGroupSFX unless assigned elsewhere.
+const (
+	AudioGroupSFX      = "sfx"
+	AudioGroupMusic    = "music"
+	AudioGroupAmbience = "ambience"
+)
+
+// MusicTrack is a streamed music track. Music plays flat: it ignores the listener,
+// distance attenuation and the audio source limit.
+type MusicTrack struct {
+	Music   rl.Music
+	Volume  float32
+	Group   string
+	fade    *Fade
+	playing bool
+	paused  bool
+}
+
+// FadeCurve shapes how a fade moves between its start and target volumes
+type FadeCurve int
+
+const (
+	FadeLinear      FadeCurve = iota
+	FadeExponential           // Starts slow and finishes fast, for natural-sounding fade-ins
+	FadeLogarithmic           // Starts fast and finishes slow, for natural-sounding tails
+	FadeSCurve                // Eases in and out, for cross-fades
+)
+
+// fadeCurveSteepness sets how strongly the exponential and logarithmic curves bend
+const fadeCurveSteepness = 4.0
+
+// Fade moves a volume from From to To over Duration seconds along Curve
+type Fade struct {
+	From     float32
+	To       float32
+	Duration float32
+	Curve    FadeCurve
+	Elapsed  float32
+}
+
+// Advance moves the fade on by deltaTime and returns the current volume and whether it is done
+func (f *Fade) Advance(deltaTime float32) (float32, bool) {
+	f.Elapsed += deltaTime
+	if f.Duration <= 0 || f.Elapsed >= f.Duration {
+		return f.To, true
+	}
+	return f.From + (f.To-f.From)*fadeCurveValue(f.Curve, f.Elapsed/f.Duration), false
+}
+
+// fadeCurveValue maps progress t in [0, 1] onto the curve, also in [0, 1]
+func fadeCurveValue(curve FadeCurve, t float32) float32 {
+	x := float64(t)
+	switch curve {
+	case FadeExponential:
+		return float32((math.Exp(fadeCurveSteepness*x) - 1) / (math.Exp(fadeCurveSteepness) - 1))
+	case FadeLogarithmic:
+		return float32(math.Log1p((math.Exp(fadeCurveSteepness)-1)*x) / fadeCurveSteepness)
+	case FadeSCurve:
+		return float32(x * x * (3 - 2*x))
+	default:
+		return t
+	}
+}
+
 // DistanceModel defines how audio volume changes with distance
 type DistanceModel int
 
@@ -75,7 +204,18 @@ func NewAudioSystem(world *ecs.World) *AudioSystem {
 		bufferSize:         1024,
 		channels:           2,
 		distanceModel:      InverseDistanceClamped,
+		rolloffFactor:      1.0,
 		dopplerEnabled:     true,
+		sourceCones:        make(map[core.EntityID]AudioCone),
+		music:              make(map[string]*MusicTrack),
+		groupVolumes:       make(map[string]float32),
+		sourceGroups:       make(map[core.EntityID]string),
+		sourceFades:        make(map[core.EntityID]*Fade),
+		occludable:         make(map[core.EntityID]bool),
+		occlusionGain:      0.4,
+		occluders:          make([]Occluder, 0, 32),
+		listenerVelocities: make(map[core.EntityID]ListenerVelocity),
+		idleOneShotIDs:     make(map[core.EntityID]bool),
 	}
 }
 
@@ -106,6 +246,9 @@ func (as *AudioSystem) Initialize() error {
 
 // Shutdown shuts down the audio system
 func (as *AudioSystem) Shutdown() {
+	for name := range as.music {
+		as.UnloadMusic(name)
+	}
 	if as.audioDevice {
 		rl.CloseAudioDevice()
 		as.audioDevice = false
@@ -124,8 +267,16 @@ func (as *AudioSystem) Update(deltaTime float32) {
 		return
 	}
 
+	// Everything holds still while paused, so playback resumes exactly where it stopped
+	if as.paused {
+		return
+	}
+
 	// Find the audio listener
 	as.findAudioListener()
+	if as.listenerEntity != 0 {
+		as.listenerPosition = as.worldPosition(as.listenerEntity)
+	}
 
 	// Update audio sources
 	as.updateAudioSources(deltaTime)
@@ -138,19 +289,52 @@ func (as *AudioSystem) Update(deltaTime float32) {
 
 	// Update sound playback
 	as.updateSoundPlayback(deltaTime)
+
+	// Set aside one-shot entities whose sound has finished
+	as.cleanupOneShots(deltaTime)
+
+	// Keep music streams fed
+	as.updateMusic(deltaTime)
 }
 
 // findAudioListener finds the active audio listener
 func (as *AudioSystem) findAudioListener() {
 	listenerEntities := as.world.GetEntitiesWithComponents(components.AudioListenerComponentType, components.TransformComponentType)
 
-	// Use the first active listener found
+	// Forget a listener that no longer exists so the 2D fallback kicks in
+	as.listenerEntity = 0
+
+	// Use the designated listener if it is there and enabled, otherwise the enabled
+	// listener with the lowest ID so the choice doesn't change between frames
 	for _, entityID := range listenerEntities {
-		as.listenerEntity = entityID
-		break
+		if !as.isListenerEnabled(entityID) {
+			continue
+		}
+		if entityID == as.activeListener {
+			as.listenerEntity = entityID
+			return
+		}
+		if as.listenerEntity == 0 || entityID < as.listenerEntity {
+			as.listenerEntity = entityID
+		}
 	}
 }
 
+// worldPosition returns where an entity is in the world, composing its parents' transforms
+func (as *AudioSystem) worldPosition(entityID core.EntityID) rl.Vector3 {
+	return TransformHierarchyOf(as.world).GetWorldPosition(entityID)
+}
+
+// isListenerEnabled reports whether the entity's audio listener is switched on
+func (as *AudioSystem) isListenerEnabled(entityID core.EntityID) bool {
+	listenerComp, exists := as.world.GetComponent(entityID, components.AudioListenerComponentType)
+	if !exists {
+		return false
+	}
+	listener, ok := listenerComp.(*components.AudioListenerComponent)
+	return ok && listener.Enabled
+}
+
 // updateAudioSources updates all audio sources
 func (as *AudioSystem) updateAudioSources(deltaTime float32) {
 	as.activeAudioSources = as.activeAudioSources[:0]
@@ -158,13 +342,30 @@ func (as *AudioSystem) updateAudioSources(deltaTime float32) {
 	audioEntities := as.world.GetEntitiesWithComponents(components.AudioSourceComponentType, components.TransformComponentType)
 
 	for _, entityID := range audioEntities {
+		// Finished one-shots wait silently for PlayOneShot without taking a voice
+		if as.idleOneShotIDs[entityID] {
+			continue
+		}
+
 		audioComp, _ := as.world.GetComponent(entityID, components.AudioSourceComponentType)
 		transformComp, _ := as.world.GetComponent(entityID, components.TransformComponentType)
 
 		if audioSource, ok := audioComp.(*components.AudioSourceComponent); ok {
 			if transform, ok := transformComp.(*components.TransformComponent); ok {
+				// Take the clip length from the sound itself if it was never set
+				if audioSource.AudioClipLength <= 0 {
+					audioSource.AudioClipLength = SoundLength(audioSource.Sound)
+				}
+
 				// Update fade effects
 				audioSource.UpdateFade(deltaTime)
+				if fade, ok := as.sourceFades[entityID]; ok {
+					volume, done := fade.Advance(deltaTime)
+					audioSource.Volume = volume
+					if done {
+						delete(as.sourceFades, entityID)
+					}
+				}
 
 				// Update current time
 				if audioSource.IsPlaying && !audioSource.IsPaused {
@@ -179,28 +380,32 @@ func (as *AudioSystem) updateAudioSources(deltaTime float32) {
 						rl.StopSound(audioSource.Sound)
 						rl.PlaySound(audioSource.Sound)
 					}
+				} else if audioSource.IsLooping && audioSource.IsPlaying && !audioSource.IsPaused {
+					// Unknown length - start over once raylib reports the sound has ended
+					if audioSource.CurrentTime > 0 && !rl.IsSoundPlaying(audioSource.Sound) {
+						audioSource.CurrentTime = 0.0
+						rl.PlaySound(audioSource.Sound)
+					}
 				}
 
 				// Create active audio source entry
+				position := as.worldPosition(entityID)
 				activeSource := ActiveAudioSource{
 					EntityID:     entityID,
 					AudioSource:  audioSource,
 					Transform:    transform,
 					Priority:     audioSource.Priority,
-					LastPosition: transform.Position,
+					LastPosition: position,
+					Position:     position,
 				}
 
 				// Calculate distance to listener
 				if as.listenerEntity != 0 {
-					if listenerTransformComp, exists := as.world.GetComponent(as.listenerEntity, components.TransformComponentType); exists {
-						if listenerTransform, ok := listenerTransformComp.(*components.TransformComponent); ok {
-							activeSource.Distance = core.Vector3Distance(transform.Position, listenerTransform.Position)
-						}
-					}
+					activeSource.Distance = core.Vector3Distance(position, as.listenerPosition)
 				}
 
 				// Calculate effective volume
-				activeSource.Volume = audioSource.GetEffectiveVolume(activeSource.Distance)
+				activeSource.Volume = as.attenuatedVolume(audioSource, activeSource.Distance) * as.sourceGroupVolume(entityID)
 				activeSource.IsAudible = activeSource.Volume > 0.01 // Threshold for audibility
 
 				as.activeAudioSources = append(as.activeAudioSources, activeSource)
@@ -215,27 +420,20 @@ func (as *AudioSystem) updateAudioSources(deltaTime float32) {
 	as.limitAudioSources()
 }
 
-// sortAudioSources sorts audio sources by priority and distance
+// sortAudioSources sorts audio sources by priority (higher first), then by distance (closer
+// first). Ties go to the lower entity ID, so equal sources keep their voices frame to frame
+// instead of swapping in and out of the limit.
 func (as *AudioSystem) sortAudioSources() {
-	// Simple bubble sort by priority (higher first), then by distance (closer first)
-	n := len(as.activeAudioSources)
-	for i := 0; i < n-1; i++ {
-		for j := 0; j < n-i-1; j++ {
-			a := as.activeAudioSources[j]
-			b := as.activeAudioSources[j+1]
-
-			shouldSwap := false
-			if a.Priority < b.Priority {
-				shouldSwap = true
-			} else if a.Priority == b.Priority && a.Distance > b.Distance {
-				shouldSwap = true
-			}
-
-			if shouldSwap {
-				as.activeAudioSources[j], as.activeAudioSources[j+1] = as.activeAudioSources[j+1], as.activeAudioSources[j]
-			}
+	sources := as.activeAudioSources
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Priority != sources[j].Priority {
+			return sources[i].Priority > sources[j].Priority
 		}
-	}
+		if sources[i].Distance != sources[j].Distance {
+			return sources[i].Distance < sources[j].Distance
+		}
+		return sources[i].EntityID < sources[j].EntityID
+	})
 }
 
 // limitAudioSources limits the number of simultaneously playing audio sources
@@ -260,21 +458,15 @@ func (as *AudioSystem) limitAudioSources() {
 func (as *AudioSystem) updateReverbZones() {
 	as.reverbZones = as.reverbZones[:0]
 
-	reverbEntities := as.world.GetEntitiesWithComponents(components.AudioReverbZoneComponentType, components.TransformComponentType)
-
+	// Without a listener there is no room to be in; process2DFallback plays everything dry
 	if as.listenerEntity == 0 {
 		return
 	}
-
-	listenerTransformComp, exists := as.world.GetComponent(as.listenerEntity, components.TransformComponentType)
-	if !exists {
+	if _, exists := as.world.GetComponent(as.listenerEntity, components.TransformComponentType); !exists {
 		return
 	}
 
-	listenerTransform, ok := listenerTransformComp.(*components.TransformComponent)
-	if !ok {
-		return
-	}
+	reverbEntities := as.world.GetEntitiesWithComponents(components.AudioReverbZoneComponentType, components.TransformComponentType)
 
 	for _, entityID := range reverbEntities {
 		reverbComp, _ := as.world.GetComponent(entityID, components.AudioReverbZoneComponentType)
@@ -283,7 +475,7 @@ func (as *AudioSystem) updateReverbZones() {
 		if reverbZone, ok := reverbComp.(*components.AudioReverbZoneComponent); ok {
 			if transform, ok := transformComp.(*components.TransformComponent); ok {
 				if reverbZone.Enabled {
-					distance := core.Vector3Distance(listenerTransform.Position, transform.Position)
+					distance := core.Vector3Distance(as.listenerPosition, as.worldPosition(entityID))
 					influence := as.calculateReverbInfluence(distance, reverbZone)
 
 					if influence > 0.0 {
@@ -300,6 +492,18 @@ func (as *AudioSystem) updateReverbZones() {
 	}
 }
 
+// reverbInfluence returns the strongest influence among the reverb zones around the listener.
+// Overlapping zones don't add up; the listener hears the room it is most inside of.
+func (as *AudioSystem) reverbInfluence() float32 {
+	strongest := float32(0.0)
+	for _, zone := range as.reverbZones {
+		if zone.Influence > strongest {
+			strongest = zone.Influence
+		}
+	}
+	return strongest
+}
+
 // calculateReverbInfluence calculates the influence of a reverb zone
 func (as *AudioSystem) calculateReverbInfluence(distance float32, reverbZone *components.AudioReverbZoneComponent) float32 {
 	if distance <= reverbZone.MinDistance {
@@ -315,16 +519,19 @@ func (as *AudioSystem) calculateReverbInfluence(distance float32, reverbZone *co
 // process3DAudio processes 3D spatial audio effects
 func (as *AudioSystem) process3DAudio(deltaTime float32) {
 	if as.listenerEntity == 0 {
+		as.process2DFallback()
 		return
 	}
 
 	listenerTransformComp, exists := as.world.GetComponent(as.listenerEntity, components.TransformComponentType)
 	if !exists {
+		as.process2DFallback()
 		return
 	}
 
 	listenerTransform, ok := listenerTransformComp.(*components.TransformComponent)
 	if !ok {
+		as.process2DFallback()
 		return
 	}
 
@@ -334,10 +541,17 @@ func (as *AudioSystem) process3DAudio(deltaTime float32) {
 	if exists {
 		if l, ok := listenerComp.(*components.AudioListenerComponent); ok {
 			listener = l
-			listener.UpdateVelocity(listenerTransform.Position, deltaTime)
+			// Track movement even with an explicit velocity, so switching back to auto doesn't jump
+			listener.UpdateVelocity(as.listenerPosition, deltaTime)
 		}
 	}
 
+	// Gather blocking geometry once per frame, and only if a source can be occluded
+	as.occluders = as.occluders[:0]
+	if len(as.occludable) > 0 {
+		as.collectOccluders()
+	}
+
 	// Process each active audio source
 	for i := range as.activeAudioSources {
 		source := &as.activeAudioSources[i]
@@ -345,20 +559,51 @@ func (as *AudioSystem) process3DAudio(deltaTime float32) {
 	}
 }
 
+// process2DFallback applies plain 2D volume to all sources when there is no listener,
+// so menu and ambient sounds still play in scenes without an AudioListener. Whatever the
+// last listener left on a sound - pan, Doppler pitch, reverb send - is reset with it.
+func (as *AudioSystem) process2DFallback() {
+	for i := range as.activeAudioSources {
+		as.applyFlatMix(&as.activeAudioSources[i])
+	}
+}
+
+// applyFlatMix plays a source as plain 2D audio: its own volume scaled by the master and
+// group volumes, centered, at its own pitch and dry
+func (as *AudioSystem) applyFlatMix(source *ActiveAudioSource) {
+	source.Volume = source.AudioSource.Volume * as.masterVolume * as.sourceGroupVolume(source.EntityID)
+	source.ReverbWet = 0.0
+	source.AudioSource.SetVolume(source.Volume)
+	source.AudioSource.SetPitch(source.AudioSource.Pitch)
+	rl.SetSoundPan(source.AudioSource.Sound, raylibPan(0))
+}
+
 // process3DAudioSource processes 3D audio for a single source
 func (as *AudioSystem) process3DAudioSource(source *ActiveAudioSource, listenerTransform *components.TransformComponent, listener *components.AudioListenerComponent, deltaTime float32) {
 	if !source.AudioSource.Is3D || source.AudioSource.SpatialBlend == 0.0 {
-		// 2D audio - just apply volume
-		source.AudioSource.SetVolume(source.AudioSource.Volume * as.masterVolume)
+		// 2D audio - just apply volume, with no room reverb
+		as.applyFlatMix(source)
 		return
 	}
 
 	// Calculate 3D audio parameters
 	distance := source.Distance
-	direction := core.Vector3Normalize(core.Vector3Subtract(source.Transform.Position, listenerTransform.Position))
+	direction := core.Vector3Normalize(core.Vector3Subtract(source.Position, as.listenerPosition))
 
 	// Calculate volume based on distance
-	volume := source.AudioSource.GetEffectiveVolume(distance) * as.masterVolume
+	volume := as.attenuatedVolume(source.AudioSource, distance) * as.masterVolume * as.sourceGroupVolume(source.EntityID)
+
+	// Attenuate directional sources when the listener is outside their cone
+	if cone, ok := as.sourceCones[source.EntityID]; ok {
+		forward := forwardFromRotation(source.Transform.Rotation)
+		toListener := core.Vector3Scale(direction, -1)
+		volume *= calculateConeGain(cone, forward, toListener)
+	}
+
+	// Muffle occludable sources with geometry between them and the listener
+	if as.occludable[source.EntityID] && as.isOccluded(source, as.listenerPosition) {
+		volume *= as.occlusionGain
+	}
 
 	// Calculate Doppler effect if enabled
 	if as.dopplerEnabled && listener != nil && source.AudioSource.DopplerFactor > 0.0 {
@@ -366,22 +611,207 @@ func (as *AudioSystem) process3DAudioSource(source *ActiveAudioSource, listenerT
 		source.AudioSource.SetPitch(source.AudioSource.Pitch * pitch)
 	}
 
-	// Calculate stereo panning based on position
+	// Calculate stereo panning based on position, and muffle sources behind the listener
 	if listener != nil {
-		_ = as.calculateStereoPan(direction, listenerTransform)
-		// Apply pan (raylib doesn't have direct pan control, so this would need custom implementation)
-		// For now, we'll just adjust volume
+		pan := as.calculateStereoPan(direction, listenerTransform)
+		rl.SetSoundPan(source.AudioSource.Sound, raylibPan(pan))
+
+		volume *= calculateRearGain(direction, forwardFromRotation(listenerTransform.Rotation))
 	}
 
+	// Send to the reverb of the zone the listener is in, scaled like the rest of the spatial mix
+	source.ReverbWet = as.reverbInfluence() * source.AudioSource.SpatialBlend
+
 	// Apply final volume
+	source.Volume = volume
 	source.AudioSource.SetVolume(volume)
 
 	// Update velocity for next frame (for Doppler)
 	if deltaTime > 0 {
-		displacement := core.Vector3Subtract(source.Transform.Position, source.LastPosition)
+		displacement := core.Vector3Subtract(source.Position, source.LastPosition)
 		source.Velocity = core.Vector3Scale(displacement, 1.0/deltaTime)
-		source.LastPosition = source.Transform.Position
+		source.LastPosition = source.Position
+	}
+}
+
+// attenuatedVolume returns the source's volume at distance under the configured distance model.
+// The component's own volume at distance 0 carries the fades without any falloff.
+func (as *AudioSystem) attenuatedVolume(audioSource *components.AudioSourceComponent, distance float32) float32 {
+	gain := distanceGain(as.distanceModel, distance, audioSource.MinDistance, audioSource.MaxDistance, as.rolloffFactor)
+	return audioSource.GetEffectiveVolume(0) * gain
+}
+
+// distanceGain returns the volume multiplier for a source at distance under model,
+// using the OpenAL curves with minDistance as the reference distance
+func distanceGain(model DistanceModel, distance, minDistance, maxDistance, rolloff float32) float32 {
+	var gain float32
+	switch model {
+	case InverseDistance:
+		gain = inverseDistanceGain(distance, minDistance, rolloff)
+	case InverseDistanceClamped:
+		gain = inverseDistanceGain(clampDistance(distance, minDistance, maxDistance), minDistance, rolloff)
+	case LinearDistance:
+		gain = linearDistanceGain(float32(math.Min(float64(distance), float64(maxDistance))), minDistance, maxDistance, rolloff)
+	case LinearDistanceClamped:
+		gain = linearDistanceGain(clampDistance(distance, minDistance, maxDistance), minDistance, maxDistance, rolloff)
+	case ExponentDistance:
+		gain = exponentDistanceGain(distance, minDistance, rolloff)
+	case ExponentDistanceClamped:
+		gain = exponentDistanceGain(clampDistance(distance, minDistance, maxDistance), minDistance, rolloff)
+	default:
+		return 1.0
+	}
+
+	if gain < 0.0 {
+		return 0.0
+	} else if gain > 1.0 {
+		return 1.0
+	}
+	return gain
+}
+
+// clampDistance keeps distance between the reference and maximum distances
+func clampDistance(distance, minDistance, maxDistance float32) float32 {
+	if distance < minDistance {
+		return minDistance
+	} else if maxDistance > minDistance && distance > maxDistance {
+		return maxDistance
+	}
+	return distance
+}
+
+// inverseDistanceGain falls off as minDistance / distance at a rolloff of 1
+func inverseDistanceGain(distance, minDistance, rolloff float32) float32 {
+	denominator := minDistance + rolloff*(distance-minDistance)
+	if denominator <= 0.0 {
+		return 1.0
+	}
+	return minDistance / denominator
+}
+
+// linearDistanceGain falls off in a straight line to silence at maxDistance at a rolloff of 1
+func linearDistanceGain(distance, minDistance, maxDistance, rolloff float32) float32 {
+	if maxDistance <= minDistance {
+		if distance <= minDistance {
+			return 1.0
+		}
+		return 0.0
+	}
+	return 1.0 - rolloff*(distance-minDistance)/(maxDistance-minDistance)
+}
+
+// exponentDistanceGain falls off as (distance / minDistance) to the power of -rolloff
+func exponentDistanceGain(distance, minDistance, rolloff float32) float32 {
+	if minDistance <= 0.0 || distance <= 0.0 {
+		return 1.0
+	}
+	return float32(math.Pow(float64(distance/minDistance), float64(-rolloff)))
+}
+
+// collectOccluders boxes every mesh entity from its transform: a unit cube scaled by Scale
+func (as *AudioSystem) collectOccluders() {
+	meshEntities := as.world.GetEntitiesWithComponents(components.MeshRendererComponentType, components.TransformComponentType)
+
+	for _, entityID := range meshEntities {
+		transformComp, _ := as.world.GetComponent(entityID, components.TransformComponentType)
+		if transform, ok := transformComp.(*components.TransformComponent); ok {
+			half := core.Vector3Scale(transform.Scale, 0.5)
+			center := as.worldPosition(entityID)
+			as.occluders = append(as.occluders, Occluder{
+				EntityID: entityID,
+				Box: rl.BoundingBox{
+					Min: core.Vector3Subtract(center, half),
+					Max: rl.Vector3Add(center, half),
+				},
+			})
+		}
+	}
+}
+
+// isOccluded casts a ray from the listener to the source and reports whether any occluder
+// other than the source and listener themselves is in the way
+func (as *AudioSystem) isOccluded(source *ActiveAudioSource, listenerPosition rl.Vector3) bool {
+	if source.Distance <= 0 {
+		return false
+	}
+	ray := rl.Ray{
+		Position:  listenerPosition,
+		Direction: core.Vector3Normalize(core.Vector3Subtract(source.Position, listenerPosition)),
+	}
+
+	for _, occluder := range as.occluders {
+		if occluder.EntityID == source.EntityID || occluder.EntityID == as.listenerEntity {
+			continue
+		}
+		// A listener standing inside a box hears what is in there with it
+		if boxContains(occluder.Box, listenerPosition) {
+			continue
+		}
+		hit := rl.GetRayCollisionBox(ray, occluder.Box)
+		if hit.Hit && hit.Distance < source.Distance {
+			return true
+		}
+	}
+	return false
+}
+
+// boxContains reports whether point lies inside box
+func boxContains(box rl.BoundingBox, point rl.Vector3) bool {
+	return point.X >= box.Min.X && point.X <= box.Max.X &&
+		point.Y >= box.Min.Y && point.Y <= box.Max.Y &&
+		point.Z >= box.Min.Z && point.Z <= box.Max.Z
+}
+
+// forwardFromRotation returns the unit forward (+Z) vector for Euler rotation in degrees
+func forwardFromRotation(rotation rl.Vector3) rl.Vector3 {
+	pitch := float64(rotation.X) * math.Pi / 180.0
+	yaw := float64(rotation.Y) * math.Pi / 180.0
+
+	return rl.Vector3{
+		X: float32(math.Cos(pitch) * math.Sin(yaw)),
+		Y: float32(-math.Sin(pitch)),
+		Z: float32(math.Cos(pitch) * math.Cos(yaw)),
+	}
+}
+
+// rightFromRotation returns the unit right (+X) vector for Euler rotation in degrees.
+// Only yaw turns it; roll is ignored, so the ears stay level with the horizon.
+func rightFromRotation(rotation rl.Vector3) rl.Vector3 {
+	yaw := float64(rotation.Y) * math.Pi / 180.0
+
+	return rl.Vector3{
+		X: float32(math.Cos(yaw)),
+		Y: 0,
+		Z: float32(-math.Sin(yaw)),
+	}
+}
+
+// calculateConeGain returns the volume multiplier for a listener in direction toListener
+func calculateConeGain(cone AudioCone, forward rl.Vector3, toListener rl.Vector3) float32 {
+	if cone.InnerAngle >= 360 {
+		return 1.0
 	}
+
+	cosAngle := float64(rl.Vector3DotProduct(core.Vector3Normalize(forward), core.Vector3Normalize(toListener)))
+	if cosAngle > 1.0 {
+		cosAngle = 1.0
+	} else if cosAngle < -1.0 {
+		cosAngle = -1.0
+	}
+	angle := float32(math.Acos(cosAngle) * 180.0 / math.Pi)
+
+	halfInner := cone.InnerAngle / 2
+	halfOuter := cone.OuterAngle / 2
+
+	if angle <= halfInner {
+		return 1.0
+	} else if angle >= halfOuter {
+		return cone.OuterGain
+	}
+
+	// Linear transition between the inner and outer cone
+	t := (angle - halfInner) / (halfOuter - halfInner)
+	return 1.0 + (cone.OuterGain-1.0)*t
 }
 
 // calculateDopplerPitch calculates the Doppler effect pitch multiplier
@@ -391,8 +821,8 @@ func (as *AudioSystem) calculateDopplerPitch(source *ActiveAudioSource, listener
 	}
 
 	// Calculate relative velocity
-	relativeVelocity := core.Vector3Subtract(source.Velocity, listener.Velocity)
-	direction := core.Vector3Normalize(core.Vector3Subtract(source.Transform.Position, listenerTransform.Position))
+	relativeVelocity := core.Vector3Subtract(source.Velocity, as.listenerVelocity(listener))
+	direction := core.Vector3Normalize(core.Vector3Subtract(source.Position, as.listenerPosition))
 
 	// Project relative velocity onto the line between source and listener
 	velocityAlongLine := rl.Vector3DotProduct(relativeVelocity, direction)
@@ -414,10 +844,18 @@ func (as *AudioSystem) calculateDopplerPitch(source *ActiveAudioSource, listener
 	return dopplerShift
 }
 
+// listenerVelocity returns the velocity Doppler uses for the active listener: the one set with
+// SetListenerVelocity in explicit mode, otherwise the one derived from its movement
+func (as *AudioSystem) listenerVelocity(listener *components.AudioListenerComponent) rl.Vector3 {
+	if setting, ok := as.listenerVelocities[as.listenerEntity]; ok && setting.Mode == ListenerVelocityExplicit {
+		return setting.Velocity
+	}
+	return listener.Velocity
+}
+
 // calculateStereoPan calculates stereo panning based on audio source direction
 func (as *AudioSystem) calculateStereoPan(direction rl.Vector3, listenerTransform *components.TransformComponent) float32 {
-	// Get listener's right vector (simplified - assumes Y is up)
-	listenerRight := core.Vector3Normalize(rl.Vector3{X: 1, Y: 0, Z: 0}) // Simplified
+	listenerRight := rightFromRotation(listenerTransform.Rotation)
 
 	// Calculate dot product to determine left/right position
 	pan := rl.Vector3DotProduct(direction, listenerRight)
@@ -432,6 +870,33 @@ func (as *AudioSystem) calculateStereoPan(direction rl.Vector3, listenerTransfor
 	return pan
 }
 
+// raylibPan converts a pan in [-1, 1] (negative is left) to raylib's pan value,
+// which is the left channel level: 1.0 is fully left, 0.5 centered, 0.0 fully right
+func raylibPan(pan float32) float32 {
+	if pan < -1.0 {
+		pan = -1.0
+	} else if pan > 1.0 {
+		pan = 1.0
+	}
+	return 0.5 - pan*0.5
+}
+
+// rearGain is the volume multiplier for a source directly behind the listener
+const rearGain = 0.7
+
+// calculateRearGain returns the volume multiplier for a source in direction from a
+// listener facing forward: 1.0 anywhere in front, falling to rearGain directly behind
+func calculateRearGain(direction rl.Vector3, forward rl.Vector3) float32 {
+	behind := -rl.Vector3DotProduct(direction, core.Vector3Normalize(forward))
+	if behind <= 0.0 {
+		return 1.0
+	}
+	if behind > 1.0 {
+		behind = 1.0
+	}
+	return 1.0 - (1.0-rearGain)*behind
+}
+
 // updateSoundPlayback updates sound playback state
 func (as *AudioSystem) updateSoundPlayback(deltaTime float32) {
 	for _, source := range as.activeAudioSources {
@@ -445,17 +910,253 @@ func (as *AudioSystem) updateSoundPlayback(deltaTime float32) {
 
 		// Check if sound has finished playing (for non-looping sounds)
 		if audioSource.IsPlaying && !audioSource.IsLooping {
-			// In a full implementation, you'd check if the sound has actually finished
-			// raylib doesn't provide direct access to this, so we'd need to track it manually
-			if audioSource.AudioClipLength > 0 && audioSource.CurrentTime >= audioSource.AudioClipLength {
+			if audioSource.AudioClipLength > 0 {
+				if audioSource.CurrentTime >= audioSource.AudioClipLength {
+					audioSource.Stop()
+				}
+			} else if !audioSource.IsPaused && audioSource.CurrentTime > 0 && !rl.IsSoundPlaying(audioSource.Sound) {
+				// Unknown length - ask raylib whether the sound is still going
 				audioSource.Stop()
 			}
 		}
 	}
 }
 
+// SoundLength returns the duration of a loaded sound in seconds, or 0 if it can't be told
+func SoundLength(sound rl.Sound) float32 {
+	if sound.Stream.SampleRate == 0 {
+		return 0.0
+	}
+	return float32(sound.FrameCount) / float32(sound.Stream.SampleRate)
+}
+
+// WaveLength returns the duration of wave data in seconds, or 0 if it can't be told
+func WaveLength(wave rl.Wave) float32 {
+	if wave.SampleRate == 0 {
+		return 0.0
+	}
+	return float32(wave.FrameCount) / float32(wave.SampleRate)
+}
+
+// updateMusic refills the buffers of every playing music stream, which raylib needs every frame
+func (as *AudioSystem) updateMusic(deltaTime float32) {
+	for _, track := range as.music {
+		if !track.playing || track.paused {
+			continue
+		}
+		if track.fade != nil {
+			volume, done := track.fade.Advance(deltaTime)
+			track.Volume = volume
+			if done {
+				track.fade = nil
+			}
+		}
+		rl.UpdateMusicStream(track.Music)
+		rl.SetMusicVolume(track.Music, track.Volume*as.masterVolume*as.GetGroupVolume(track.Group))
+
+		// A track that isn't looping stops by itself at the end
+		if !rl.IsMusicStreamPlaying(track.Music) {
+			track.playing = false
+		}
+	}
+}
+
+// Music methods
+
+// LoadMusic opens a music file for streaming under name, replacing any track already loaded
+// under that name. Tracks loop by default.
+func (as *AudioSystem) LoadMusic(name string, fileName string) error {
+	music := rl.LoadMusicStream(fileName)
+	if !rl.IsMusicReady(music) {
+		return fmt.Errorf("failed to load music stream %q", fileName)
+	}
+	music.Looping = true
+
+	as.UnloadMusic(name)
+	as.music[name] = &MusicTrack{Music: music, Volume: 1.0, Group: AudioGroupMusic}
+	return nil
+}
+
+// UnloadMusic stops and frees the named track
+func (as *AudioSystem) UnloadMusic(name string) {
+	track, ok := as.music[name]
+	if !ok {
+		return
+	}
+	rl.StopMusicStream(track.Music)
+	rl.UnloadMusicStream(track.Music)
+	track.playing = false
+	track.paused = false // Keeps ResumeAll away from the freed stream
+	delete(as.music, name)
+}
+
+// PlayMusic starts the named track from the beginning, or resumes it if it is paused
+func (as *AudioSystem) PlayMusic(name string) {
+	track, ok := as.music[name]
+	if !ok {
+		return
+	}
+	if track.paused {
+		rl.ResumeMusicStream(track.Music)
+		track.paused = false
+		return
+	}
+	rl.SetMusicVolume(track.Music, track.Volume*as.masterVolume*as.GetGroupVolume(track.Group))
+	rl.PlayMusicStream(track.Music)
+	track.playing = true
+}
+
+// PauseMusic pauses the named track where it is
+func (as *AudioSystem) PauseMusic(name string) {
+	track, ok := as.music[name]
+	if !ok || !track.playing || track.paused {
+		return
+	}
+	rl.PauseMusicStream(track.Music)
+	track.paused = true
+}
+
+// StopMusic stops the named track and rewinds it
+func (as *AudioSystem) StopMusic(name string) {
+	track, ok := as.music[name]
+	if !ok {
+		return
+	}
+	rl.StopMusicStream(track.Music)
+	track.playing = false
+	track.paused = false
+}
+
+// SetMusicVolume sets the named track's volume, before the master volume
+func (as *AudioSystem) SetMusicVolume(name string, volume float32) {
+	track, ok := as.music[name]
+	if !ok {
+		return
+	}
+	if volume < 0.0 {
+		volume = 0.0
+	} else if volume > 1.0 {
+		volume = 1.0
+	}
+	track.Volume = volume
+}
+
+// FadeMusicTo fades the named track's volume to targetVolume over duration seconds.
+// Fading one track down while another fades up cross-fades them.
+func (as *AudioSystem) FadeMusicTo(name string, targetVolume float32, duration float32, curve FadeCurve) {
+	track, ok := as.music[name]
+	if !ok {
+		return
+	}
+	track.fade = &Fade{From: track.Volume, To: clampVolume(targetVolume), Duration: duration, Curve: curve}
+}
+
+// SetMusicLooping sets whether the named track starts over when it reaches the end
+func (as *AudioSystem) SetMusicLooping(name string, looping bool) {
+	if track, ok := as.music[name]; ok {
+		track.Music.Looping = looping
+	}
+}
+
+// IsMusicPlaying reports whether the named track is playing and not paused
+func (as *AudioSystem) IsMusicPlaying(name string) bool {
+	track, ok := as.music[name]
+	return ok && track.playing && !track.paused
+}
+
+// cleanupOneShots sets aside the entities of one-shot sounds that have finished playing,
+// so PlayOneShot can reuse them instead of growing the world with every sound. The World
+// can't destroy entities, so it keeps as many one-shot entities as ever played at once.
+// Clips of unknown length are polled with rl.IsSoundPlaying once they have started.
+func (as *AudioSystem) cleanupOneShots(deltaTime float32) {
+	remaining := as.oneShots[:0]
+	for _, shot := range as.oneShots {
+		shot.Elapsed += deltaTime
+
+		var finished bool
+		if shot.Length > 0 {
+			finished = shot.Elapsed >= shot.Length
+		} else {
+			finished = !shot.AudioSource.PlayOnAwake && !rl.IsSoundPlaying(shot.AudioSource.Sound)
+		}
+		if !finished {
+			remaining = append(remaining, shot)
+			continue
+		}
+
+		shot.AudioSource.Stop()
+		delete(as.sourceCones, shot.EntityID)
+		delete(as.sourceGroups, shot.EntityID)
+		delete(as.sourceFades, shot.EntityID)
+		delete(as.occludable, shot.EntityID)
+		as.idleOneShots = append(as.idleOneShots, shot)
+		as.idleOneShotIDs[shot.EntityID] = true
+	}
+	as.oneShots = remaining
+}
+
+// GetOneShotCount returns the number of one-shot sounds still waiting to finish
+func (as *AudioSystem) GetOneShotCount() int {
+	return len(as.oneShots)
+}
+
 // Configuration methods
 
+// PauseAll pauses every playing sound and music stream, for example behind a pause menu.
+// Only what PauseAll paused is resumed by ResumeAll; sources paused on their own stay paused.
+func (as *AudioSystem) PauseAll() {
+	if as.paused {
+		return
+	}
+	as.paused = true
+
+	for _, source := range as.activeAudioSources {
+		audioSource := source.AudioSource
+		if audioSource.IsPlaying && !audioSource.IsPaused {
+			rl.PauseSound(audioSource.Sound)
+			audioSource.IsPaused = true
+			as.pausedSources = append(as.pausedSources, audioSource)
+		}
+	}
+
+	for _, track := range as.music {
+		if track.playing && !track.paused {
+			rl.PauseMusicStream(track.Music)
+			track.paused = true
+			as.pausedMusic = append(as.pausedMusic, track)
+		}
+	}
+}
+
+// ResumeAll resumes the sounds and music paused by PauseAll
+func (as *AudioSystem) ResumeAll() {
+	if !as.paused {
+		return
+	}
+	as.paused = false
+
+	for _, audioSource := range as.pausedSources {
+		if audioSource.IsPaused {
+			rl.ResumeSound(audioSource.Sound)
+			audioSource.IsPaused = false
+		}
+	}
+	as.pausedSources = as.pausedSources[:0]
+
+	for _, track := range as.pausedMusic {
+		if track.paused {
+			rl.ResumeMusicStream(track.Music)
+			track.paused = false
+		}
+	}
+	as.pausedMusic = as.pausedMusic[:0]
+}
+
+// IsPaused reports whether PauseAll is in effect
+func (as *AudioSystem) IsPaused() bool {
+	return as.paused
+}
+
 // SetMasterVolume sets the master volume for all audio
 func (as *AudioSystem) SetMasterVolume(volume float32) {
 	if volume < 0.0 {
@@ -482,11 +1183,142 @@ func (as *AudioSystem) SetMaxAudioSources(max int) {
 	as.maxAudioSources = max
 }
 
+// SetActiveListener makes entityID the listener used for 3D audio. Pass 0 to go back to
+// the first enabled listener found.
+func (as *AudioSystem) SetActiveListener(entityID core.EntityID) {
+	as.activeListener = entityID
+}
+
+// GetActiveListener returns the listener used for 3D audio on the last update, or 0 if none
+func (as *AudioSystem) GetActiveListener() core.EntityID {
+	return as.listenerEntity
+}
+
 // SetDistanceModel sets the distance model for 3D audio
 func (as *AudioSystem) SetDistanceModel(model DistanceModel) {
 	as.distanceModel = model
 }
 
+// SetRolloffFactor sets how steeply the distance model falls off; 1.0 is the standard curve
+func (as *AudioSystem) SetRolloffFactor(rolloff float32) {
+	if rolloff < 0.0 {
+		rolloff = 0.0
+	}
+	as.rolloffFactor = rolloff
+}
+
+// SetGroupVolume sets the volume multiplier for a named audio group
+func (as *AudioSystem) SetGroupVolume(name string, volume float32) {
+	if volume < 0.0 {
+		volume = 0.0
+	} else if volume > 1.0 {
+		volume = 1.0
+	}
+	as.groupVolumes[name] = volume
+}
+
+// GetGroupVolume returns a group's volume multiplier; groups never set play at full volume
+func (as *AudioSystem) GetGroupVolume(name string) float32 {
+	if volume, ok := as.groupVolumes[name]; ok {
+		return volume
+	}
+	return 1.0
+}
+
+// SetSourceGroup assigns an audio source entity to a named group
+func (as *AudioSystem) SetSourceGroup(entityID core.EntityID, group string) {
+	as.sourceGroups[entityID] = group
+}
+
+// sourceGroupVolume returns the volume multiplier of the group a source entity is in
+func (as *AudioSystem) sourceGroupVolume(entityID core.EntityID) float32 {
+	group, ok := as.sourceGroups[entityID]
+	if !ok {
+		group = AudioGroupSFX
+	}
+	return as.GetGroupVolume(group)
+}
+
+// FadeTo fades an audio source entity's volume to targetVolume over duration seconds,
+// replacing any fade already running on it
+func (as *AudioSystem) FadeTo(entityID core.EntityID, targetVolume float32, duration float32, curve FadeCurve) {
+	audioComp, exists := as.world.GetComponent(entityID, components.AudioSourceComponentType)
+	if !exists {
+		return
+	}
+	audioSource, ok := audioComp.(*components.AudioSourceComponent)
+	if !ok {
+		return
+	}
+	as.sourceFades[entityID] = &Fade{From: audioSource.Volume, To: clampVolume(targetVolume), Duration: duration, Curve: curve}
+}
+
+// clampVolume keeps a volume within [0, 1]
+func clampVolume(volume float32) float32 {
+	if volume < 0.0 {
+		return 0.0
+	} else if volume > 1.0 {
+		return 1.0
+	}
+	return volume
+}
+
+// SetSourceOccludable opts an audio source entity in or out of occlusion by mesh geometry
+func (as *AudioSystem) SetSourceOccludable(entityID core.EntityID, occludable bool) {
+	if occludable {
+		as.occludable[entityID] = true
+	} else {
+		delete(as.occludable, entityID)
+	}
+}
+
+// SetOcclusionGain sets the volume multiplier for an occluded source
+func (as *AudioSystem) SetOcclusionGain(gain float32) {
+	as.occlusionGain = clampVolume(gain)
+}
+
+// SetSourceCone makes an audio source entity directional
+func (as *AudioSystem) SetSourceCone(entityID core.EntityID, cone AudioCone) {
+	if cone.OuterAngle < cone.InnerAngle {
+		cone.OuterAngle = cone.InnerAngle
+	}
+	if cone.OuterGain < 0.0 {
+		cone.OuterGain = 0.0
+	} else if cone.OuterGain > 1.0 {
+		cone.OuterGain = 1.0
+	}
+	as.sourceCones[entityID] = cone
+}
+
+// ClearSourceCone makes an audio source entity omnidirectional again
+func (as *AudioSystem) ClearSourceCone(entityID core.EntityID) {
+	delete(as.sourceCones, entityID)
+}
+
+// SetListenerVelocity gives a listener entity a fixed velocity for Doppler and switches it to
+// ListenerVelocityExplicit mode, for listeners whose movement doesn't reflect their speed,
+// such as one riding a vehicle that is moved in steps
+func (as *AudioSystem) SetListenerVelocity(entityID core.EntityID, velocity rl.Vector3) {
+	as.listenerVelocities[entityID] = ListenerVelocity{Mode: ListenerVelocityExplicit, Velocity: velocity}
+}
+
+// SetListenerVelocityMode chooses whether a listener entity's velocity is derived from its
+// movement or taken from SetListenerVelocity. A velocity set earlier is kept across modes.
+func (as *AudioSystem) SetListenerVelocityMode(entityID core.EntityID, mode ListenerVelocityMode) {
+	setting := as.listenerVelocities[entityID]
+	setting.Mode = mode
+	if setting == (ListenerVelocity{}) {
+		delete(as.listenerVelocities, entityID)
+		return
+	}
+	as.listenerVelocities[entityID] = setting
+}
+
+// GetListenerVelocityMode returns where a listener entity's velocity comes from
+func (as *AudioSystem) GetListenerVelocityMode(entityID core.EntityID) ListenerVelocityMode {
+	return as.listenerVelocities[entityID].Mode
+}
+
 // SetDopplerEnabled enables or disables Doppler effect
 func (as *AudioSystem) SetDopplerEnabled(enabled bool) {
 	as.dopplerEnabled = enabled
@@ -504,19 +1336,39 @@ func (as *AudioSystem) GetAudioDeviceInfo() (sampleRate, bufferSize, channels in
 
 // PlayOneShot plays a sound effect once at a specific position
 func (as *AudioSystem) PlayOneShot(sound rl.Sound, position rl.Vector3, volume float32) {
-	// Create a temporary entity for the one-shot sound
-	entity := as.world.CreateEntity()
-
-	// Add transform component
-	transform := components.NewTransformComponentAt(position)
-	entity.AddComponent(transform)
-
-	// Add audio source component
 	audioSource := components.NewAudioSourceComponent(sound)
 	audioSource.Volume = volume
 	audioSource.PlayOnAwake = true
-	entity.AddComponent(audioSource)
+	audioSource.AudioClipLength = SoundLength(sound)
+
+	// Reuse the entity of a one-shot that has finished, if there is one
+	if n := len(as.idleOneShots); n > 0 {
+		shot := as.idleOneShots[n-1]
+		as.idleOneShots = as.idleOneShots[:n-1]
+		delete(as.idleOneShotIDs, shot.EntityID)
+		*shot.Transform = *components.NewTransformComponentAt(position)
+		*shot.AudioSource = *audioSource
+		as.oneShots = append(as.oneShots, OneShot{
+			EntityID:    shot.EntityID,
+			Transform:   shot.Transform,
+			AudioSource: shot.AudioSource,
+			Length:      audioSource.AudioClipLength,
+		})
+		return
+	}
 
-	// The entity will be cleaned up when the sound finishes playing
-	// In a full implementation, you'd want a cleanup system for temporary entities
+	// Create an entity for the one-shot sound
+	entity := as.world.CreateEntity()
+	transform := components.NewTransformComponentAt(position)
+	entity.AddComponent(transform)
+	entity.AddComponent(audioSource)
+	entityID, _ := FindEntity(as.world, components.AudioSourceComponentType, audioSource)
+
+	// Set aside by cleanupOneShots when the sound finishes playing
+	as.oneShots = append(as.oneShots, OneShot{
+		EntityID:    entityID,
+		Transform:   transform,
+		AudioSource: audioSource,
+		Length:      audioSource.AudioClipLength,
+	})
 }
\ No newline at end of file
@@ -0,0 +1,497 @@
+package systems
+
+import (
+	"testing"
+
+	"gameengine/components"
+	"gameengine/core"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// newTestAudioSystem returns an audio system that updates without opening an audio device
+func newTestAudioSystem(world *ecs.World) *AudioSystem {
+	as := NewAudioSystem(world)
+	as.initialized, as.audioDevice = true, true
+	return as
+}
+
+func TestOneShotsReuseFinishedEntities(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	fire := func() {
+		// Half the clips have a known length, half are polled until they stop
+		for i := 0; i < 100; i++ {
+			as.PlayOneShot(rl.Sound{}, rl.Vector3{X: float32(i)}, 1)
+			if i%2 == 0 {
+				as.oneShots[len(as.oneShots)-1].Length = 0.5
+			}
+		}
+	}
+
+	fire()
+	for _, shot := range as.oneShots {
+		if shot.EntityID == 0 {
+			t.Fatal("one-shot recorded without its entity")
+		}
+	}
+	for frame := 0; frame < 40; frame++ {
+		as.Update(1.0 / 60)
+	}
+	if as.GetOneShotCount() != 0 {
+		t.Fatalf("%d one-shots still tracked after they finished", as.GetOneShotCount())
+	}
+	if len(as.activeAudioSources) != 0 {
+		t.Errorf("%d finished one-shots still in the mix", len(as.activeAudioSources))
+	}
+
+	// A second volley plays on the entities the first one left behind
+	fire()
+	if n := len(world.GetEntitiesWithComponent(components.AudioSourceComponentType)); n != 100 {
+		t.Errorf("%d one-shot entities after two volleys of 100, want 100", n)
+	}
+	if n := len(world.GetEntitiesWithComponent(components.TransformComponentType)); n != 100 {
+		t.Errorf("%d transforms after two volleys of 100, want 100", n)
+	}
+	for _, shot := range as.oneShots {
+		if !shot.AudioSource.PlayOnAwake || shot.AudioSource.IsPlaying {
+			t.Fatalf("reused one-shot %d wasn't reset to play", shot.EntityID)
+		}
+	}
+}
+
+// newAudioEntity adds an entity with a transform and an audio source at x and returns its ID
+func newAudioEntity(t testing.TB, world *ecs.World, x float32) core.EntityID {
+	t.Helper()
+	entity := world.CreateEntity()
+	entity.AddComponent(components.NewTransformComponentAt(rl.Vector3{X: x}))
+	source := components.NewAudioSourceComponent(rl.Sound{})
+	entity.AddComponent(source)
+	entityID, ok := FindEntity(world, components.AudioSourceComponentType, source)
+	if !ok {
+		t.Fatal("new entity not found by its audio source")
+	}
+	return entityID
+}
+
+func TestStereoPanFollowsSide(t *testing.T) {
+	as := newTestAudioSystem(ecs.NewWorld())
+	listener := components.NewTransformComponentAt(rl.Vector3{})
+
+	if pan := as.calculateStereoPan(rl.Vector3{X: 1}, listener); pan <= 0 {
+		t.Errorf("source to the right panned %v, want positive", pan)
+	}
+	if pan := as.calculateStereoPan(rl.Vector3{X: -1}, listener); pan >= 0 {
+		t.Errorf("source to the left panned %v, want negative", pan)
+	}
+	if left, right := raylibPan(-1), raylibPan(1); left != 1 || right != 0 || raylibPan(0) != 0.5 {
+		t.Errorf("raylib pan left %v, right %v, centre %v", left, right, raylibPan(0))
+	}
+
+	forward := forwardFromRotation(listener.Rotation)
+	if gain := calculateRearGain(rl.Vector3{Z: -1}, forward); gain != rearGain {
+		t.Errorf("source behind the listener gained %v, want %v", gain, rearGain)
+	}
+	if gain := calculateRearGain(rl.Vector3{Z: 1}, forward); gain != 1 {
+		t.Errorf("source in front gained %v, want 1", gain)
+	}
+}
+
+func TestPanFollowsListenerYaw(t *testing.T) {
+	as := newTestAudioSystem(ecs.NewWorld())
+	listener := components.NewTransformComponentAt(rl.Vector3{})
+	listener.SetRotation(rl.Vector3{Y: 90})
+	const epsilon = 1e-5
+
+	// Turned 90 degrees, the source that was on the right is now straight ahead
+	right := rl.Vector3{X: 1}
+	if pan := as.calculateStereoPan(right, listener); pan > epsilon || pan < -epsilon {
+		t.Errorf("source ahead panned %v, want 0", pan)
+	}
+	if ahead := rl.Vector3DotProduct(right, forwardFromRotation(listener.Rotation)); ahead < 1-epsilon {
+		t.Errorf("source at +X is %v along the listener's forward, want 1", ahead)
+	}
+	if pan := as.calculateStereoPan(rl.Vector3{Z: 1}, listener); pan > -1+epsilon {
+		t.Errorf("source at +Z panned %v, want fully left", pan)
+	}
+}
+
+func TestDistanceModels(t *testing.T) {
+	const minDistance, maxDistance = 10, 100
+	tests := []struct {
+		model         DistanceModel
+		min, mid, max float32 // Gains at 10, 55 and 100
+	}{
+		{InverseDistance, 1, 10.0 / 55, 0.1},
+		{InverseDistanceClamped, 1, 10.0 / 55, 0.1},
+		{LinearDistance, 1, 0.5, 0},
+		{LinearDistanceClamped, 1, 0.5, 0},
+		{ExponentDistance, 1, 10.0 / 55, 0.1},
+		{ExponentDistanceClamped, 1, 10.0 / 55, 0.1},
+		{NoDistanceAttenuation, 1, 1, 1},
+	}
+	for _, test := range tests {
+		for _, sample := range []struct{ distance, want float32 }{
+			{minDistance, test.min},
+			{(minDistance + maxDistance) / 2, test.mid},
+			{maxDistance, test.max},
+		} {
+			got := distanceGain(test.model, sample.distance, minDistance, maxDistance, 1)
+			if diff := got - sample.want; diff > 1e-5 || diff < -1e-5 {
+				t.Errorf("model %d at %v: gain %v, want %v", test.model, sample.distance, got, sample.want)
+			}
+		}
+	}
+}
+
+func TestConeGain(t *testing.T) {
+	// Facing +Z: full volume within 30 degrees, a quarter beyond 60
+	cone := AudioCone{InnerAngle: 60, OuterAngle: 120, OuterGain: 0.25}
+	forward := rl.Vector3{Z: 1}
+	tests := []struct {
+		name       string
+		toListener rl.Vector3
+		want       float32
+	}{
+		{"ahead", rl.Vector3{Z: 1}, 1},
+		{"inside the inner cone", rl.Vector3{X: 0.2, Z: 1}, 1},
+		{"halfway between the cones", rl.Vector3{X: 1, Z: 1}, 0.625},
+		{"outside the outer cone", rl.Vector3{X: 1}, 0.25},
+		{"behind", rl.Vector3{Z: -1}, 0.25},
+	}
+	for _, test := range tests {
+		got := calculateConeGain(cone, forward, test.toListener)
+		if diff := got - test.want; diff > 1e-4 || diff < -1e-4 {
+			t.Errorf("%s: gain %v, want %v", test.name, got, test.want)
+		}
+	}
+	if got := calculateConeGain(OmnidirectionalCone, forward, rl.Vector3{Z: -1}); got != 1 {
+		t.Errorf("omnidirectional source behind the listener at %v, want 1", got)
+	}
+}
+
+// newListenerEntity adds an enabled audio listener at position and returns its ID
+func newListenerEntity(t testing.TB, world *ecs.World, position rl.Vector3) core.EntityID {
+	t.Helper()
+	entity := world.CreateEntity()
+	transform := components.NewTransformComponentAt(position)
+	entity.AddComponent(transform)
+	entity.AddComponent(&components.AudioListenerComponent{Enabled: true, SpeedOfSound: 343, DopplerLevel: 1})
+	entityID, ok := FindEntity(world, components.TransformComponentType, transform)
+	if !ok {
+		t.Fatal("new listener not found by its transform")
+	}
+	return entityID
+}
+
+// newSpatialSource adds a 3D audio source at position and returns it
+func newSpatialSource(t testing.TB, world *ecs.World, position rl.Vector3) *components.AudioSourceComponent {
+	t.Helper()
+	entityID := newAudioEntity(t, world, position.X)
+	component, _ := world.GetComponent(entityID, components.TransformComponentType)
+	component.(*components.TransformComponent).SetPosition(position)
+	component, _ = world.GetComponent(entityID, components.AudioSourceComponentType)
+	source := component.(*components.AudioSourceComponent)
+	source.Is3D = true
+	source.SpatialBlend = 1
+	source.MinDistance = 1
+	source.MaxDistance = 100
+	return source
+}
+
+func TestDopplerUsesExplicitListenerVelocity(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	listenerID := newListenerEntity(t, world, rl.Vector3{})
+	component, _ := world.GetComponent(listenerID, components.AudioListenerComponentType)
+	listener := component.(*components.AudioListenerComponent)
+	component, _ = world.GetComponent(listenerID, components.TransformComponentType)
+	listenerTransform := component.(*components.TransformComponent)
+	as.listenerEntity = listenerID
+
+	// A still source 10 units ahead; the listener never moves, so only an explicit
+	// velocity can make it approach
+	audio := components.NewAudioSourceComponent(rl.Sound{})
+	audio.DopplerFactor = 1
+	source := &ActiveAudioSource{AudioSource: audio, Position: rl.Vector3{Z: 10}}
+	pitch := func() float32 {
+		return as.calculateDopplerPitch(source, listenerTransform, listener, 1.0/60)
+	}
+
+	if got := pitch(); got != 1 {
+		t.Errorf("pitch %v with nothing moving, want 1", got)
+	}
+	as.SetListenerVelocity(listenerID, rl.Vector3{Z: 34.3})
+	if mode := as.GetListenerVelocityMode(listenerID); mode != ListenerVelocityExplicit {
+		t.Errorf("SetListenerVelocity left the listener in mode %d", mode)
+	}
+	if got := pitch(); got < 1.099 || got > 1.101 {
+		t.Errorf("pitch %v approaching at a tenth of the speed of sound, want 1.1", got)
+	}
+
+	// Back to auto, the still listener's own movement rules again
+	as.SetListenerVelocityMode(listenerID, ListenerVelocityAuto)
+	if got := pitch(); got != 1 {
+		t.Errorf("pitch %v in auto mode with a still listener, want 1", got)
+	}
+	as.SetListenerVelocityMode(listenerID, ListenerVelocityExplicit)
+	if got := pitch(); got < 1.099 || got > 1.101 {
+		t.Errorf("pitch %v after switching back to explicit, want the kept velocity's 1.1", got)
+	}
+}
+
+func TestListenerInsideReverbZoneGetsWet(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	listener := newListenerEntity(t, world, rl.Vector3{})
+	newSpatialSource(t, world, rl.Vector3{X: 3})
+	zone := world.CreateEntity()
+	zone.AddComponent(components.NewTransformComponentAt(rl.Vector3{}))
+	zone.AddComponent(&components.AudioReverbZoneComponent{Enabled: true, MinDistance: 5, MaxDistance: 20})
+
+	as.Update(1.0 / 60)
+	if len(as.activeAudioSources) != 1 || as.activeAudioSources[0].ReverbWet <= 0 {
+		t.Fatalf("source in the listener's zone has no reverb send: %+v", as.activeAudioSources)
+	}
+
+	component, _ := world.GetComponent(listener, components.TransformComponentType)
+	component.(*components.TransformComponent).SetPosition(rl.Vector3{X: 50})
+	as.Update(1.0 / 60)
+	if wet := as.activeAudioSources[0].ReverbWet; wet != 0 {
+		t.Errorf("listener outside the zone still sends %v to reverb", wet)
+	}
+}
+
+func TestNoListenerPlaysFlat(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	as.SetGroupVolume(AudioGroupSFX, 0.4)
+	// Far past MaxDistance, so any attenuation would silence it
+	source := newSpatialSource(t, world, rl.Vector3{X: 500})
+	source.Volume = 0.5
+	zone := world.CreateEntity()
+	zone.AddComponent(components.NewTransformComponentAt(rl.Vector3{X: 500}))
+	zone.AddComponent(&components.AudioReverbZoneComponent{Enabled: true, MinDistance: 5, MaxDistance: 20})
+
+	as.Update(1.0 / 60)
+	if len(as.activeAudioSources) != 1 {
+		t.Fatalf("%d sources active without a listener, want 1", len(as.activeAudioSources))
+	}
+	active := as.activeAudioSources[0]
+	if active.Volume < 0.199 || active.Volume > 0.201 {
+		t.Errorf("source without a listener at %v, want 0.5 * 0.4 = 0.2", active.Volume)
+	}
+	if active.ReverbWet != 0 {
+		t.Errorf("source without a listener sends %v to reverb", active.ReverbWet)
+	}
+}
+
+func TestMusicIgnoresTheSourceLimit(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	as.SetMaxAudioSources(1)
+	newAudioEntity(t, world, 0)
+	newAudioEntity(t, world, 1)
+
+	// A track as LoadMusic and PlayMusic leave it, without a stream behind it
+	track := &MusicTrack{Volume: 1, Group: AudioGroupMusic, playing: true}
+	as.music["theme"] = track
+	as.FadeMusicTo("theme", 0, 1, FadeLinear)
+
+	as.Update(0.5)
+	if track.Volume < 0.49 || track.Volume > 0.51 {
+		t.Errorf("music halfway through a fade to silence at %v, want 0.5", track.Volume)
+	}
+	if len(as.activeAudioSources) > 1 {
+		t.Errorf("%d sources active with a limit of 1", len(as.activeAudioSources))
+	}
+}
+
+func TestTiedSourcesSortByEntity(t *testing.T) {
+	as := newTestAudioSystem(ecs.NewWorld())
+	for _, entityID := range []core.EntityID{5, 3, 9, 1} {
+		as.activeAudioSources = append(as.activeAudioSources, ActiveAudioSource{EntityID: entityID, Distance: 10})
+	}
+	as.activeAudioSources = append(as.activeAudioSources, ActiveAudioSource{EntityID: 7, Priority: 1, Distance: 50})
+
+	as.sortAudioSources()
+	var order []core.EntityID
+	for _, source := range as.activeAudioSources {
+		order = append(order, source.EntityID)
+	}
+	want := []core.EntityID{7, 1, 3, 5, 9}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("sorted sources %v, want %v", order, want)
+		}
+	}
+}
+
+func BenchmarkSortAudioSources(b *testing.B) {
+	as := newTestAudioSystem(ecs.NewWorld())
+	sources := make([]ActiveAudioSource, 64)
+	for i := range sources {
+		// A few priorities and many shared distances, as a busy scene produces
+		sources[i] = ActiveAudioSource{
+			EntityID: core.EntityID(64 - i),
+			Priority: i % 3,
+			Distance: float32(i % 8),
+		}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		as.activeAudioSources = append(as.activeAudioSources[:0], sources...)
+		as.sortAudioSources()
+	}
+}
+
+func TestGroupVolumesAreIndependent(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	effect := newAudioEntity(t, world, 0)
+	theme := newAudioEntity(t, world, 1)
+	as.SetSourceGroup(theme, AudioGroupMusic)
+
+	as.SetGroupVolume(AudioGroupSFX, 0.2)
+	if volume := as.sourceGroupVolume(effect); volume != 0.2 {
+		t.Errorf("SFX source at %v, want 0.2", volume)
+	}
+	if volume := as.sourceGroupVolume(theme); volume != 1 {
+		t.Errorf("music source at %v after lowering SFX, want 1", volume)
+	}
+}
+
+func TestClipLengthFromTheSound(t *testing.T) {
+	twoSeconds := rl.Sound{FrameCount: 88200, Stream: rl.AudioStream{SampleRate: 44100}}
+	if length := SoundLength(twoSeconds); length != 2 {
+		t.Errorf("SoundLength = %v, want 2", length)
+	}
+	if length := SoundLength(rl.Sound{}); length != 0 {
+		t.Errorf("SoundLength of an unloaded sound = %v, want 0", length)
+	}
+	if length := WaveLength(rl.Wave{FrameCount: 22050, SampleRate: 44100}); length != 0.5 {
+		t.Errorf("WaveLength = %v, want 0.5", length)
+	}
+
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	entityID := newAudioEntity(t, world, 0)
+	component, _ := world.GetComponent(entityID, components.AudioSourceComponentType)
+	source := component.(*components.AudioSourceComponent)
+	source.Sound = twoSeconds
+	source.AudioClipLength = 0
+
+	as.updateAudioSources(1.0 / 60)
+	if source.AudioClipLength != 2 {
+		t.Errorf("AudioClipLength = %v after an update, want 2 from the sound", source.AudioClipLength)
+	}
+}
+
+func TestDesignatedListenerIsUsed(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	first := newListenerEntity(t, world, rl.Vector3{X: -10})
+	second := newListenerEntity(t, world, rl.Vector3{X: 40})
+	newSpatialSource(t, world, rl.Vector3{})
+
+	as.SetActiveListener(second)
+	as.Update(1.0 / 60)
+	if as.GetActiveListener() != second {
+		t.Fatalf("listener %d used, want the designated %d", as.GetActiveListener(), second)
+	}
+	if distance := as.activeAudioSources[0].Distance; distance != 40 {
+		t.Errorf("source distance %v, want 40 from the designated listener", distance)
+	}
+
+	component, _ := world.GetComponent(second, components.AudioListenerComponentType)
+	component.(*components.AudioListenerComponent).Enabled = false
+	as.Update(1.0 / 60)
+	if as.GetActiveListener() != first {
+		t.Errorf("listener %d used with the designated one disabled, want %d", as.GetActiveListener(), first)
+	}
+	if distance := as.activeAudioSources[0].Distance; distance != 10 {
+		t.Errorf("source distance %v, want 10 from the fallback listener", distance)
+	}
+}
+
+func TestPauseAllHoldsPlayback(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	playing := newSpatialSource(t, world, rl.Vector3{})
+	playing.IsPlaying = true
+	playing.AudioClipLength = 10
+	held := newSpatialSource(t, world, rl.Vector3{X: 1})
+	held.IsPlaying, held.IsPaused = true, true
+	as.Update(0.5)
+
+	// Installed after the update, which would see there's no stream behind it and stop it
+	track := &MusicTrack{Volume: 1, Group: AudioGroupMusic, playing: true}
+	as.music["theme"] = track
+
+	as.PauseAll()
+	if !as.IsPaused() || !playing.IsPaused || !track.paused {
+		t.Fatalf("PauseAll left audio running: paused %v, source %v, music %v", as.IsPaused(), playing.IsPaused, track.paused)
+	}
+	before := playing.CurrentTime
+	as.Update(1)
+	if playing.CurrentTime != before {
+		t.Errorf("source advanced from %v to %v while paused", before, playing.CurrentTime)
+	}
+
+	as.ResumeAll()
+	if as.IsPaused() || playing.IsPaused || track.paused {
+		t.Errorf("ResumeAll left audio paused: paused %v, source %v, music %v", as.IsPaused(), playing.IsPaused, track.paused)
+	}
+	if !held.IsPaused {
+		t.Error("ResumeAll resumed a source that was paused on its own")
+	}
+	as.Update(0.5)
+	if playing.CurrentTime <= before {
+		t.Errorf("source didn't advance after resuming: %v", playing.CurrentTime)
+	}
+}
+
+func TestFadeCurveShapes(t *testing.T) {
+	tests := []struct {
+		curve FadeCurve
+		want  [3]float32 // Volume at 25, 50 and 75% of a fade from 0 to 1
+	}{
+		{FadeLinear, [3]float32{0.25, 0.5, 0.75}},
+		{FadeExponential, [3]float32{0.032, 0.119, 0.356}},
+		{FadeLogarithmic, [3]float32{0.667, 0.831, 0.930}},
+		{FadeSCurve, [3]float32{0.156, 0.5, 0.844}},
+	}
+	for _, test := range tests {
+		fade := &Fade{From: 0, To: 1, Duration: 4, Curve: test.curve}
+		for i, want := range test.want {
+			got, done := fade.Advance(1)
+			if done || got-want > 0.001 || want-got > 0.001 {
+				t.Errorf("curve %d at %d%%: volume %v (done %v), want %v", test.curve, (i+1)*25, got, done, want)
+			}
+		}
+		if got, done := fade.Advance(1); !done || got != 1 {
+			t.Errorf("curve %d ended at %v (done %v), want 1", test.curve, got, done)
+		}
+	}
+}
+
+func TestFadeToMovesSourceVolume(t *testing.T) {
+	world := ecs.NewWorld()
+	as := newTestAudioSystem(world)
+	entityID := newAudioEntity(t, world, 0)
+	component, _ := world.GetComponent(entityID, components.AudioSourceComponentType)
+	source := component.(*components.AudioSourceComponent)
+	source.Volume = 1
+
+	as.FadeTo(entityID, 0, 1, FadeSCurve)
+	as.Update(0.5)
+	if source.Volume != 0.5 {
+		t.Errorf("volume %v halfway through an S-curve fade to silence, want 0.5", source.Volume)
+	}
+	as.Update(0.5)
+	if source.Volume != 0 {
+		t.Errorf("volume %v after the fade, want 0", source.Volume)
+	}
+}
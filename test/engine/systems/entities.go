@@ -0,0 +1,24 @@
+// Entity lookup on top of the ecs.World query API
+package systems
+
+import (
+	"gameengine/core"
+	"gameengine/ecs"
+)
+
+// The World creates and queries entities. Components go on through the entity CreateEntity
+// returns, or World.AddComponent once the entity exists. It has no way to destroy an entity,
+// remove a component or disable an entity, so nothing here pretends to: systems reuse
+// entities they're done with, and loaders check everything before they create anything.
+
+// FindEntity returns the entity holding component, which must be stored under
+// componentType. CreateEntity doesn't hand back an ID, so this is how a caller finds the
+// entity it just built.
+func FindEntity(world *ecs.World, componentType core.ComponentType, component interface{}) (core.EntityID, bool) {
+	for _, entityID := range world.GetEntitiesWithComponent(componentType) {
+		if stored, ok := world.GetComponent(entityID, componentType); ok && stored == component {
+			return entityID, true
+		}
+	}
+	return 0, false
+}
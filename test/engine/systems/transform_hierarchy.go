@@ -0,0 +1,159 @@
+// Parent/child links between entity transforms
+package systems
+
+import (
+	"fmt"
+
+	"gameengine/components"
+	"gameengine/core"
+	"gameengine/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TransformHierarchy links entities into a tree. A child's TransformComponent is local to its
+// parent; WorldMatrix and GetWorldPosition compose the chain up to the root.
+type TransformHierarchy struct {
+	world    *ecs.World
+	parents  map[core.EntityID]core.EntityID
+	children map[core.EntityID][]core.EntityID
+}
+
+// One hierarchy per world, so every system and the editor see the same links
+var transformHierarchies = make(map[*ecs.World]*TransformHierarchy)
+
+// TransformHierarchyOf returns the transform hierarchy of world, creating it on first use
+func TransformHierarchyOf(world *ecs.World) *TransformHierarchy {
+	if hierarchy, ok := transformHierarchies[world]; ok {
+		return hierarchy
+	}
+	hierarchy := &TransformHierarchy{
+		world:    world,
+		parents:  make(map[core.EntityID]core.EntityID),
+		children: make(map[core.EntityID][]core.EntityID),
+	}
+	transformHierarchies[world] = hierarchy
+	return hierarchy
+}
+
+// ReleaseTransformHierarchy drops the hierarchy of a world that's no longer used
+func ReleaseTransformHierarchy(world *ecs.World) {
+	delete(transformHierarchies, world)
+}
+
+// SetParent makes parent the parent of child, or detaches child when parent is 0. It refuses
+// links that would make an entity its own ancestor.
+func (h *TransformHierarchy) SetParent(child, parent core.EntityID) error {
+	if child == 0 {
+		return fmt.Errorf("cannot parent entity 0")
+	}
+	for ancestor := parent; ancestor != 0; ancestor = h.parents[ancestor] {
+		if ancestor == child {
+			return fmt.Errorf("entity %d cannot be parented to its own descendant %d", child, parent)
+		}
+	}
+
+	if old, ok := h.parents[child]; ok {
+		h.removeChild(old, child)
+		delete(h.parents, child)
+	}
+	if parent != 0 {
+		h.parents[child] = parent
+		h.children[parent] = append(h.children[parent], child)
+	}
+	return nil
+}
+
+// removeChild takes child out of parent's child list
+func (h *TransformHierarchy) removeChild(parent, child core.EntityID) {
+	siblings := h.children[parent]
+	for i, sibling := range siblings {
+		if sibling == child {
+			siblings = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+	if len(siblings) == 0 {
+		delete(h.children, parent)
+	} else {
+		h.children[parent] = siblings
+	}
+}
+
+// RemoveEntity detaches an entity from its parent and makes its children roots
+func (h *TransformHierarchy) RemoveEntity(entityID core.EntityID) {
+	for _, child := range h.children[entityID] {
+		delete(h.parents, child)
+	}
+	delete(h.children, entityID)
+	if parent, ok := h.parents[entityID]; ok {
+		h.removeChild(parent, entityID)
+		delete(h.parents, entityID)
+	}
+}
+
+// GetParent returns an entity's parent, or 0 for a root
+func (h *TransformHierarchy) GetParent(entityID core.EntityID) core.EntityID {
+	return h.parents[entityID]
+}
+
+// GetChildren returns an entity's children in the order they were attached
+func (h *TransformHierarchy) GetChildren(entityID core.EntityID) []core.EntityID {
+	return h.children[entityID]
+}
+
+// Depth returns how many ancestors an entity has
+func (h *TransformHierarchy) Depth(entityID core.EntityID) int {
+	depth := 0
+	for parent := h.parents[entityID]; parent != 0; parent = h.parents[parent] {
+		depth++
+	}
+	return depth
+}
+
+// LocalMatrix builds a transform's matrix: scale, then rotation about X, Y and Z in degrees,
+// then translation. Rotations are built with MatrixRotate, the same as DrawModelEx, so a
+// positive yaw turns forward (+Z) toward +X as forwardFromRotation expects.
+func LocalMatrix(transform *components.TransformComponent) rl.Matrix {
+	scale := rl.MatrixScale(transform.Scale.X, transform.Scale.Y, transform.Scale.Z)
+	rotation := rl.MatrixMultiply(
+		rl.MatrixMultiply(
+			rl.MatrixRotate(rl.Vector3{X: 1}, transform.Rotation.X*rl.Deg2rad),
+			rl.MatrixRotate(rl.Vector3{Y: 1}, transform.Rotation.Y*rl.Deg2rad),
+		),
+		rl.MatrixRotate(rl.Vector3{Z: 1}, transform.Rotation.Z*rl.Deg2rad),
+	)
+	translation := rl.MatrixTranslate(transform.Position.X, transform.Position.Y, transform.Position.Z)
+	return rl.MatrixMultiply(rl.MatrixMultiply(scale, rotation), translation)
+}
+
+// WorldMatrix composes an entity's transform with those of its ancestors. Ancestors without
+// a transform, such as destroyed ones, end the chain.
+func (h *TransformHierarchy) WorldMatrix(entityID core.EntityID) rl.Matrix {
+	matrix := rl.MatrixIdentity()
+	for current := entityID; current != 0; current = h.parents[current] {
+		transformComp, exists := h.world.GetComponent(current, components.TransformComponentType)
+		if !exists {
+			break
+		}
+		transform, ok := transformComp.(*components.TransformComponent)
+		if !ok {
+			break
+		}
+		matrix = rl.MatrixMultiply(matrix, LocalMatrix(transform))
+	}
+	return matrix
+}
+
+// GetWorldPosition returns where an entity is in the world
+func (h *TransformHierarchy) GetWorldPosition(entityID core.EntityID) rl.Vector3 {
+	if _, ok := h.parents[entityID]; !ok {
+		// Roots are the common case; skip the matrix work
+		if transformComp, exists := h.world.GetComponent(entityID, components.TransformComponentType); exists {
+			if transform, ok := transformComp.(*components.TransformComponent); ok {
+				return transform.Position
+			}
+		}
+	}
+	return rl.Vector3Transform(rl.Vector3{}, h.WorldMatrix(entityID))
+}